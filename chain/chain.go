@@ -17,6 +17,7 @@ import (
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/trie/cache"
+	"github.com/icon-project/goloop/common/txindex"
 	"github.com/icon-project/goloop/common/txlocator"
 	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
@@ -86,6 +87,7 @@ type singleChain struct {
 
 	dbLock   sync.RWMutex
 	database db.Database
+	backend  db.Database
 	vld      module.CommitVoteSetDecoder
 	pd       module.PatchDecoder
 	sm       module.ServiceManager
@@ -110,6 +112,8 @@ type singleChain struct {
 	mtx        sync.RWMutex
 	task       chainTask
 	termWaiter *sync.Cond
+	hotBackup  *taskBackup
+	txIndexer  *txindex.Indexer
 
 	// monitor
 	metricCtx context.Context
@@ -121,6 +125,8 @@ const (
 	DefaultContractDir = "contract"
 	DefaultCacheDir    = "cache"
 	DefaultTmpDBDir    = "tmp"
+
+	ChainGenesisZipFileName = "genesis.zip"
 )
 
 func (c *singleChain) Database() db.Database {
@@ -131,6 +137,10 @@ func (c *singleChain) Wallet() module.Wallet {
 	return c.wallet
 }
 
+func (c *singleChain) SlashingProtection() *consensus.SlashingProtection {
+	return c.cfg.SlashingProtection
+}
+
 func (c *singleChain) NID() int {
 	return c.cfg.NID
 }
@@ -385,19 +395,66 @@ func (c *singleChain) _setStartingTask(task chainTask) error {
 }
 
 func (c *singleChain) openDatabase(dbDir, dbType string) (db.Database, error) {
-	if dbType != "mapdb" {
+	database, _, err := c.openDatabaseWithBackend(dbDir, dbType)
+	return database, err
+}
+
+// openDatabaseWithBackend opens the database, and returns it with the
+// backend database, which the modifiers are not applied to.
+func (c *singleChain) openDatabaseWithBackend(dbDir, dbType string) (db.Database, db.Database, error) {
+	backend, modifiers := db.SplitType(dbType)
+	if backend != db.MapDBBackend {
 		c.logger.Infof("prepare a directory %s for database", dbDir)
 		if err := os.MkdirAll(dbDir, 0700); err != nil {
-			return nil, errors.Wrapf(err, "fail to make directory dir=%s", dbDir)
+			return nil, nil, errors.Wrapf(err, "fail to make directory dir=%s", dbDir)
 		}
 	}
 	DBName := strconv.FormatInt(int64(c.cfg.NID), 16)
-	if cdb, err := db.Open(dbDir, dbType, DBName); err != nil {
+	cdb, err := db.Open(dbDir, string(backend), DBName)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err,
+			"fail to open database dir=%s type=%s name=%s", dbDir, c.cfg.DBType, DBName)
+	}
+	if len(modifiers) > 0 {
+		secret, err := c.cfg.DBSecret()
+		if err != nil {
+			_ = cdb.Close()
+			return nil, nil, err
+		}
+		mdb, err := db.WithModifiers(cdb, modifiers, secret)
+		if err != nil {
+			_ = cdb.Close()
+			return nil, nil, err
+		}
+		return mdb, cdb, nil
+	}
+	return cdb, cdb, nil
+}
+
+// openDatabaseReadOnly opens the existing database only for reading with
+// the modifiers of the type.
+func (c *singleChain) openDatabaseReadOnly(dbDir, dbType string) (db.Database, error) {
+	backend, modifiers := db.SplitType(dbType)
+	DBName := strconv.FormatInt(int64(c.cfg.NID), 16)
+	cdb, err := db.OpenReadOnly(dbDir, string(backend), DBName)
+	if err != nil {
 		return nil, errors.Wrapf(err,
 			"fail to open database dir=%s type=%s name=%s", dbDir, c.cfg.DBType, DBName)
-	} else {
-		return cdb, nil
 	}
+	if len(modifiers) > 0 {
+		secret, err := c.cfg.DBSecret()
+		if err != nil {
+			_ = cdb.Close()
+			return nil, err
+		}
+		mdb, err := db.WithModifiers(cdb, modifiers, secret)
+		if err != nil {
+			_ = cdb.Close()
+			return nil, err
+		}
+		return mdb, nil
+	}
+	return cdb, nil
 }
 
 func (c *singleChain) ensureDatabase() {
@@ -416,10 +473,13 @@ func (c *singleChain) ensureDatabase() {
 
 func (c *singleChain) prepareDatabase(chainDir string) error {
 	DBDir := path.Join(chainDir, DefaultDBDir)
-	cdb, err := c.openDatabase(DBDir, c.cfg.DBType)
+	cdb, backend, err := c.openDatabaseWithBackend(DBDir, c.cfg.DBType)
 	if err != nil {
 		return err
 	}
+	if c.cfg.DBMetrics {
+		cdb = db.WithMonitor(cdb, metric.NewDatabaseMetric(metric.GetMetricContextByCID(c.CID())))
+	}
 	if len(c.cfg.NodeCache) == 0 {
 		c.cfg.NodeCache = NodeCacheDefault
 	}
@@ -436,6 +496,7 @@ func (c *singleChain) prepareDatabase(chainDir string) error {
 		return errors.Wrap(err, "FailToAttachAPIInfoCache")
 	}
 	c.database = cdb
+	c.backend = backend
 	return nil
 }
 
@@ -445,6 +506,7 @@ func (c *singleChain) releaseDatabase() {
 	if c.database != nil {
 		c.database.Close()
 		c.database = nil
+		c.backend = nil
 	}
 }
 
@@ -509,6 +571,7 @@ func (c *singleChain) prepareManagers() error {
 }
 
 func (c *singleChain) releaseManagers() {
+	c.stopTxIndexer()
 	if c.cs != nil {
 		c.cs.Term()
 		c.cs = nil
@@ -623,8 +686,8 @@ func (c *singleChain) Prune(gsfile string, dbtype string, height int64) error {
 	return c._runTask(task, false)
 }
 
-func (c *singleChain) Backup(file string, extra []string) error {
-	task := newTaskBackup(c, file, extra)
+func (c *singleChain) Backup(file, base string, extra []string) error {
+	task := newTaskBackup(c, file, base, extra)
 	return c._runTask(task, false)
 }
 
@@ -662,6 +725,9 @@ func (c *singleChain) _handleTerminateInLock() {
 }
 
 func (c *singleChain) _terminate() {
+	if c.hotBackup != nil {
+		c.hotBackup.Stop()
+	}
 	c.releaseDatabase()
 	c.plt.Term()
 }
@@ -693,8 +759,7 @@ func (c *singleChain) Verify() error {
 func (c *singleChain) Reset(gs string, height int64, blockHash []byte) error {
 	if len(gs) == 0 {
 		chainDir := c.cfg.AbsBaseDir()
-		const chainGenesisZipFileName = "genesis.zip"
-		gs = path.Join(chainDir, chainGenesisZipFileName)
+		gs = path.Join(chainDir, ChainGenesisZipFileName)
 	}
 	task := newTaskReset(c, gs, height, blockHash)
 	return c._runTask(task, false)
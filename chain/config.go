@@ -10,6 +10,7 @@ import (
 
 	"github.com/icon-project/goloop/common/crypto"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 )
 
@@ -35,6 +36,11 @@ type Config struct {
 	NID    int    `json:"nid"`
 	DBType string `json:"db_type"`
 
+	// DBKeyStore and DBKeySecret are used for database type with
+	// encryption modifiers.
+	DBKeyStore  string `json:"db_key_store,omitempty"`
+	DBKeySecret string `json:"db_key_secret,omitempty"`
+
 	Platform string `json:"platform,omitempty"`
 
 	// static
@@ -49,6 +55,8 @@ type Config struct {
 	ChildrenLimit    *int   `json:"children_limit,omitempty"`
 	NephewsLimit     *int   `json:"nephews_limit,omitempty"`
 	ValidateTxOnSend bool   `json:"validate_tx_on_send,omitempty"`
+	DBMetrics        bool   `json:"db_metrics,omitempty"`
+	TxIndex          bool   `json:"tx_index,omitempty"`
 
 	// runtime
 	Channel        string `json:"channel"`
@@ -65,6 +73,8 @@ type Config struct {
 	FilePath string `json:"-"` // absolute path
 
 	NIDForP2P bool `json:"-"`
+
+	SlashingProtection *consensus.SlashingProtection `json:"-"`
 }
 
 func (c *Config) ResolveAbsolute(targetPath string) string {
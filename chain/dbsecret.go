@@ -0,0 +1,55 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"os"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+// LoadDBSecret returns the secret for database encryption stored in the
+// keystore file. The password of the keystore is read from the secret file.
+func LoadDBSecret(keyStore, keySecret string) ([]byte, error) {
+	if keyStore == "" {
+		return nil, errors.IllegalArgumentError.New("NoKeyStoreForDBEncryption")
+	}
+	ks, err := os.ReadFile(keyStore)
+	if err != nil {
+		return nil, errors.Wrapf(err, "FailToReadKeyStore(file=%s)", keyStore)
+	}
+	var pw []byte
+	if keySecret != "" {
+		if pw, err = os.ReadFile(keySecret); err != nil {
+			return nil, errors.Wrapf(err, "FailToReadKeySecret(file=%s)", keySecret)
+		}
+	}
+	sk, err := wallet.DecryptKeyStore(ks, pw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "FailToDecryptKeyStore(file=%s)", keyStore)
+	}
+	return sk.Bytes(), nil
+}
+
+func (c *Config) DBSecret() ([]byte, error) {
+	var keySecret string
+	if c.DBKeySecret != "" {
+		keySecret = c.ResolveAbsolute(c.DBKeySecret)
+	}
+	return LoadDBSecret(c.ResolveAbsolute(c.DBKeyStore), keySecret)
+}
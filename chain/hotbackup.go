@@ -0,0 +1,246 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"os"
+	"path"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	// TemporalHotBackupDir is the prefix of the directory in the chain
+	// directory, which keeps the checkpoint of the database until it's
+	// stored in the backup.
+	TemporalHotBackupDir = ".hotbackup"
+)
+
+// HotBackup stores chain data into the file while the chain is running.
+// It makes a checkpoint of the database, then stores the checkpoint with
+// other files in background.
+// Result of the backup is available with InspectBackup.
+func (c *singleChain) HotBackup(file, base string, extra []string) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.state != Started {
+		return errors.InvalidStateError.Errorf(
+			"InvalidState(state=%s)", c.state.String())
+	}
+	if t := c.hotBackup; t != nil {
+		if _, done := t.result.GetValue(); !done {
+			return errors.InvalidStateError.Errorf(
+				"AlreadyInBackup(file=%s)", path.Base(t.file))
+		}
+	}
+
+	if err := c.checkHotBackup(); err != nil {
+		return err
+	}
+
+	t := newTaskBackup(c, file, base, extra).(*taskBackup)
+	c.hotBackup = t
+	c.mtx.Unlock()
+
+	// making a checkpoint may take long for some backends.
+	err := t.startHot()
+
+	c.mtx.Lock()
+	if err != nil && c.hotBackup == t {
+		c.hotBackup = nil
+	}
+	return err
+}
+
+// checkHotBackup checks whether the database of the chain supports hot
+// backup. goleveldb copies all entries for the checkpoint, so it takes
+// longer than pebble and rocksdb, and the files of the copy are never
+// shared with the base backup.
+func (c *singleChain) checkHotBackup() error {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.backend == nil {
+		return errors.InvalidStateError.New("DatabaseNotReady")
+	}
+	if _, ok := c.backend.(db.Checkpointer); !ok {
+		return errors.UnsupportedError.Errorf(
+			"HotBackupNotSupported(db_type=%s,supported=goleveldb|pebble|rocksdb)",
+			c.cfg.DBType)
+	}
+	return nil
+}
+
+// checkpoint makes a checkpoint of the database under dir. It returns
+// the height of the last block in the checkpoint.
+func (c *singleChain) checkpoint(dir string) (int64, error) {
+	c.dbLock.RLock()
+	defer c.dbLock.RUnlock()
+
+	if c.backend == nil {
+		return 0, errors.InvalidStateError.New("DatabaseNotReady")
+	}
+	dbDir := path.Join(dir, DefaultDBDir)
+	name := strconv.FormatInt(int64(c.cfg.NID), 16)
+	if err := db.Checkpoint(c.backend, dbDir, name); err != nil {
+		return 0, err
+	}
+
+	cdb, err := c.openDatabaseReadOnly(dbDir, c.cfg.DBType)
+	if err != nil {
+		return 0, err
+	}
+	defer cdb.Close()
+	return block.GetLastHeightOf(cdb), nil
+}
+
+func (t *taskBackup) startHot() (ret error) {
+	chainDir := t.chain.cfg.AbsBaseDir()
+	tmpDir, err := os.MkdirTemp(chainDir, TemporalHotBackupDir)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal directory")
+	}
+	defer func() {
+		if ret != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	height, err := t.chain.checkpoint(tmpDir)
+	if err != nil {
+		return err
+	}
+	info := &BackupInfo{
+		NID:     common.HexInt32{Value: int32(t.chain.NID())},
+		CID:     common.HexInt32{Value: int32(t.chain.CID())},
+		Channel: t.chain.Channel(),
+		Height:  height,
+		Codec:   codec.BC.Name(),
+	}
+	if t.base != "" {
+		if err := t._loadBase(info); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(path.Dir(t.file), TemporalBackupFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to make temporal file")
+	}
+	defer func() {
+		if ret != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if err := tmp.Chmod(0644); err != nil {
+		return err
+	}
+	t.fd = tmp
+	t.zw = zip.NewWriter(tmp)
+	if err := writeBackupInfo(t.zw, info); err != nil {
+		return err
+	}
+
+	go func() {
+		defer os.RemoveAll(tmpDir)
+		err := t._hotBackup(tmpDir)
+		if err == nil {
+			err = os.Rename(tmp.Name(), t.file)
+		}
+		if err != nil {
+			os.Remove(tmp.Name())
+			t.chain.logger.Warnf("Hot backup failed file=%s err=%+v", t.file, err)
+		} else {
+			t.chain.logger.Infof("Hot backup done file=%s height=%d", t.file, height)
+		}
+		t.result.SetValue(err)
+	}()
+	return nil
+}
+
+// _hotBackup stores the checkpoint in tmpDir, then other files in the
+// chain directory. Contracts and WAL are stored after the checkpoint, so
+// all contracts referred by the checkpoint and votes for the last block
+// are included. The WAL may have a partially written record at the end,
+// but it's repaired on reading.
+func (t *taskBackup) _hotBackup(tmpDir string) error {
+	defer t.fd.Close()
+	defer t.zw.Close()
+
+	chainDir := t.chain.cfg.AbsBaseDir()
+	names := append([]string{DefaultContractDir, DefaultWALDir}, t.extra...)
+
+	total, err := countFiles(path.Join(tmpDir, DefaultDBDir))
+	if err != nil {
+		return err
+	}
+	if cnt, err := t._countFiles(chainDir, names); err != nil {
+		return err
+	} else {
+		atomic.StoreInt32(&t.total, int32(total+cnt))
+	}
+
+	if err := zipWrite(t.zw, tmpDir, DefaultDBDir, t.OnFile, t.OnWrite); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := zipWrite(t.zw, chainDir, name, t.OnFile, t.OnWrite); err != nil {
+			return err
+		}
+	}
+	return writeBackupManifest(t.zw, &t.manifest)
+}
+
+// InspectBackup returns the state of the last hot backup of the chain.
+func InspectBackup(c module.Chain, informal bool) map[string]interface{} {
+	sc, ok := c.(*singleChain)
+	if !ok {
+		return nil
+	}
+	sc.mtx.RLock()
+	t := sc.hotBackup
+	sc.mtx.RUnlock()
+	if t == nil {
+		return nil
+	}
+
+	m := map[string]interface{}{
+		"file": path.Base(t.file),
+	}
+	if t.base != "" {
+		m["base"] = path.Base(t.base)
+	}
+	if err, done := t.result.GetValue(); !done {
+		m["state"] = t.DetailOf(Started)
+	} else if err != nil {
+		m["state"] = t.DetailOf(Failed)
+		m["error"] = err.Error()
+	} else {
+		m["state"] = t.DetailOf(Finished)
+	}
+	return m
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package snapshot implements the portable state snapshot file.
+//
+// A snapshot file is composed of the following parts.
+//
+//	magic   : "GLSS"
+//	header  : uint32 length, header (codec.BC encoded Header)
+//	chunk*  : uint32 length, SHA3-256 of data, data (gzip compressed
+//	          codec.BC encoded list of entries)
+//	trailer : uint32 zero, uint64 number of entries, SHA3-256 digest
+//
+// The digest of the trailer is SHA3-256 of the header followed by hashes
+// of all chunks, so any modification, truncation or reordering of chunks
+// is detected. Entries in the bucket with the hasher are also verified
+// against their keys on reading.
+package snapshot
+
+import (
+	"github.com/icon-project/goloop/common/db"
+)
+
+const (
+	Magic   = "GLSS"
+	Version = 1
+
+	// DefaultChunkSize is the default size of uncompressed entries in a chunk.
+	DefaultChunkSize = 4 * 1024 * 1024
+
+	// MaxChunkSize limits the size of a chunk to read.
+	MaxChunkSize = 64 * 1024 * 1024
+
+	// MaxUncompressedChunkSize limits the size of decompressed entries of
+	// a chunk, so a crafted chunk can't exhaust memory on reading.
+	MaxUncompressedChunkSize = 256 * 1024 * 1024
+
+	// MaxHeaderSize limits the size of the header to read.
+	MaxHeaderSize = 256 * 1024 * 1024
+)
+
+// Header describes the snapshot. Genesis is the pruned genesis storage
+// of the block at Height, which is used as genesis of the restored chain.
+type Header struct {
+	Version int
+	CID     int
+	NID     int
+	Height  int64
+	BlockID []byte
+	Genesis []byte
+}
+
+// Entry is a key-value pair in a bucket of the database.
+type Entry struct {
+	Bucket db.BucketID
+	Key    []byte
+	Value  []byte
+}
+
+type entryFormat struct {
+	Bucket []byte
+	Key    []byte
+	Value  []byte
+}
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Reader reads entries from the snapshot file verifying hashes of chunks
+// and entries. Next returns io.EOF only after the trailer is verified.
+type Reader struct {
+	r      io.Reader
+	header Header
+	digest hash.Hash
+
+	entries []entryFormat
+	index   int
+	count   uint64
+	done    bool
+
+	// maxUncompressed limits the size of decompressed entries of a chunk.
+	maxUncompressed int64
+}
+
+func readFull(r io.Reader, buf []byte) error {
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return errors.CriticalFormatError.Wrap(err, "UnexpectedEOF")
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Reader) readLength(max int) (int, error) {
+	var lb [4]byte
+	if err := readFull(r.r, lb[:]); err != nil {
+		return 0, err
+	}
+	length := int(binary.BigEndian.Uint32(lb[:]))
+	if length > max {
+		return 0, errors.CriticalFormatError.Errorf(
+			"TooLargeBlock(size=%d,max=%d)", length, max)
+	}
+	return length, nil
+}
+
+// Header returns the header of the snapshot.
+func (r *Reader) Header() *Header {
+	return &r.header
+}
+
+// Count returns the number of entries read so far.
+func (r *Reader) Count() uint64 {
+	return r.count
+}
+
+func (r *Reader) readTrailer() error {
+	var cb [8]byte
+	if err := readFull(r.r, cb[:]); err != nil {
+		return err
+	}
+	if count := binary.BigEndian.Uint64(cb[:]); count != r.count {
+		return errors.CriticalFormatError.Errorf(
+			"InvalidEntryCount(exp=%d,real=%d)", count, r.count)
+	}
+	digest := make([]byte, crypto.HashLen)
+	if err := readFull(r.r, digest); err != nil {
+		return err
+	}
+	if real := r.digest.Sum(nil); !bytes.Equal(digest, real) {
+		return errors.CriticalHashError.Errorf(
+			"InvalidDigest(exp=%#x,real=%#x)", digest, real)
+	}
+	return nil
+}
+
+func (r *Reader) readChunk() error {
+	length, err := r.readLength(MaxChunkSize)
+	if err != nil {
+		return err
+	}
+	if length == 0 {
+		if err := r.readTrailer(); err != nil {
+			return err
+		}
+		r.done = true
+		return io.EOF
+	}
+	h := make([]byte, crypto.HashLen)
+	if err := readFull(r.r, h); err != nil {
+		return err
+	}
+	data := make([]byte, length)
+	if err := readFull(r.r, data); err != nil {
+		return err
+	}
+	if real := crypto.SHA3Sum256(data); !bytes.Equal(h, real) {
+		return errors.CriticalHashError.Errorf(
+			"InvalidChunkHash(exp=%#x,real=%#x)", h, real)
+	}
+	r.digest.Write(h)
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return errors.CriticalFormatError.Wrap(err, "InvalidChunk")
+	}
+	lr := &io.LimitedReader{R: zr, N: r.maxUncompressed}
+	var entries []entryFormat
+	if err := codec.BC.Unmarshal(lr, &entries); err != nil {
+		if lr.N == 0 {
+			return errors.CriticalFormatError.Errorf(
+				"TooLargeChunk(max=%d)", r.maxUncompressed)
+		}
+		return errors.CriticalFormatError.Wrap(err, "InvalidChunk")
+	}
+	r.entries = entries
+	r.index = 0
+	return nil
+}
+
+// Next returns the next entry. It returns io.EOF at the end of the snapshot.
+func (r *Reader) Next() (*Entry, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	for r.index >= len(r.entries) {
+		if err := r.readChunk(); err != nil {
+			return nil, err
+		}
+	}
+	ef := &r.entries[r.index]
+	r.index += 1
+	e := &Entry{
+		Bucket: db.BucketID(ef.Bucket),
+		Key:    ef.Key,
+		Value:  ef.Value,
+	}
+	if hasher := e.Bucket.Hasher(); hasher != nil {
+		if real := hasher.Hash(e.Value); !bytes.Equal(e.Key, real) {
+			return nil, errors.CriticalHashError.Errorf(
+				"InvalidEntryHash(bucket=%q,key=%#x,real=%#x)", e.Bucket, e.Key, real)
+		}
+	}
+	r.count += 1
+	return e, nil
+}
+
+// NewReader reads magic and the header from r, then returns Reader for
+// reading entries.
+func NewReader(r io.Reader) (*Reader, error) {
+	magic := make([]byte, len(Magic))
+	if err := readFull(r, magic); err != nil {
+		return nil, err
+	}
+	if string(magic) != Magic {
+		return nil, errors.CriticalFormatError.Errorf("InvalidMagic(magic=%q)", magic)
+	}
+	sr := &Reader{
+		r:               r,
+		digest:          sha3.New256(),
+		maxUncompressed: MaxUncompressedChunkSize,
+	}
+	length, err := sr.readLength(MaxHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	hb := make([]byte, length)
+	if err := readFull(r, hb); err != nil {
+		return nil, err
+	}
+	if _, err := codec.BC.UnmarshalFromBytes(hb, &sr.header); err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidHeader")
+	}
+	if sr.header.Version != Version {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedVersion(version=%d)", sr.header.Version)
+	}
+	sr.digest.Write(hb)
+	return sr, nil
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func writeSnapshot(t *testing.T, entries []Entry, chunkSize int) []byte {
+	buf := bytes.NewBuffer(nil)
+	w, err := NewWriter(buf, &Header{
+		CID:     0x1234,
+		NID:     0x3,
+		Height:  100,
+		BlockID: crypto.SHA3Sum256([]byte("block")),
+		Genesis: []byte("genesis"),
+	}, chunkSize)
+	assert.NoError(t, err)
+	for _, e := range entries {
+		assert.NoError(t, w.Add(e.Bucket, e.Key, e.Value))
+	}
+	assert.NoError(t, w.Close())
+	assert.EqualValues(t, len(entries), w.Count())
+	return buf.Bytes()
+}
+
+func readSnapshot(bs []byte) ([]Entry, error) {
+	r, err := NewReader(bytes.NewReader(bs))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+}
+
+func testEntries(n int) []Entry {
+	var entries []Entry
+	for i := 0; i < n; i++ {
+		value := []byte(fmt.Sprintf("value%d", i))
+		entries = append(entries, Entry{
+			Bucket: db.BytesByHash,
+			Key:    crypto.SHA3Sum256(value),
+			Value:  value,
+		})
+		entries = append(entries, Entry{
+			Bucket: db.ChainProperty,
+			Key:    []byte(fmt.Sprintf("key%d", i)),
+			Value:  value,
+		})
+	}
+	return entries
+}
+
+func TestSnapshot_RoundTrip(t *testing.T) {
+	entries := testEntries(100)
+	bs := writeSnapshot(t, entries, 128)
+
+	r, err := NewReader(bytes.NewReader(bs))
+	assert.NoError(t, err)
+	h := r.Header()
+	assert.Equal(t, Version, h.Version)
+	assert.Equal(t, 0x1234, h.CID)
+	assert.EqualValues(t, 100, h.Height)
+	assert.Equal(t, []byte("genesis"), h.Genesis)
+
+	read, err := readSnapshot(bs)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, read)
+
+	empty := writeSnapshot(t, nil, 0)
+	read, err = readSnapshot(empty)
+	assert.NoError(t, err)
+	assert.Empty(t, read)
+}
+
+func TestSnapshot_Corruption(t *testing.T) {
+	bs := writeSnapshot(t, testEntries(100), 128)
+
+	// every modified byte should be detected
+	for _, idx := range []int{0, 10, len(bs) / 2, len(bs) - 40, len(bs) - 1} {
+		corrupted := bytes.Clone(bs)
+		corrupted[idx] ^= 0xff
+		_, err := readSnapshot(corrupted)
+		assert.Error(t, err, "index=%d", idx)
+	}
+
+	// truncated
+	_, err := readSnapshot(bs[:len(bs)-33])
+	assert.True(t, errors.CriticalFormatError.Equals(err))
+
+	// invalid entry hash
+	entries := testEntries(1)
+	entries[0].Value = []byte("other")
+	_, err = readSnapshot(writeSnapshot(t, entries, 0))
+	assert.True(t, errors.CriticalHashError.Equals(err))
+}
+
+func TestSnapshot_TooLargeUncompressedChunk(t *testing.T) {
+	entries := testEntries(100)
+	bs := writeSnapshot(t, entries, DefaultChunkSize)
+
+	r, err := NewReader(bytes.NewReader(bs))
+	assert.NoError(t, err)
+	r.maxUncompressed = 256
+	_, err = r.Next()
+	assert.True(t, errors.CriticalFormatError.Equals(err))
+	assert.Contains(t, err.Error(), "TooLargeChunk")
+
+	// the chunk is fine with the default limit
+	read, err := readSnapshot(bs)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, read)
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Writer writes entries into the snapshot file. Entries are buffered
+// until the size reaches the chunk size, and written as a chunk.
+type Writer struct {
+	w         io.Writer
+	chunkSize int
+	digest    hash.Hash
+
+	entries []entryFormat
+	size    int
+	count   uint64
+	closed  bool
+}
+
+func (w *Writer) writeBlock(data []byte) error {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	if _, err := w.w.Write(length[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(data)
+	return err
+}
+
+// Add appends an entry of the bucket to the snapshot.
+func (w *Writer) Add(id db.BucketID, key, value []byte) error {
+	if w.closed {
+		return errors.InvalidStateError.New("AlreadyClosed")
+	}
+	w.entries = append(w.entries, entryFormat{
+		Bucket: []byte(id),
+		Key:    key,
+		Value:  value,
+	})
+	w.size += len(id) + len(key) + len(value)
+	w.count += 1
+	if w.size >= w.chunkSize {
+		return w.flushChunk()
+	}
+	return nil
+}
+
+func (w *Writer) flushChunk() error {
+	if len(w.entries) == 0 {
+		return nil
+	}
+	buf := bytes.NewBuffer(nil)
+	zw := gzip.NewWriter(buf)
+	cw := &countingWriter{w: zw}
+	if err := codec.BC.Marshal(cw, w.entries); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if cw.n > MaxUncompressedChunkSize {
+		return errors.IllegalArgumentError.Errorf(
+			"TooLargeChunk(uncompressed=%d,max=%d)", cw.n, MaxUncompressedChunkSize)
+	}
+	data := buf.Bytes()
+	if len(data) > MaxChunkSize {
+		return errors.IllegalArgumentError.Errorf(
+			"TooLargeChunk(size=%d,max=%d)", len(data), MaxChunkSize)
+	}
+	h := crypto.SHA3Sum256(data)
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	if _, err := w.w.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(h); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	w.digest.Write(h)
+	w.entries = w.entries[:0]
+	w.size = 0
+	return nil
+}
+
+// Count returns the number of entries added to the snapshot.
+func (w *Writer) Count() uint64 {
+	return w.count
+}
+
+// Close flushes remaining entries and writes the trailer. It doesn't close
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.flushChunk(); err != nil {
+		return err
+	}
+	w.closed = true
+	var trailer [12]byte
+	binary.BigEndian.PutUint64(trailer[4:], w.count)
+	if _, err := w.w.Write(trailer[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(w.digest.Sum(nil))
+	return err
+}
+
+// NewWriter writes magic and the header to w, then returns Writer for
+// writing entries. Version of the header is set to Version. chunkSize is
+// the size of uncompressed entries in a chunk, and DefaultChunkSize is used
+// if it's not positive.
+func NewWriter(w io.Writer, h *Header, chunkSize int) (*Writer, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	hdr := *h
+	hdr.Version = Version
+	hb, err := codec.BC.MarshalToBytes(&hdr)
+	if err != nil {
+		return nil, err
+	}
+	sw := &Writer{
+		w:         w,
+		chunkSize: chunkSize,
+		digest:    sha3.New256(),
+	}
+	if _, err := w.Write([]byte(Magic)); err != nil {
+		return nil, err
+	}
+	if err := sw.writeBlock(hb); err != nil {
+		return nil, err
+	}
+	sw.digest.Write(hb)
+	return sw, nil
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
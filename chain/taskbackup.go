@@ -26,13 +26,20 @@ import (
 	"path"
 	"sort"
 	"sync/atomic"
+	"time"
 
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/codec"
 	"github.com/icon-project/goloop/common/errors"
 )
 
-const TemporalBackupFile = ".backup"
+const (
+	TemporalBackupFile = ".backup"
+
+	// BackupManifestFile is the name of the entry listing all files of the
+	// chain at the backup, including files stored in the base backups.
+	BackupManifestFile = ".manifest.json"
+)
 
 type BackupInfo struct {
 	NID     common.HexInt32 `json:"nid"`
@@ -40,6 +47,23 @@ type BackupInfo struct {
 	Channel string          `json:"channel"`
 	Height  int64           `json:"height"`
 	Codec   string          `json:"codec"`
+
+	// Base is the name of the backup which the incremental backup is
+	// based on. It's empty for the full backup.
+	Base string `json:"base,omitempty"`
+}
+
+type BackupFile struct {
+	Name    string `json:"name"`
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"modTime"`
+}
+
+// BackupManifest lists all files of the chain at the backup. Files which
+// are not changed since the base backup are listed, but not stored in
+// the incremental backup.
+type BackupManifest struct {
+	Files []BackupFile `json:"files"`
 }
 
 var backupStates = map[State]string{
@@ -52,6 +76,7 @@ var backupStates = map[State]string{
 type taskBackup struct {
 	chain   *singleChain
 	file    string
+	base    string
 	extra   []string
 	fd      io.WriteCloser
 	zw      *zip.Writer
@@ -59,9 +84,15 @@ type taskBackup struct {
 	total   int32
 	stop    int32
 	result  resultStore
+
+	baseFiles map[string]BackupFile
+	manifest  BackupManifest
 }
 
 func (t *taskBackup) String() string {
+	if t.base != "" {
+		return fmt.Sprintf("Backup(file=%s,base=%s)", path.Base(t.file), path.Base(t.base))
+	}
 	return fmt.Sprintf("Backup(file=%s)", path.Base(t.file))
 }
 
@@ -93,6 +124,18 @@ func (t *taskBackup) Start() (ret error) {
 		t.chain.releaseDatabase()
 		return nil
 	}
+	info := &BackupInfo{
+		NID:     common.HexInt32{Value: int32(t.chain.NID())},
+		CID:     common.HexInt32{Value: int32(t.chain.CID())},
+		Channel: t.chain.Channel(),
+		Height:  t.chain.lastBlockHeight(),
+		Codec:   codec.BC.Name(),
+	}
+	if t.base != "" {
+		if err := t._loadBase(info); err != nil {
+			return err
+		}
+	}
 	tmp, err := os.CreateTemp(path.Dir(t.file), TemporalBackupFile)
 	if err != nil {
 		return errors.Wrap(err, "Fail to make temporal file")
@@ -110,13 +153,7 @@ func (t *taskBackup) Start() (ret error) {
 	t.fd = tmp
 	t.zw = zip.NewWriter(tmp)
 
-	if err := writeBackupInfo(t.zw, &BackupInfo{
-		NID:     common.HexInt32{Value: int32(t.chain.NID())},
-		CID:     common.HexInt32{Value: int32(t.chain.CID())},
-		Channel: t.chain.Channel(),
-		Height:  t.chain.lastBlockHeight(),
-		Codec:   codec.BC.Name(),
-	}); err != nil {
+	if err := writeBackupInfo(t.zw, info); err != nil {
 		return err
 	}
 
@@ -135,7 +172,56 @@ func (t *taskBackup) Start() (ret error) {
 	return nil
 }
 
-func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
+func (t *taskBackup) _loadBase(info *BackupInfo) error {
+	zr, err := zip.OpenReader(t.base)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"InvalidBaseBackup(base=%s)", path.Base(t.base))
+	}
+	defer zr.Close()
+
+	base, err := ReadBackupInfo(&zr.Reader)
+	if err != nil {
+		return errors.IllegalArgumentError.Wrap(err, "InvalidBackupInfo")
+	}
+	if base.CID != info.CID || base.NID != info.NID ||
+		base.Channel != info.Channel || base.Codec != info.Codec {
+		return errors.IllegalArgumentError.Errorf(
+			"IncompatibleBaseBackup(cid=%s,nid=%s,channel=%s,codec=%s)",
+			base.CID, base.NID, base.Channel, base.Codec)
+	}
+	if base.Height > info.Height {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidBaseHeight(base=%d,current=%d)", base.Height, info.Height)
+	}
+	manifest, err := ReadBackupManifest(&zr.Reader)
+	if err != nil {
+		return err
+	}
+	t.baseFiles = make(map[string]BackupFile, len(manifest.Files))
+	for _, f := range manifest.Files {
+		t.baseFiles[f.Name] = f
+	}
+	info.Base = path.Base(t.base)
+	return nil
+}
+
+// OnFile records the file in the manifest, and returns whether the file
+// should be stored. Files not changed since the base backup are skipped.
+func (t *taskBackup) OnFile(n string, st fs.FileInfo) bool {
+	f := BackupFile{
+		Name:    n,
+		Size:    st.Size(),
+		ModTime: st.ModTime().UnixNano(),
+	}
+	t.manifest.Files = append(t.manifest.Files, f)
+	if bf, ok := t.baseFiles[n]; ok && bf == f {
+		return false
+	}
+	return true
+}
+
+func zipWrite(writer *zip.Writer, p, n string, filter func(string, fs.FileInfo) bool, on func(int64) error) error {
 	p2 := path.Join(p, n)
 	st, err := os.Stat(p2)
 	if errors.Is(err, fs.ErrNotExist) {
@@ -144,6 +230,9 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return errors.Wrap(err, "writeToZip: FAIL on os.State")
 	}
 	if st.Mode().IsRegular() {
+		if filter != nil && !filter(n, st) {
+			return on(0)
+		}
 		fd, err := os.Open(p2)
 		defer fd.Close()
 		if err != nil {
@@ -163,7 +252,9 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		if err != nil {
 			return errors.Wrapf(err, "writeToZip: fail to create entry %s", n)
 		}
-		if _, err := io.Copy(zf, fd); err != nil {
+		// the file may grow while it's stored on hot backup, so copy
+		// only the size recorded in the header and the manifest.
+		if _, err := io.CopyN(zf, fd, st.Size()); err != nil {
 			return errors.Wrap(err, "writeToZip: fail to copy")
 		}
 		if err := on(st.Size()); err != nil {
@@ -183,7 +274,7 @@ func zipWrite(writer *zip.Writer, p, n string, on func(int64) error) error {
 		return fis[i].Name() < fis[j].Name()
 	})
 	for _, fi := range fis {
-		if err := zipWrite(writer, p, path.Join(n, fi.Name()), on); err != nil {
+		if err := zipWrite(writer, p, path.Join(n, fi.Name()), filter, on); err != nil {
 			return err
 		}
 	}
@@ -252,20 +343,25 @@ func (t *taskBackup) _backup() error {
 		DefaultWALDir, DefaultDBDir, DefaultContractDir,
 	}, t.extra...)
 
-	chainDir := t.chain.cfg.AbsBaseDir()
+	return t._writeFiles(t.chain.cfg.AbsBaseDir(), names)
+}
+
+// _writeFiles writes the files under names in chainDir skipping files
+// not changed since the base backup, then writes the manifest.
+func (t *taskBackup) _writeFiles(chainDir string, names []string) error {
 	if cnt, err := t._countFiles(chainDir, names); err != nil {
 		return err
 	} else {
-		t.total = int32(cnt)
+		atomic.StoreInt32(&t.total, int32(cnt))
 	}
 
 	for _, name := range names {
-		if err := zipWrite(t.zw, chainDir, name, t.OnWrite); err != nil {
+		if err := zipWrite(t.zw, chainDir, name, t.OnFile, t.OnWrite); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return writeBackupManifest(t.zw, &t.manifest)
 }
 
 func (t *taskBackup) Stop() {
@@ -282,10 +378,11 @@ func (t *taskBackup) Wait() error {
 	return t.result.Wait()
 }
 
-func newTaskBackup(chain *singleChain, file string, extra []string) chainTask {
+func newTaskBackup(chain *singleChain, file, base string, extra []string) chainTask {
 	return &taskBackup{
 		chain: chain,
 		file:  file,
+		base:  base,
 		extra: extra,
 	}
 }
@@ -323,3 +420,50 @@ func ReadBackupInfo(zr *zip.Reader) (*BackupInfo, error) {
 	}
 	return info, nil
 }
+
+func writeBackupManifest(zw *zip.Writer, manifest *BackupManifest) error {
+	bs, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     BackupManifestFile,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bs)
+	return err
+}
+
+// ReadBackupManifest returns the manifest of the backup. For the backup
+// without the manifest, it returns the files stored in the backup.
+func ReadBackupManifest(zr *zip.Reader) (*BackupManifest, error) {
+	manifest := new(BackupManifest)
+	for _, f := range zr.File {
+		if f.Name != BackupManifestFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		if err := json.NewDecoder(rc).Decode(manifest); err != nil {
+			return nil, errors.CriticalFormatError.Wrap(err, "InvalidBackupManifest")
+		}
+		return manifest, nil
+	}
+	for _, f := range zr.File {
+		if f.Mode().IsRegular() {
+			manifest.Files = append(manifest.Files, BackupFile{
+				Name:    f.Name,
+				Size:    int64(f.UncompressedSize64),
+				ModTime: f.Modified.UnixNano(),
+			})
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"archive/zip"
+	"os"
+	"path"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+func writeChainFile(t *testing.T, dir, name, content string, mtime time.Time) {
+	p := path.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(path.Dir(p), 0755))
+	assert.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	assert.NoError(t, os.Chtimes(p, mtime, mtime))
+}
+
+func writeTestBackup(chainDir, file, base string, info *BackupInfo) error {
+	tb := &taskBackup{file: file, base: base}
+	if base != "" {
+		if err := tb._loadBase(info); err != nil {
+			return err
+		}
+	}
+	fd, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	tb.fd = fd
+	tb.zw = zip.NewWriter(fd)
+	if err := writeBackupInfo(tb.zw, info); err != nil {
+		return err
+	}
+	if err := tb._writeFiles(chainDir, []string{DefaultWALDir, DefaultDBDir}); err != nil {
+		return err
+	}
+	return tb.zw.Close()
+}
+
+func testBackupInfo(height int64) *BackupInfo {
+	return &BackupInfo{
+		NID:     common.HexInt32{Value: 1},
+		CID:     common.HexInt32{Value: 0x1234},
+		Channel: "test",
+		Height:  height,
+		Codec:   codec.BC.Name(),
+	}
+}
+
+func readBackup(t *testing.T, file string) ([]string, *BackupInfo, []string) {
+	zr, err := zip.OpenReader(file)
+	assert.NoError(t, err)
+	defer zr.Close()
+
+	var stored []string
+	for _, f := range zr.File {
+		if f.Name != BackupManifestFile {
+			stored = append(stored, f.Name)
+		}
+	}
+	info, err := ReadBackupInfo(&zr.Reader)
+	assert.NoError(t, err)
+	manifest, err := ReadBackupManifest(&zr.Reader)
+	assert.NoError(t, err)
+	var listed []string
+	for _, f := range manifest.Files {
+		listed = append(listed, f.Name)
+	}
+	sort.Strings(stored)
+	sort.Strings(listed)
+	return stored, info, listed
+}
+
+func TestTaskBackup_Incremental(t *testing.T) {
+	chainDir := t.TempDir()
+	backupDir := t.TempDir()
+	mtime := time.Unix(1700000000, 0)
+	writeChainFile(t, chainDir, "db/000001.ldb", "a", mtime)
+	writeChainFile(t, chainDir, "db/000002.ldb", "b", mtime)
+	writeChainFile(t, chainDir, "wal/000.wal", "w", mtime)
+
+	full := path.Join(backupDir, "full.zip")
+	assert.NoError(t, writeTestBackup(chainDir, full, "", testBackupInfo(10)))
+	stored, info, listed := readBackup(t, full)
+	assert.Equal(t, "", info.Base)
+	assert.Equal(t, []string{"db/000001.ldb", "db/000002.ldb", "wal/000.wal"}, stored)
+	assert.Equal(t, stored, listed)
+
+	// changed, added and removed files
+	writeChainFile(t, chainDir, "db/000002.ldb", "bb", mtime.Add(time.Second))
+	writeChainFile(t, chainDir, "db/000003.ldb", "c", mtime.Add(time.Second))
+	assert.NoError(t, os.Remove(path.Join(chainDir, "wal/000.wal")))
+
+	incr := path.Join(backupDir, "incr.zip")
+	assert.NoError(t, writeTestBackup(chainDir, incr, full, testBackupInfo(20)))
+	stored, info, listed = readBackup(t, incr)
+	assert.Equal(t, "full.zip", info.Base)
+	assert.Equal(t, []string{"db/000002.ldb", "db/000003.ldb"}, stored)
+	assert.Equal(t, []string{"db/000001.ldb", "db/000002.ldb", "db/000003.ldb"}, listed)
+
+	// same size with different modification time is also stored
+	writeChainFile(t, chainDir, "db/000001.ldb", "x", mtime.Add(2*time.Second))
+	incr2 := path.Join(backupDir, "incr2.zip")
+	assert.NoError(t, writeTestBackup(chainDir, incr2, incr, testBackupInfo(30)))
+	stored, info, _ = readBackup(t, incr2)
+	assert.Equal(t, "incr.zip", info.Base)
+	assert.Equal(t, []string{"db/000001.ldb"}, stored)
+}
+
+func TestTaskBackup_InvalidBase(t *testing.T) {
+	chainDir := t.TempDir()
+	backupDir := t.TempDir()
+	writeChainFile(t, chainDir, "db/000001.ldb", "a", time.Unix(1700000000, 0))
+
+	full := path.Join(backupDir, "full.zip")
+	assert.NoError(t, writeTestBackup(chainDir, full, "", testBackupInfo(10)))
+
+	incr := path.Join(backupDir, "incr.zip")
+	check := func(base string, info *BackupInfo) {
+		err := writeTestBackup(chainDir, incr, base, info)
+		assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+	}
+
+	// missing base
+	check(path.Join(backupDir, "missing.zip"), testBackupInfo(20))
+
+	// corrupted base
+	corrupted := path.Join(backupDir, "corrupted.zip")
+	bs, err := os.ReadFile(full)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(corrupted, bs[:len(bs)/2], 0644))
+	check(corrupted, testBackupInfo(20))
+
+	// base of other chain
+	info := testBackupInfo(20)
+	info.Channel = "other"
+	check(full, info)
+
+	// base higher than the current
+	check(full, testBackupInfo(5))
+}
+
+func TestHotBackup_NotSupported(t *testing.T) {
+	chainDir := t.TempDir()
+	backupDir := t.TempDir()
+	backend, err := db.Open(chainDir, string(db.MapDBBackend), "1")
+	assert.NoError(t, err)
+	defer backend.Close()
+
+	c := &singleChain{
+		backend: backend,
+		state:   Started,
+		cfg: Config{
+			DBType:  string(db.MapDBBackend),
+			BaseDir: chainDir,
+		},
+	}
+	err = c.HotBackup(path.Join(backupDir, "backup.zip"), "", nil)
+	assert.True(t, errors.UnsupportedError.Equals(err))
+	assert.Nil(t, c.hotBackup)
+
+	// nothing is left behind
+	entries, err := os.ReadDir(backupDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	entries, err = os.ReadDir(chainDir)
+	assert.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), TemporalHotBackupDir)
+	}
+}
+
+func TestHotBackup_CheckpointGoLevelDB(t *testing.T) {
+	chainDir := t.TempDir()
+	backend, err := db.Open(path.Join(chainDir, DefaultDBDir), string(db.GoLevelDBBackend), "1")
+	assert.NoError(t, err)
+	defer backend.Close()
+
+	c := &singleChain{
+		backend: backend,
+		state:   Started,
+		cfg: Config{
+			NID:     1,
+			DBType:  string(db.GoLevelDBBackend),
+			BaseDir: chainDir,
+		},
+	}
+	assert.NoError(t, c.checkHotBackup())
+
+	bk, err := backend.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("key"), []byte("value")))
+	assert.NoError(t, block.SetLastHeight(backend, codec.BC, 7))
+
+	dir := t.TempDir()
+	height, err := c.checkpoint(dir)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 7, height)
+
+	// the checkpoint is kept after changes of the chain database
+	assert.NoError(t, bk.Delete([]byte("key")))
+	cdb, err := db.OpenReadOnly(path.Join(dir, DefaultDBDir), string(db.GoLevelDBBackend), "1")
+	assert.NoError(t, err)
+	defer cdb.Close()
+	cbk, err := cdb.GetBucket(db.ChainProperty)
+	assert.NoError(t, err)
+	value, err := cbk.Get([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+}
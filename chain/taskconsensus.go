@@ -17,7 +17,10 @@
 package chain
 
 import (
+	"fmt"
+
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
 )
 
 type taskConsensus struct {
@@ -37,6 +40,14 @@ func (t *taskConsensus) String() string {
 }
 
 func (t *taskConsensus) DetailOf(s State) string {
+	if s == Started {
+		if sm, ok := t.chain.cs.(module.ConsensusSyncMonitor); ok {
+			if st := sm.GetSyncStatus(); st != nil {
+				return fmt.Sprintf("started fastsync %d/%d peers=%d rate=%.1f/s",
+					st.Height, st.TargetHeight, st.Peers, st.Rate)
+			}
+		}
+	}
 	if name, ok := consensusStates[s]; ok {
 		return name
 	} else {
@@ -62,6 +73,9 @@ func (t *taskConsensus) _start(c *singleChain) error {
 	if err := c.cs.Start(); err != nil {
 		return err
 	}
+	if err := c.startTxIndexer(); err != nil {
+		return err
+	}
 	c.srv.SetChain(c.cfg.Channel, c)
 	if err := c.nm.Start(); err != nil {
 		return err
@@ -24,7 +24,6 @@ import (
 	"net/http"
 	"os"
 	"path"
-	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
@@ -95,8 +94,7 @@ func (t *taskImportICON) _prepareDatabase() error {
 	cfg := t.chain.cfg
 	chainDir := cfg.AbsBaseDir()
 	tmpDBDir := path.Join(chainDir, DefaultTmpDBDir)
-	dbName := strconv.FormatInt(int64(cfg.NID), 16)
-	if dbase, err := db.Open(tmpDBDir, cfg.DBType, dbName); err != nil {
+	if dbase, err := t.chain.openDatabase(tmpDBDir, cfg.DBType); err != nil {
 		return err
 	} else {
 		t.dbase = dbase
@@ -536,11 +536,20 @@ func (t *taskReset) _resetToHeight(height int64, blockHash []byte) (ret error) {
 }
 
 func (t *taskReset) _reset() (ret error) {
+	var err error
 	if t.height == 0 {
-		return t._resetToGenesis()
+		err = t._resetToGenesis()
 	} else {
-		return t._resetToHeight(t.height, t.blockHash)
+		err = t._resetToHeight(t.height, t.blockHash)
+	}
+	if err != nil {
+		return err
+	}
+	// the chain signs messages from the lower height again.
+	if sp := t.chain.SlashingProtection(); sp != nil && t.chain.wallet != nil {
+		return sp.Reset(t.chain.wallet.Address(), t.chain.NID())
 	}
+	return nil
 }
 
 func (t *taskReset) Stop() {
@@ -0,0 +1,556 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/chain/gs"
+	"github.com/icon-project/goloop/chain/snapshot"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	SnapshotExportTask = "snapshot_export"
+	SnapshotImportTask = "snapshot_import"
+
+	snapshotBatchSize = 4096
+)
+
+var snapshotExportStates = map[State]string{
+	Starting: "snapshot export starting",
+	Stopping: "snapshot export stopping",
+	Failed:   "snapshot export failed",
+	Finished: "snapshot export done",
+}
+
+var snapshotImportStates = map[State]string{
+	Starting: "snapshot import starting",
+	Stopping: "snapshot import stopping",
+	Failed:   "snapshot import failed",
+	Finished: "snapshot import done",
+}
+
+// snapshotDB records entries written by the block manager into the snapshot.
+// Entries are also stored in the temporary database, so the builder can
+// resolve them, and the same entry is written only once.
+type snapshotDB struct {
+	db.Database
+	writer *snapshot.Writer
+}
+
+type snapshotBucket struct {
+	db.Bucket
+	id     db.BucketID
+	writer *snapshot.Writer
+}
+
+func (b *snapshotBucket) Set(key, value []byte) error {
+	old, err := b.Bucket.Get(key)
+	if err != nil {
+		return err
+	}
+	if old != nil && bytes.Equal(old, value) {
+		return nil
+	}
+	if err := b.Bucket.Set(key, value); err != nil {
+		return err
+	}
+	return b.writer.Add(b.id, key, value)
+}
+
+func (b *snapshotBucket) Delete(key []byte) error {
+	return errors.UnsupportedError.Errorf("DeleteOnSnapshot(bucket=%q)", b.id)
+}
+
+func (d *snapshotDB) GetBucket(id db.BucketID) (db.Bucket, error) {
+	bk, err := d.Database.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotBucket{
+		Bucket: bk,
+		id:     id,
+		writer: d.writer,
+	}, nil
+}
+
+type snapshotExportParams struct {
+	File   string `json:"file"`
+	Height int64  `json:"height,omitempty"`
+}
+
+type taskSnapshotExport struct {
+	chain  *singleChain
+	result resultStore
+	file   string
+	height int64
+
+	entries    uint64
+	resolved   uint64
+	unresolved uint64
+	stop       int32
+}
+
+func (t *taskSnapshotExport) String() string {
+	return fmt.Sprintf("SnapshotExport(file=%s,height=%d)", path.Base(t.file), t.height)
+}
+
+func (t *taskSnapshotExport) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("snapshot export height=%d entries=%d resolved=%d unresolved=%d",
+			t.height,
+			atomic.LoadUint64(&t.entries),
+			atomic.LoadUint64(&t.resolved),
+			atomic.LoadUint64(&t.unresolved))
+	default:
+		if st, ok := snapshotExportStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskSnapshotExport) Start() error {
+	if err := t.chain.prepareManagers(); err != nil {
+		return err
+	}
+	blk, err := t.chain.bm.GetLastBlock()
+	if err != nil {
+		t.chain.releaseManagers()
+		return err
+	}
+	if t.height == 0 {
+		t.height = blk.Height() - 1
+	}
+	if t.height < 0 || t.height >= blk.Height() {
+		t.chain.releaseManagers()
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidHeight(height=%d,last=%d)", t.height, blk.Height())
+	}
+	go t.doExport()
+	return nil
+}
+
+func (t *taskSnapshotExport) doExport() {
+	err := t._export()
+	t.result.SetValue(err)
+}
+
+func (t *taskSnapshotExport) _interrupted() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+func (t *taskSnapshotExport) onExport(height int64, r, u int) error {
+	if t._interrupted() {
+		return errors.ErrInterrupted
+	}
+	atomic.StoreUint64(&t.resolved, uint64(r))
+	atomic.StoreUint64(&t.unresolved, uint64(u))
+	return nil
+}
+
+func (t *taskSnapshotExport) _exportGenesis(blk module.Block, votes module.CommitVoteSet) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	gsw := gs.NewGenesisStorageWriter(buf)
+	if err := t.chain.bm.ExportGenesis(blk, votes, gsw); err != nil {
+		return nil, errors.Wrap(err, "fail on exporting genesis storage")
+	}
+	if err := gsw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (t *taskSnapshotExport) _writeSnapshot(w io.Writer, hdr *snapshot.Header) error {
+	c := t.chain
+	chainDir := c.cfg.AbsBaseDir()
+	dbpath := path.Join(chainDir, DefaultTmpDBDir)
+
+	_ = os.RemoveAll(dbpath)
+	tmpDB, err := c.openDatabase(dbpath, string(db.GoLevelDBBackend))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.Must(tmpDB.Close())
+		log.Must(os.RemoveAll(dbpath))
+	}()
+
+	sw, err := snapshot.NewWriter(w, hdr, 0)
+	if err != nil {
+		return err
+	}
+	sdb := &snapshotDB{Database: tmpDB, writer: sw}
+	err = c.bm.ExportBlocks(hdr.Height, hdr.Height, sdb, func(h int64, r, u int) error {
+		atomic.StoreUint64(&t.entries, sw.Count())
+		return t.onExport(h, r, u)
+	})
+	if err != nil {
+		return err
+	}
+	if err := sw.Close(); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&t.entries, sw.Count())
+	return nil
+}
+
+func (t *taskSnapshotExport) _export() (rerr error) {
+	c := t.chain
+	defer c.releaseManagers()
+
+	blk, err := c.bm.GetBlockByHeight(t.height)
+	if err != nil {
+		return err
+	}
+	if cid, err := c.sm.GetChainID(blk.Result()); err != nil {
+		return errors.InvalidStateError.New("No ChainID is recorded (require Revision 8)")
+	} else if cid != int64(c.CID()) {
+		return errors.InvalidStateError.Errorf("Invalid chain ID real=%d exp=%d", cid, c.CID())
+	}
+	nblk, err := c.bm.GetBlockByHeight(t.height + 1)
+	if err != nil {
+		return errors.InvalidStateError.Errorf("No next block height=%d", t.height)
+	}
+	genesis, err := t._exportGenesis(blk, nblk.Votes())
+	if err != nil {
+		return err
+	}
+
+	tmpFile := t.file + TempSuffix
+	fd, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "fail to create file=%s", tmpFile)
+	}
+	defer func() {
+		if rerr != nil {
+			_ = fd.Close()
+			_ = os.Remove(tmpFile)
+		}
+	}()
+
+	c.logger.Infof("Export Snapshot to=%s height=%d", t.file, t.height)
+	bw := bufio.NewWriter(fd)
+	err = t._writeSnapshot(bw, &snapshot.Header{
+		CID:     c.CID(),
+		NID:     c.NID(),
+		Height:  t.height,
+		BlockID: blk.ID(),
+		Genesis: genesis,
+	})
+	if err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpFile, t.file); err != nil {
+		return errors.Wrapf(err, "fail to rename %s to %s", tmpFile, t.file)
+	}
+	c.logger.Infof("Export Snapshot done entries=%d", atomic.LoadUint64(&t.entries))
+	return nil
+}
+
+func (t *taskSnapshotExport) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskSnapshotExport) Wait() error {
+	return t.result.Wait()
+}
+
+type snapshotImportParams struct {
+	File string `json:"file"`
+}
+
+type taskSnapshotImport struct {
+	chain  *singleChain
+	result resultStore
+	file   string
+
+	height  int64
+	entries uint64
+	stop    int32
+}
+
+func (t *taskSnapshotImport) String() string {
+	return fmt.Sprintf("SnapshotImport(file=%s)", path.Base(t.file))
+}
+
+func (t *taskSnapshotImport) DetailOf(s State) string {
+	switch s {
+	case Started:
+		return fmt.Sprintf("snapshot import height=%d entries=%d",
+			atomic.LoadInt64(&t.height), atomic.LoadUint64(&t.entries))
+	default:
+		if st, ok := snapshotImportStates[s]; ok {
+			return st
+		} else {
+			return s.String()
+		}
+	}
+}
+
+func (t *taskSnapshotImport) Start() error {
+	go t.doImport()
+	return nil
+}
+
+func (t *taskSnapshotImport) doImport() {
+	err := t._import()
+	t.result.SetValue(err)
+}
+
+func (t *taskSnapshotImport) _interrupted() bool {
+	return atomic.LoadInt32(&t.stop) != 0
+}
+
+func (t *taskSnapshotImport) _verifyHeader(hdr *snapshot.Header) (module.GenesisStorage, error) {
+	c := t.chain
+	if hdr.CID != c.CID() || hdr.NID != c.NID() {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidChain(cid=%#x,nid=%#x,exp_cid=%#x,exp_nid=%#x)",
+			hdr.CID, hdr.NID, c.CID(), c.NID())
+	}
+	g, err := gs.New(hdr.Genesis)
+	if err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidGenesisStorage")
+	}
+	if gt, err := g.Type(); err != nil {
+		return nil, err
+	} else if gt != module.GenesisPruned {
+		return nil, errors.CriticalFormatError.Errorf("InvalidGenesisType(type=%d)", gt)
+	}
+	pg, err := gs.NewPrunedGenesis(g.Genesis())
+	if err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "InvalidPrunedGenesis")
+	}
+	if pg.Height.Value != hdr.Height || !bytes.Equal(pg.Block.Bytes(), hdr.BlockID) {
+		return nil, errors.CriticalFormatError.Errorf(
+			"GenesisMismatch(height=%d,block=%#x,exp_height=%d,exp_block=%#x)",
+			pg.Height.Value, pg.Block.Bytes(), hdr.Height, hdr.BlockID)
+	}
+	return g, nil
+}
+
+func (t *taskSnapshotImport) _writeDatabase(r *snapshot.Reader, dbDir string) (rerr error) {
+	_ = os.RemoveAll(dbDir)
+	dbase, err := t.chain.openDatabase(dbDir, t.chain.cfg.DBType)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		log.Must(dbase.Close())
+		if rerr != nil {
+			log.Must(os.RemoveAll(dbDir))
+		}
+	}()
+
+	batch := db.NewBatch(dbase)
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		batch.Set(e.Bucket, e.Key, e.Value)
+		if batch.Len() >= snapshotBatchSize {
+			if t._interrupted() {
+				return errors.ErrInterrupted
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+			atomic.StoreUint64(&t.entries, r.Count())
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&t.entries, r.Count())
+
+	// check the block is accessible with the height
+	hdr := r.Header()
+	bk, err := dbase.GetBucket(db.BlockHeaderHashByHeight)
+	if err != nil {
+		return err
+	}
+	id, err := bk.Get(codec.BC.MustMarshalToBytes(hdr.Height))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, hdr.BlockID) {
+		return errors.CriticalFormatError.Errorf(
+			"InvalidBlockOfHeight(height=%d,exp=%#x,real=%#x)", hdr.Height, hdr.BlockID, id)
+	}
+	return nil
+}
+
+func (t *taskSnapshotImport) _import() (ret error) {
+	c := t.chain
+	fd, err := os.Open(t.file)
+	if err != nil {
+		return errors.NotFoundError.Wrapf(err, "fail to open file=%s", t.file)
+	}
+	defer fd.Close()
+
+	r, err := snapshot.NewReader(bufio.NewReader(fd))
+	if err != nil {
+		return err
+	}
+	hdr := r.Header()
+	atomic.StoreInt64(&t.height, hdr.Height)
+	g, err := t._verifyHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	chainDir := c.cfg.AbsBaseDir()
+	dbDir := path.Join(chainDir, DefaultDBDir)
+	dbDirNew := dbDir + TempSuffix
+
+	c.logger.Infof("Import Snapshot from=%s height=%d", t.file, hdr.Height)
+	if err := t._writeDatabase(r, dbDirNew); err != nil {
+		return err
+	}
+
+	var rb Revertible
+	defer func() {
+		rb.RevertOrCommit(ret != nil)
+	}()
+	rb.Append(func(revert bool) {
+		if revert {
+			log.Must(os.RemoveAll(dbDirNew))
+		}
+	})
+
+	// replace with new database
+	c.releaseDatabase()
+	rb.Append(func(revert bool) {
+		if revert {
+			c.ensureDatabase()
+		}
+	})
+	if ret = rb.Delete(dbDir); ret != nil {
+		return
+	}
+	if ret = rb.Rename(dbDirNew, dbDir); ret != nil {
+		return
+	}
+	c.ensureDatabase()
+	rb.Append(func(revert bool) {
+		if revert {
+			c.releaseDatabase()
+		}
+	})
+
+	// remove other directories
+	for _, dir := range []string{DefaultContractDir, DefaultWALDir, DefaultCacheDir} {
+		if ret = rb.Delete(path.Join(chainDir, dir)); ret != nil {
+			return
+		}
+	}
+
+	// replace genesis storage
+	gsFile := path.Join(chainDir, ChainGenesisZipFileName)
+	if ret = rb.Delete(gsFile); ret != nil {
+		return
+	}
+	if ret = os.WriteFile(gsFile, hdr.Genesis, 0644); ret != nil {
+		return
+	}
+	rb.Append(func(revert bool) {
+		if revert {
+			_ = os.Remove(gsFile)
+		}
+	})
+
+	oldGS, oldGenesis := c.cfg.GenesisStorage, c.cfg.Genesis
+	c.cfg.GenesisStorage = g
+	c.cfg.Genesis = g.Genesis()
+	if ret = c.cfg.Save(); ret != nil {
+		c.cfg.GenesisStorage = oldGS
+		c.cfg.Genesis = oldGenesis
+		return errors.UnknownError.Wrap(ret, "fail to store configuration")
+	}
+	c.logger.Infof("Import Snapshot done entries=%d", r.Count())
+	return nil
+}
+
+func (t *taskSnapshotImport) Stop() {
+	atomic.StoreInt32(&t.stop, 1)
+}
+
+func (t *taskSnapshotImport) Wait() error {
+	return t.result.Wait()
+}
+
+func taskSnapshotExportFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(snapshotExportParams)
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, err
+	}
+	if len(p.File) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoFile")
+	}
+	if p.Height < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidHeight(height=%d)", p.Height)
+	}
+	return &taskSnapshotExport{
+		chain:  c,
+		file:   p.File,
+		height: p.Height,
+	}, nil
+}
+
+func taskSnapshotImportFactory(c *singleChain, params json.RawMessage) (chainTask, error) {
+	p := new(snapshotImportParams)
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, err
+	}
+	if len(p.File) == 0 {
+		return nil, errors.IllegalArgumentError.New("NoFile")
+	}
+	return &taskSnapshotImport{
+		chain: c,
+		file:  p.File,
+	}, nil
+}
+
+func init() {
+	registerTaskFactory(SnapshotExportTask, taskSnapshotExportFactory)
+	registerTaskFactory(SnapshotImportTask, taskSnapshotImportFactory)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package chain
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/txindex"
+	"github.com/icon-project/goloop/module"
+)
+
+func (c *singleChain) TxIndexEnabled() bool {
+	return c.cfg.TxIndex
+}
+
+func (c *singleChain) startTxIndexer() error {
+	if !c.cfg.TxIndex {
+		return nil
+	}
+	indexer := txindex.NewIndexer(c.database, c.bm, c.sm,
+		c.cfg.GenesisStorage.Height(), c.logger)
+	if err := indexer.Start(); err != nil {
+		return err
+	}
+	c.txIndexer = indexer
+	return nil
+}
+
+func (c *singleChain) stopTxIndexer() {
+	if c.txIndexer != nil {
+		c.txIndexer.Term()
+		c.txIndexer = nil
+	}
+}
+
+// InspectTxIndex returns the status of the transaction index of the chain.
+func InspectTxIndex(c module.Chain, informal bool) map[string]interface{} {
+	sc, ok := c.(*singleChain)
+	if !ok || !sc.cfg.TxIndex {
+		return nil
+	}
+	sc.mtx.RLock()
+	indexer := sc.txIndexer
+	sc.mtx.RUnlock()
+	if indexer == nil {
+		return nil
+	}
+
+	bottom, top, err := indexer.Status()
+	m := map[string]interface{}{
+		"bottom":     common.HexInt64{Value: bottom},
+		"top":        common.HexInt64{Value: top},
+		"backfilled": bottom <= sc.cfg.GenesisStorage.Height(),
+	}
+	if err != nil {
+		m["error"] = err.Error()
+	}
+	return m
+}
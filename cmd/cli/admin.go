@@ -21,6 +21,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/node"
 )
 
@@ -123,6 +124,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 			param.SeedAddr, _ = fs.GetString("seed")
 			param.Role, _ = fs.GetUint("role")
 			param.DBType, _ = fs.GetString("db_type")
+			param.DBKeyStore, _ = fs.GetString("db_key_store")
+			param.DBKeySecret, _ = fs.GetString("db_key_secret")
 			param.Platform, _ = fs.GetString("platform")
 			param.ConcurrencyLevel, _ = fs.GetInt("concurrency")
 			param.NormalTxPoolSize, _ = fs.GetInt("normal_tx_pool")
@@ -145,6 +148,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 				param.NephewsLimit = &nephewsLimit
 			}
 			param.ValidateTxOnSend, _ = fs.GetBool("validate_tx_on_send")
+			param.DBMetrics, _ = fs.GetBool("db_metrics")
+			param.TxIndex, _ = fs.GetBool("tx_index")
 
 			var buf *bytes.Buffer
 			if len(genesisZip) > 0 {
@@ -183,7 +188,10 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.String("genesis_template", "", "Genesis template directory or file")
 	joinFlags.String("seed", "", "List of trust-seed ip-port, Comma separated string")
 	joinFlags.Uint("role", 3, "[0:None, 1:Seed, 2:Validator, 3:Both]")
-	joinFlags.String("db_type", "goleveldb", "Name of database system("+strings.Join(db.RegisteredBackendTypes(), ", ")+")")
+	joinFlags.String("db_type", "goleveldb", "Name of database system("+strings.Join(db.RegisteredBackendTypes(), ", ")+
+		") with optional modifiers(+"+db.EncryptionModifier+": encrypt values, +"+db.KeyEncryptionModifier+": encrypt keys and values)")
+	joinFlags.String("db_key_store", "", "KeyStore file on the node for database encryption")
+	joinFlags.String("db_key_secret", "", "Secret (password) file on the node for db_key_store")
 	joinFlags.String("platform", "", "Name of service platform")
 	joinFlags.Int("concurrency", 1, "Maximum number of executors to be used for concurrency")
 	joinFlags.Int("normal_tx_pool", 0, "Size of normal transaction pool")
@@ -202,6 +210,8 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	joinFlags.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	joinFlags.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	joinFlags.Bool("validate_tx_on_send", false, "Validate transaction on send")
+	joinFlags.Bool("db_metrics", false, "Collect metrics of database operations per bucket")
+	joinFlags.Bool("tx_index", false, "Maintain index of transactions by addresses")
 
 	leaveCmd := &cobra.Command{
 		Use:   "leave CID",
@@ -258,6 +268,25 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	inspectCmd.Flags().StringP("format", "f", "", "Format the output using the given Go template")
 	inspectCmd.Flags().Bool("informal", false, "Inspect with informal data")
 
+	consensusCmd := &cobra.Command{
+		Use:   "consensus CID",
+		Short: "Show consensus state of the chain",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := new(node.ConsensusView)
+			reqUrl := node.UrlChain + "/" + args[0] + "/consensus"
+			resp, err := adminClient.Get(reqUrl, v)
+			if err != nil {
+				return err
+			}
+			if err = JsonPrettyPrintln(os.Stdout, v); err != nil {
+				return errors.Errorf("failed JsonIntend resp=%+v, err=%+v", resp, err)
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(consensusCmd)
+
 	opFunc := func(op string) func(cmd *cobra.Command, args []string) error {
 		return func(cmd *cobra.Command, args []string) error {
 			reqUrl := node.UrlChain + "/" + args[0] + "/" + op
@@ -391,8 +420,12 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fs := cmd.Flags()
 			manual, _ := fs.GetBool("manual")
+			hot, _ := fs.GetBool("hot")
+			base, _ := fs.GetString("base")
 			param := &node.ChainBackupParam{
 				Manual: manual,
+				Hot:    hot,
+				Base:   base,
 			}
 			var v string
 			reqUrl := node.UrlChain + "/" + args[0] + "/backup"
@@ -407,6 +440,29 @@ func NewChainCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	rootCmd.AddCommand(backupCmd)
 	backupFlags := backupCmd.Flags()
 	backupFlags.Bool("manual", false, "Manual backup mode (just release database)")
+	backupFlags.Bool("hot", false, "Hot backup mode (backup without stopping the chain, goleveldb copies all entries)")
+	backupFlags.String("base", "", "Name of the base backup for incremental backup")
+
+	evictCmd := &cobra.Command{
+		Use:   "evict CID TX_HASH",
+		Short: "Evict the transaction from the transaction pool",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txHash, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+			if err != nil {
+				return err
+			}
+			param := &node.ChainEvictParam{TxHash: txHash}
+			var v string
+			reqUrl := node.UrlChain + "/" + args[0] + "/evict"
+			if _, err := adminClient.PostWithJson(reqUrl, param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(evictCmd)
 
 	genesisCmd := &cobra.Command{
 		Use:   "genesis CID FILE",
@@ -577,10 +633,88 @@ func NewSystemCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comma
 
 	NewBackupCmd(rootCmd, &adminClient)
 	NewRestoreCmd(rootCmd, &adminClient)
+	NewSlashingProtectionCmd(rootCmd, &adminClient)
 
 	return rootCmd, vc
 }
 
+func NewSlashingProtectionCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
+	rootCmd := &cobra.Command{
+		Use:   "slashing_protection",
+		Short: "Manage signed consensus messages for slashing protection",
+	}
+	parent.AddCommand(rootCmd)
+
+	exportCmd := &cobra.Command{
+		Use:   "export [FILE]",
+		Short: "Export signed consensus messages",
+		Args:  cobra.RangeArgs(0, 1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := new(consensus.SlashingProtectionData)
+			if _, err := client.Get(node.UrlSystem+"/slashing_protection", v); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				return JsonPrettyPrintln(os.Stdout, v)
+			}
+			return JsonPrettySaveFile(args[0], 0600, v)
+		},
+	}
+	rootCmd.AddCommand(exportCmd)
+
+	importCmd := &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import signed consensus messages exported from other node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bs, err := ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			data := new(consensus.SlashingProtectionData)
+			if err = json.Unmarshal(bs, data); err != nil {
+				return errors.Errorf("invalid file=%s err=%+v", args[0], err)
+			}
+			var v string
+			if _, err = client.PostWithJson(node.UrlSystem+"/slashing_protection", data, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(importCmd)
+
+	resetCmd := &cobra.Command{
+		Use:   "reset NID [ADDRESS]",
+		Short: "Reset signed consensus messages of the signer(default: node) for the network",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nid, err := strconv.ParseInt(args[0], 0, 32)
+			if err != nil {
+				return errors.Errorf("invalid nid=%s err=%+v", args[0], err)
+			}
+			param := &node.SlashingProtectionResetParam{
+				NID: common.HexInt32{Value: int32(nid)},
+			}
+			if len(args) > 1 {
+				addr, err := common.NewAddressFromString(args[1])
+				if err != nil {
+					return errors.Errorf("invalid address=%s err=%+v", args[1], err)
+				}
+				param.Address = addr
+			}
+			var v string
+			if _, err = client.PostWithJson(node.UrlSystem+"/slashing_protection/reset", param, &v); err != nil {
+				return err
+			}
+			fmt.Println(v)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(resetCmd)
+}
+
 func NewBackupCmd(parent *cobra.Command, client *node.UnixDomainSockHttpClient) {
 	rootCmd := &cobra.Command{
 		Use:   "backup",
@@ -0,0 +1,725 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/txindex"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service"
+)
+
+// sharedKeySpaceBackends are the backends storing all buckets in one key
+// space, which are required for converting whole entries.
+var sharedKeySpaceBackends = []db.BackendType{
+	db.GoLevelDBBackend,
+	db.PebbleDBBackend,
+}
+
+func checkSharedKeySpaceBackend(backend db.BackendType) error {
+	for _, be := range sharedKeySpaceBackends {
+		if be == backend {
+			return nil
+		}
+	}
+	return errors.UnsupportedError.Errorf("UnsupportedBackend(type=%s)", backend)
+}
+
+type databaseParams struct {
+	dbType    string
+	name      string
+	keyStore  string
+	keySecret string
+}
+
+// checkDir checks that the database exists in the directory.
+func (p *databaseParams) checkDir(dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, p.name)); err != nil {
+		return errors.IllegalArgumentError.Wrapf(err,
+			"InvalidDatabaseDir(dir=%s,name=%s)", dir, p.name)
+	}
+	return nil
+}
+
+// open opens the existing database in the directory only for reading,
+// so inspecting it doesn't create or modify anything.
+func (p *databaseParams) open(dir string) (db.Database, error) {
+	return p.openWith(dir, db.OpenReadOnly)
+}
+
+// openForWrite opens the existing database in the directory for updating
+// it.
+func (p *databaseParams) openForWrite(dir string) (db.Database, error) {
+	return p.openWith(dir, db.Open)
+}
+
+func (p *databaseParams) openWith(
+	dir string, opener func(dir, dbType, name string) (db.Database, error),
+) (db.Database, error) {
+	if err := p.checkDir(dir); err != nil {
+		return nil, err
+	}
+	backend, modifiers := db.SplitType(p.dbType)
+	dbase, err := opener(dir, string(backend), p.name)
+	if err != nil {
+		return nil, err
+	}
+	if len(modifiers) > 0 {
+		secret, err := chain.LoadDBSecret(p.keyStore, p.keySecret)
+		if err != nil {
+			_ = dbase.Close()
+			return nil, err
+		}
+		mdb, err := db.WithModifiers(dbase, modifiers, secret)
+		if err != nil {
+			_ = dbase.Close()
+			return nil, err
+		}
+		return mdb, nil
+	}
+	return dbase, nil
+}
+
+// openRaw opens the existing database in the directory only for reading
+// without applying modifiers.
+func (p *databaseParams) openRaw(dir string) (db.Database, []string, error) {
+	if err := p.checkDir(dir); err != nil {
+		return nil, nil, err
+	}
+	backend, modifiers := db.SplitType(p.dbType)
+	dbase, err := db.OpenReadOnly(dir, string(backend), p.name)
+	return dbase, modifiers, err
+}
+
+type knownBucket struct {
+	name string
+	id   db.BucketID
+}
+
+var knownBuckets = []knownBucket{
+	{"merkle", db.MerkleTrie},
+	{"bytes", db.BytesByHash},
+	{"txloc", db.TransactionLocatorByHash},
+	{"txaddr", db.TransactionLocatorByAddress},
+	{"height", db.BlockHeaderHashByHeight},
+	{"property", db.ChainProperty},
+	{"btp.icon", "i" + db.ListByMerkleRootBase},
+	{"btp.eth", "e" + db.ListByMerkleRootBase},
+}
+
+// bucketIDOf returns the bucket id for the name of known bucket.
+// Other names are used as bucket id.
+func bucketIDOf(name string) db.BucketID {
+	for _, kb := range knownBuckets {
+		if kb.name == name {
+			return kb.id
+		}
+	}
+	return db.BucketID(name)
+}
+
+// bucketOfRawKey returns the name of the bucket for the raw entry of the
+// database storing all buckets in one key space. Keys of MerkleTrie bucket
+// are hashes of the values without prefix, so a hash sized key is regarded
+// as a trie node unless it matches the declared key size of a bucket or
+// the prefix of a bucket without declared key size while the value doesn't
+// match the key.
+func bucketOfRawKey(key, value []byte) string {
+	var prefixed *knownBucket
+	for i := range knownBuckets[1:] {
+		kb := &knownBuckets[i+1]
+		if !bytes.HasPrefix(key, []byte(kb.id)) {
+			continue
+		}
+		if size := kb.id.KeySize(); size > 0 {
+			if len(key) == len(kb.id)+size {
+				return kb.name
+			}
+		} else if prefixed == nil {
+			prefixed = kb
+		}
+	}
+	if len(key) == crypto.HashLen {
+		if prefixed == nil || bytes.Equal(crypto.SHA3Sum256(value), key) {
+			return knownBuckets[0].name
+		}
+	}
+	if prefixed != nil {
+		return prefixed.name
+	}
+	return "unknown"
+}
+
+func parseHexKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidKey(key=%s)", s)
+	}
+	return key, nil
+}
+
+func hexOf(bs []byte) string {
+	return "0x" + hex.EncodeToString(bs)
+}
+
+func newDatabaseBucketsCmd(c string) *cobra.Command {
+	return &cobra.Command{
+		Use:   c,
+		Short: "List well-known buckets",
+		Args:  ArgsWithDefaultErrorFunc(cobra.NoArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			type bucketInfo struct {
+				Name string          `json:"name"`
+				ID   common.HexBytes `json:"id"`
+			}
+			var infos []bucketInfo
+			for _, kb := range knownBuckets {
+				infos = append(infos, bucketInfo{kb.name, []byte(kb.id)})
+			}
+			return JsonPrettyPrintln(os.Stdout, infos)
+		},
+	}
+}
+
+func newDatabaseGetCmd(c string, params *databaseParams) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " DB_DIR BUCKET KEY",
+		Short: "Get the value for the key(hex) in the bucket",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := parseHexKey(args[2])
+			if err != nil {
+				return err
+			}
+			dbase, err := params.open(args[0])
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+			value, err := db.DoGetWithBucketID(dbase, bucketIDOf(args[1]), key)
+			if err != nil {
+				return err
+			}
+			fmt.Println(hexOf(value))
+			return nil
+		},
+	}
+}
+
+func newDatabaseScanCmd(c string, params *databaseParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c + " DB_DIR BUCKET",
+		Short: "Scan entries of the bucket in order of keys",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+	}
+	flags := cmd.Flags()
+	prefix := flags.String("prefix", "", "Prefix of keys(hex)")
+	start := flags.String("start", "", "Start key(hex, inclusive)")
+	end := flags.String("end", "", "End key(hex, exclusive)")
+	limit := flags.Int("limit", 0, "Maximum number of entries (0 for no limit)")
+	keysOnly := flags.Bool("keys_only", false, "Print keys only")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		r := &db.Range{}
+		if len(*prefix) > 0 {
+			if len(*start) > 0 || len(*end) > 0 {
+				return errors.IllegalArgumentError.New("PrefixWithStartOrEnd")
+			}
+			p, err := parseHexKey(*prefix)
+			if err != nil {
+				return err
+			}
+			r = db.PrefixRange(p)
+		} else {
+			var err error
+			if len(*start) > 0 {
+				if r.Start, err = parseHexKey(*start); err != nil {
+					return err
+				}
+			}
+			if len(*end) > 0 {
+				if r.Limit, err = parseHexKey(*end); err != nil {
+					return err
+				}
+			}
+		}
+		dbase, err := params.open(args[0])
+		if err != nil {
+			return err
+		}
+		defer dbase.Close()
+		id := bucketIDOf(args[1])
+		bk, err := dbase.GetBucket(id)
+		if err != nil {
+			return err
+		}
+		// MerkleTrie bucket covers all raw entries in the backends storing
+		// all buckets in one key space, so pick trie nodes only.
+		backend, _ := db.SplitType(params.dbType)
+		trieOnly := id == db.MerkleTrie && checkSharedKeySpaceBackend(backend) == nil
+		it := db.NewIterator(bk, r)
+		defer it.Release()
+		for cnt := 0; (*limit <= 0 || cnt < *limit) && it.Next(); {
+			if trieOnly && bucketOfRawKey(it.Key(), it.Value()) != knownBuckets[0].name {
+				continue
+			}
+			cnt++
+			if *keysOnly {
+				fmt.Println(hexOf(it.Key()))
+			} else {
+				fmt.Println(hexOf(it.Key()), hexOf(it.Value()))
+			}
+		}
+		return it.Error()
+	}
+	return cmd
+}
+
+func blockHeaderAt(dbase db.Database, height int64) ([]byte, *block.V2HeaderFormat, error) {
+	version, err := block.GetBlockVersion(dbase, nil, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != module.BlockVersion2 {
+		return nil, nil, errors.UnsupportedError.Errorf("UnsupportedBlockVersion(version=%d)", version)
+	}
+	hash, err := block.GetBlockHeaderHashByHeight(dbase, nil, height)
+	if err != nil {
+		return nil, nil, err
+	}
+	bs, err := db.DoGetWithBucketID(dbase, db.BytesByHash, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	hf := new(block.V2HeaderFormat)
+	if _, err := codec.BC.UnmarshalFromBytes(bs, hf); err != nil {
+		return nil, nil, err
+	}
+	return hash, hf, nil
+}
+
+func newDatabaseBlockCmd(c string, params *databaseParams) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " DB_DIR [HEIGHT]",
+		Short: "Show the block header at the height (default: last height)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.RangeArgs(1, 2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbase, err := params.open(args[0])
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+			var height int64
+			if len(args) > 1 {
+				if height, err = strconv.ParseInt(args[1], 0, 64); err != nil {
+					return errors.IllegalArgumentError.Wrapf(err, "InvalidHeight(height=%s)", args[1])
+				}
+			} else if height, err = block.GetLastHeight(dbase); err != nil {
+				return err
+			}
+			hash, hf, err := blockHeaderAt(dbase, height)
+			if err != nil {
+				return err
+			}
+			var proposer module.Address
+			if len(hf.Proposer) > 0 {
+				if proposer, err = common.BytesToAddress(hf.Proposer); err != nil {
+					return err
+				}
+			}
+			return JsonPrettyPrintln(os.Stdout, map[string]interface{}{
+				"id":                     common.HexBytes(hash),
+				"version":                common.HexInt32{Value: int32(hf.Version)},
+				"height":                 common.HexInt64{Value: hf.Height},
+				"timestamp":              common.HexInt64{Value: hf.Timestamp},
+				"proposer":               proposer,
+				"prevID":                 common.HexBytes(hf.PrevID),
+				"votesHash":              common.HexBytes(hf.VotesHash),
+				"nextValidatorsHash":     common.HexBytes(hf.NextValidatorsHash),
+				"patchTransactionsHash":  common.HexBytes(hf.PatchTransactionsHash),
+				"normalTransactionsHash": common.HexBytes(hf.NormalTransactionsHash),
+				"logsBloom":              common.HexBytes(hf.LogsBloom),
+				"result":                 common.HexBytes(hf.Result),
+				"nsFilter":               common.HexBytes(hf.NSFilter),
+			})
+		},
+	}
+}
+
+func newDatabaseTxCmd(c string, params *databaseParams) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " DB_DIR TX_HASH",
+		Short: "Show the location of the transaction",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseHexKey(args[1])
+			if err != nil {
+				return err
+			}
+			dbase, err := params.open(args[0])
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+			bk, err := db.NewCodedBucket(dbase, db.TransactionLocatorByHash, nil)
+			if err != nil {
+				return err
+			}
+			var loc module.TransactionLocator
+			if err := bk.Get(db.Raw(id), &loc); err != nil {
+				return err
+			}
+			group := "normal"
+			if loc.TransactionGroup == module.TransactionGroupPatch {
+				group = "patch"
+			}
+			return JsonPrettyPrintln(os.Stdout, map[string]interface{}{
+				"txHash":      common.HexBytes(id),
+				"blockHeight": common.HexInt64{Value: loc.BlockHeight},
+				"group":       group,
+				"index":       common.HexInt32{Value: int32(loc.IndexInGroup)},
+			})
+		},
+	}
+}
+
+// chainPropertyDecoders decodes values of well-known chain properties.
+var chainPropertyDecoders = map[string]func(bs []byte) (interface{}, error){
+	"block.lastHeight":      decodeHeightProperty,
+	txindex.KeyTopHeight:    decodeHeightProperty,
+	txindex.KeyBottomHeight: decodeHeightProperty,
+}
+
+func decodeHeightProperty(bs []byte) (interface{}, error) {
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return nil, err
+	}
+	return common.HexInt64{Value: height}, nil
+}
+
+func newDatabasePropertyCmd(c string, params *databaseParams) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " DB_DIR [KEY...]",
+		Short: "Show chain properties (default: all)",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbase, err := params.open(args[0])
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+			bk, err := dbase.GetBucket(db.ChainProperty)
+			if err != nil {
+				return err
+			}
+			props := make(map[string]interface{})
+			setProp := func(key, value []byte) error {
+				name := string(key)
+				if !utf8.Valid(key) {
+					name = hexOf(key)
+				}
+				if dec, ok := chainPropertyDecoders[name]; ok {
+					v, err := dec(value)
+					if err != nil {
+						return errors.CriticalFormatError.Wrapf(err, "InvalidProperty(key=%s)", name)
+					}
+					props[name] = v
+				} else {
+					props[name] = common.HexBytes(value)
+				}
+				return nil
+			}
+			if len(args) > 1 {
+				for _, name := range args[1:] {
+					value, err := db.DoGet(bk, []byte(name))
+					if err != nil {
+						return err
+					}
+					if err := setProp([]byte(name), value); err != nil {
+						return err
+					}
+				}
+			} else {
+				it := db.NewIterator(bk, nil)
+				defer it.Release()
+				for it.Next() {
+					if err := setProp(it.Key(), it.Value()); err != nil {
+						return err
+					}
+				}
+				if err := it.Error(); err != nil {
+					return err
+				}
+			}
+			return JsonPrettyPrintln(os.Stdout, props)
+		},
+	}
+}
+
+func newDatabaseStateDiffCmd(c string, params *databaseParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c + " DB_DIR FROM TO",
+		Short: "Show changed accounts between world states of the heights",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+	}
+	storage := cmd.Flags().Bool("storage", false, "Include changed entries of storages")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		var heights [2]int64
+		for i, arg := range args[1:] {
+			height, err := strconv.ParseInt(arg, 0, 64)
+			if err != nil {
+				return errors.IllegalArgumentError.Wrapf(err, "InvalidHeight(height=%s)", arg)
+			}
+			heights[i] = height
+		}
+		dbase, err := params.open(args[0])
+		if err != nil {
+			return err
+		}
+		defer dbase.Close()
+		var results [2][]byte
+		for i, height := range heights {
+			_, hf, err := blockHeaderAt(dbase, height)
+			if err != nil {
+				return err
+			}
+			results[i] = hf.Result
+		}
+		diffs, err := service.DiffWorldStatesOfResults(dbase, results[0], results[1], *storage, 0)
+		if err != nil {
+			return err
+		}
+		accounts := make([]interface{}, 0, len(diffs))
+		for _, d := range diffs {
+			jso, err := d.ToJSON(module.JSONVersionLast)
+			if err != nil {
+				return err
+			}
+			accounts = append(accounts, jso)
+		}
+		return JsonPrettyPrintln(os.Stdout, map[string]interface{}{
+			"from":     common.HexInt64{Value: heights[0]},
+			"to":       common.HexInt64{Value: heights[1]},
+			"accounts": accounts,
+		})
+	}
+	return cmd
+}
+
+type bucketStat struct {
+	Bucket     string `json:"bucket"`
+	Count      int64  `json:"count"`
+	KeyBytes   int64  `json:"keyBytes"`
+	ValueBytes int64  `json:"valueBytes"`
+}
+
+func (s *bucketStat) add(key, value []byte) {
+	s.Count += 1
+	s.KeyBytes += int64(len(key))
+	s.ValueBytes += int64(len(value))
+}
+
+func newDatabaseStatsCmd(c string, params *databaseParams) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " DB_DIR",
+		Short: "Show number of entries and sizes of buckets",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Sizes are of stored (possibly encrypted) entries, so it
+			// doesn't need to decrypt them.
+			dbase, modifiers, err := params.openRaw(args[0])
+			if err != nil {
+				return err
+			}
+			defer dbase.Close()
+			for _, m := range modifiers {
+				if m == db.KeyEncryptionModifier {
+					return errors.UnsupportedError.New("StatsWithEncryptedKeys")
+				}
+			}
+
+			stats := make(map[string]*bucketStat)
+			for _, kb := range knownBuckets {
+				stats[kb.name] = &bucketStat{Bucket: kb.name}
+			}
+			backend, _ := db.SplitType(params.dbType)
+			if checkSharedKeySpaceBackend(backend) == nil {
+				bk, err := dbase.GetBucket(db.MerkleTrie)
+				if err != nil {
+					return err
+				}
+				it := db.NewIterator(bk, nil)
+				defer it.Release()
+				for it.Next() {
+					name := bucketOfRawKey(it.Key(), it.Value())
+					s, ok := stats[name]
+					if !ok {
+						s = &bucketStat{Bucket: name}
+						stats[name] = s
+					}
+					s.add(it.Key(), it.Value())
+				}
+				if err := it.Error(); err != nil {
+					return err
+				}
+			} else {
+				for _, kb := range knownBuckets {
+					bk, err := dbase.GetBucket(kb.id)
+					if err != nil {
+						return err
+					}
+					it := db.NewIterator(bk, nil)
+					for it.Next() {
+						stats[kb.name].add(it.Key(), it.Value())
+					}
+					err = it.Error()
+					it.Release()
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			var result []*bucketStat
+			total := &bucketStat{Bucket: "total"}
+			for _, kb := range knownBuckets {
+				result = append(result, stats[kb.name])
+			}
+			if s, ok := stats["unknown"]; ok {
+				result = append(result, s)
+			}
+			for _, s := range result {
+				total.Count += s.Count
+				total.KeyBytes += s.KeyBytes
+				total.ValueBytes += s.ValueBytes
+			}
+			result = append(result, total)
+			return JsonPrettyPrintln(os.Stdout, result)
+		},
+	}
+}
+
+func newDatabaseConvertCmd(c string, params *databaseParams, encrypt bool) *cobra.Command {
+	var short string
+	if encrypt {
+		short = "Convert plain database to encrypted one"
+	} else {
+		short = "Convert encrypted database to plain one"
+	}
+	cmd := &cobra.Command{
+		Use:   c + " SRC_DIR DST_DIR",
+		Short: short,
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(2)),
+	}
+	flags := cmd.Flags()
+	keys := flags.Bool("keys", false, "Keys are encrypted as well as values (+"+db.KeyEncryptionModifier+")")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		backend := db.BackendType(params.dbType)
+		if err := checkSharedKeySpaceBackend(backend); err != nil {
+			return err
+		}
+		if len(params.keyStore) == 0 {
+			return errors.IllegalArgumentError.New("NoKeyStore")
+		}
+		if _, err := os.Stat(args[1]); err == nil {
+			return errors.IllegalArgumentError.Errorf("DestinationExists(dir=%s)", args[1])
+		}
+		secret, err := chain.LoadDBSecret(params.keyStore, params.keySecret)
+		if err != nil {
+			return err
+		}
+		if err := params.checkDir(args[0]); err != nil {
+			return err
+		}
+		src, err := db.OpenReadOnly(args[0], params.dbType, params.name)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := db.Open(args[1], params.dbType, params.name)
+		if err != nil {
+			return err
+		}
+		defer dst.Close()
+
+		if err := db.ConvertEncryption(dst, src, secret, *keys, encrypt); err != nil {
+			return err
+		}
+		modifier := db.EncryptionModifier
+		if *keys {
+			modifier = db.KeyEncryptionModifier
+		}
+		if encrypt {
+			fmt.Printf("Converted %s ==> %s (db_type=%s%s%s)\n",
+				args[0], args[1], params.dbType, db.TypeModifierSeparator, modifier)
+		} else {
+			fmt.Printf("Converted %s ==> %s (db_type=%s)\n",
+				args[0], args[1], params.dbType)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func NewDatabaseCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Database manipulation",
+		Long: "Inspect or convert the database of the chain.\n" +
+			"The chain should be stopped while it's used.",
+	}
+	params := new(databaseParams)
+	pflags := cmd.PersistentFlags()
+	pflags.StringVar(&params.dbType, "db_type", string(db.GoLevelDBBackend),
+		"Type of the database(goleveldb, pebble or rocksdb with optional modifiers, ex. goleveldb+enc)")
+	pflags.StringVarP(&params.name, "name", "n", "", "Name of the database (hex string of NID for chains, empty for DB_DIR itself)")
+	pflags.StringVarP(&params.keyStore, "key_store", "k", "", "KeyStore file for database encryption")
+	pflags.StringVarP(&params.keySecret, "key_secret", "s", "", "Secret (password) file for the KeyStore")
+
+	cmd.AddCommand(
+		newDatabaseBucketsCmd("buckets"),
+		newDatabaseGetCmd("get", params),
+		newDatabaseScanCmd("scan", params),
+		newDatabaseBlockCmd("block", params),
+		newDatabaseTxCmd("tx", params),
+		newDatabasePropertyCmd("property", params),
+		newDatabaseStatsCmd("stats", params),
+		newDatabaseStateDiffCmd("statediff", params),
+		newDatabaseConvertCmd("encrypt", params, true),
+		newDatabaseConvertCmd("decrypt", params, false),
+	)
+	return cmd
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+)
+
+func TestBucketOfRawKey(t *testing.T) {
+	value := []byte("trie node")
+	hash := crypto.SHA3Sum256(value)
+
+	// trie node
+	assert.Equal(t, "merkle", bucketOfRawKey(hash, value))
+
+	// trie node colliding with the prefix of a bucket
+	hash[0] = db.ChainProperty[0]
+	assert.Equal(t, "property", bucketOfRawKey(hash, value))
+	for {
+		value = append(value, '.')
+		if hash = crypto.SHA3Sum256(value); hash[0] == db.ChainProperty[0] {
+			break
+		}
+	}
+	assert.Equal(t, "merkle", bucketOfRawKey(hash, value))
+
+	// 31 bytes key of a bucket without declared key size
+	key := append([]byte(db.ChainProperty), bytes.Repeat([]byte{'k'}, 31)...)
+	assert.Equal(t, "property", bucketOfRawKey(key, []byte("value")))
+
+	// bucket with declared key size
+	key = append([]byte(db.BytesByHash), hash...)
+	assert.Equal(t, "bytes", bucketOfRawKey(key, value))
+	assert.Equal(t, "unknown", bucketOfRawKey(key[:crypto.HashLen-1], value))
+
+	assert.Equal(t, "unknown", bucketOfRawKey([]byte("?unknown"), nil))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/icon-project/goloop/client"
+	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	v3 "github.com/icon-project/goloop/server/v3"
 )
@@ -68,5 +69,37 @@ func NewDebugCmd(parentCmd *cobra.Command, parentVc *viper.Viper) (*cobra.Comman
 	}
 	rootCmd.AddCommand(traceCmd)
 
+	stateDiffCmd := &cobra.Command{
+		Use:   "statediff FROM [TO]",
+		Short: "Get changed accounts between world states of the heights (default TO: last height)",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, err := intconv.ParseInt(args[0], 64)
+			if err != nil {
+				return err
+			}
+			param := &v3.StateDiffParam{
+				From: jsonrpc.HexInt(intconv.FormatInt(from)),
+			}
+			if len(args) > 1 {
+				to, err := intconv.ParseInt(args[1], 64)
+				if err != nil {
+					return err
+				}
+				param.To = jsonrpc.HexInt(intconv.FormatInt(to))
+			}
+			if storage, _ := cmd.Flags().GetBool("storage"); storage {
+				param.Storage = "0x1"
+			}
+			diff, err := debugClient.Do("debug_getStateDiff", param, nil)
+			if err != nil {
+				return err
+			}
+			return JsonPrettyPrintln(os.Stdout, diff.Result)
+		},
+	}
+	stateDiffCmd.Flags().Bool("storage", false, "Include changed entries of storages")
+	rootCmd.AddCommand(stateDiffCmd)
+
 	return rootCmd, vc
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/icon-project/goloop/common/wallet"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/node"
+	"github.com/icon-project/goloop/signer"
 )
 
 type ServerConfig struct {
@@ -27,6 +28,9 @@ type ServerConfig struct {
 	KeyPlugin     string            `json:"key_plugin,omitempty"`
 	KeyPlgOptions map[string]string `json:"key_plugin_options,omitempty"`
 
+	KeySigner        string            `json:"key_signer,omitempty"`
+	KeySignerOptions map[string]string `json:"key_signer_options,omitempty"`
+
 	Wallet module.Wallet `json:"-"`
 
 	LogLevel     string               `json:"log_level"`
@@ -51,6 +55,14 @@ func (cfg *ServerConfig) MakesureWallet(gen bool) error {
 	if cfg.Wallet != nil {
 		return nil
 	}
+	if cfg.KeySigner != "" {
+		if w, err := signer.NewWallet(cfg.KeySigner, cfg.KeySignerOptions); err != nil {
+			return err
+		} else {
+			cfg.Wallet = w
+			return nil
+		}
+	}
 	if cfg.KeyPlugin != "" {
 		options := make(map[string]string)
 		for k, v := range cfg.KeyPlgOptions {
@@ -149,12 +161,16 @@ func NewServerCmd(parentCmd *cobra.Command, parentVc *viper.Viper, version, buil
 		"Node Command Line Interface socket path (default: [node_dir]/cli.sock)")
 	rootPFlags.String("backup_dir", "",
 		"Node backup directory (default: [node_dir]/backup")
+	rootPFlags.String("slashing_protection", "",
+		"Slashing protection file (default: [node_dir]/slashing_protection.json)")
 	rootPFlags.StringP("config", "c", "", "Parsing configuration file")
 	//
 	rootPFlags.String("key_store", "", "KeyStore file for wallet")
 	rootPFlags.String("key_secret", "", "Secret (password) file for KeyStore")
 	rootPFlags.String("key_plugin", "", "KeyPlugin file for wallet")
 	rootPFlags.StringToString("key_plugin_options", nil, "KeyPlugin options")
+	rootPFlags.String("key_signer", "", "Endpoint of remote signer for wallet (unix://<path> or <host>:<port>)")
+	rootPFlags.StringToString("key_signer_options", nil, "Remote signer options (auth_key, tls_cert, tls_key, tls_ca, timeout)")
 	//
 	rootPFlags.String("log_forwarder_vendor", "", "LogForwarder vendor (fluentd,logstash)")
 	rootPFlags.String("log_forwarder_address", "", "LogForwarder address")
@@ -305,6 +321,7 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	cliSocket := vc.GetString("node_sock")
 	eeSocket := vc.GetString("ee_socket")
 	backupDir := vc.GetString("backup_dir")
+	slashingProtection := vc.GetString("slashing_protection")
 	lwFilename := vc.GetString("log_writer_filename")
 
 	if cfgFilePath != "" {
@@ -400,6 +417,9 @@ func MergeWithViper(vc *viper.Viper, cfg *ServerConfig) error {
 	if backupDir != "" {
 		cfg.BackupDir = cfg.ResolveRelative(backupDir)
 	}
+	if slashingProtection != "" {
+		cfg.SlashingProtection = cfg.ResolveRelative(slashingProtection)
+	}
 
 	//config.KeyStorePass
 	//overwrite env.KeyStorePass
@@ -0,0 +1,344 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+)
+
+// walIDsOf returns ids of WALs given by arguments. It returns all WALs
+// of the consensus if there is no argument.
+func walIDsOf(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return consensus.WALIDs, nil
+	}
+	for _, arg := range args {
+		if err := checkWALID(arg); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func checkWALID(id string) error {
+	for _, wid := range consensus.WALIDs {
+		if wid == id {
+			return nil
+		}
+	}
+	return errors.IllegalArgumentError.Errorf("InvalidWALID(id=%s)", id)
+}
+
+func checkWALDir(dir string) error {
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return errors.IllegalArgumentError.Errorf("InvalidWALDir(dir=%s)", dir)
+	}
+	return nil
+}
+
+func printWALRecord(id string, rec *consensus.WALRecord) {
+	prefix := fmt.Sprintf("%s #%d @%d", id, rec.Index, rec.Offset)
+	if rec.DecodeError != nil {
+		fmt.Printf("%s InvalidMessage(err=%v)\n", prefix, rec.DecodeError)
+		return
+	}
+	switch m := rec.Message.(type) {
+	case *consensus.VoteListMessage:
+		fmt.Printf("%s VoteListMessage{N:%d}\n", prefix, m.VoteList.Len())
+		for i := 0; i < m.VoteList.Len(); i++ {
+			fmt.Printf("  %v\n", m.VoteList.Get(i))
+		}
+	default:
+		fmt.Printf("%s %v\n", prefix, m)
+	}
+}
+
+func newWALDumpCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c + " WAL_DIR [WAL_ID...]",
+		Short: "Dump records of WALs as decoded messages",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+	}
+	height := cmd.Flags().Int64("height", 0, "Show messages of the height only (0 for all)")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkWALDir(args[0]); err != nil {
+			return err
+		}
+		ids, err := walIDsOf(args[1:])
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			cnt, err := consensus.InspectWAL(path.Join(args[0], id), func(rec *consensus.WALRecord) bool {
+				if *height == 0 || rec.DecodeError != nil || walRecordHeight(rec) == *height {
+					printWALRecord(id, rec)
+				}
+				return true
+			})
+			if consensus.IsNotExist(err) {
+				fmt.Printf("%s NoWAL\n", id)
+			} else if consensus.IsCorruptedWAL(err) || consensus.IsUnexpectedEOF(err) {
+				fmt.Printf("%s #%d BrokenRecord(err=%s)\n", id, cnt, brokenReasonOf(err))
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return cmd
+}
+
+func walRecordHeight(rec *consensus.WALRecord) int64 {
+	switch m := rec.Message.(type) {
+	case *consensus.ProposalMessage:
+		return m.Height
+	case *consensus.BlockPartMessage:
+		return m.Height
+	case *consensus.VoteMessage:
+		return m.Height
+	case *consensus.RoundStateMessage:
+		return m.Height
+	case *consensus.VoteListMessage:
+		if m.VoteList.Len() > 0 {
+			return m.VoteList.Get(0).Height
+		}
+	}
+	return -1
+}
+
+// brokenReasonOf returns the reason of the broken record without
+// its payload, which may be too long to show.
+func brokenReasonOf(err error) string {
+	if consensus.IsUnexpectedEOF(err) {
+		return "UnexpectedEOF"
+	}
+	return "BadChecksum"
+}
+
+type walStatus struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Records  int    `json:"records"`
+	Size     int64  `json:"size"`
+	Error    string `json:"error,omitempty"`
+	Repaired bool   `json:"repaired,omitempty"`
+}
+
+const (
+	walStatusOK     = "ok"
+	walStatusNoWAL  = "none"
+	walStatusBroken = "broken"
+)
+
+func newWALCheckCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c + " WAL_DIR [WAL_ID...]",
+		Short: "Validate checksums of records of WALs",
+		Args:  ArgsWithDefaultErrorFunc(cobra.MinimumNArgs(1)),
+	}
+	repair := cmd.Flags().Bool("repair", false, "Truncate WALs at the first broken record")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkWALDir(args[0]); err != nil {
+			return err
+		}
+		ids, err := walIDsOf(args[1:])
+		if err != nil {
+			return err
+		}
+		var result []*walStatus
+		for _, id := range ids {
+			s := &walStatus{ID: id, Status: walStatusOK}
+			walID := path.Join(args[0], id)
+			cnt, err := consensus.InspectWAL(walID, func(rec *consensus.WALRecord) bool {
+				s.Size = rec.Offset + int64(rec.Size)
+				return true
+			})
+			s.Records = cnt
+			if consensus.IsNotExist(err) {
+				s.Status = walStatusNoWAL
+			} else if consensus.IsCorruptedWAL(err) || consensus.IsUnexpectedEOF(err) {
+				s.Status = walStatusBroken
+				s.Error = brokenReasonOf(err)
+				if *repair {
+					if s.Repaired, err = consensus.RepairWAL(walID); err != nil {
+						return err
+					}
+				}
+			} else if err != nil {
+				return err
+			}
+			result = append(result, s)
+		}
+		return JsonPrettyPrintln(os.Stdout, result)
+	}
+	return cmd
+}
+
+func newWALTruncateCmd(c string) *cobra.Command {
+	return &cobra.Command{
+		Use:   c + " WAL_DIR WAL_ID COUNT",
+		Short: "Truncate the WAL keeping the first COUNT records",
+		Args:  ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkWALDir(args[0]); err != nil {
+				return err
+			}
+			if err := checkWALID(args[1]); err != nil {
+				return err
+			}
+			cnt, err := strconv.Atoi(args[2])
+			if err != nil || cnt < 0 {
+				return errors.IllegalArgumentError.Errorf("InvalidCount(count=%s)", args[2])
+			}
+			if err := consensus.TruncateWAL(path.Join(args[0], args[1]), cnt); err != nil {
+				return err
+			}
+			fmt.Printf("Truncated %s to %d records\n", args[1], cnt)
+			return nil
+		},
+	}
+}
+
+func newWALRebuildCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c + " WAL_DIR DB_DIR HEIGHT",
+		Short: "Rebuild the commit WAL with the votes for HEIGHT in the database",
+		Long: "Rebuild the commit WAL with the votes for HEIGHT in the database.\n" +
+			"Votes for HEIGHT are stored in the next block, so HEIGHT should be\n" +
+			"less than the last height. The consensus uses the votes only if the\n" +
+			"chain resumes at HEIGHT, so use --reset_db to set the last height of\n" +
+			"the database to HEIGHT.",
+		Args: ArgsWithDefaultErrorFunc(cobra.ExactArgs(3)),
+	}
+	params := new(databaseParams)
+	flags := cmd.Flags()
+	flags.StringVar(&params.dbType, "db_type", string(db.GoLevelDBBackend),
+		"Type of the database(backend with optional modifiers, ex. goleveldb+enc)")
+	flags.StringVarP(&params.name, "name", "n", "", "Name of the database (hex string of NID for chains, empty for DB_DIR itself)")
+	flags.StringVarP(&params.keyStore, "key_store", "k", "", "KeyStore file for database encryption")
+	flags.StringVarP(&params.keySecret, "key_secret", "s", "", "Secret (password) file for the KeyStore")
+	resetDB := flags.Bool("reset_db", false, "Set the last height of the database to HEIGHT")
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		height, err := strconv.ParseInt(args[2], 0, 64)
+		if err != nil {
+			return errors.IllegalArgumentError.Wrapf(err, "InvalidHeight(height=%s)", args[2])
+		}
+		if err := checkWALDir(args[0]); err != nil {
+			return err
+		}
+		var dbase db.Database
+		if *resetDB {
+			dbase, err = params.openForWrite(args[1])
+		} else {
+			dbase, err = params.open(args[1])
+		}
+		if err != nil {
+			return err
+		}
+		defer dbase.Close()
+
+		last, err := block.GetLastHeight(dbase)
+		if err != nil {
+			return err
+		}
+		if height <= 0 || height >= last {
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidHeight(height=%d,last=%d)", height, last)
+		}
+		ver, err := block.GetBlockVersion(dbase, nil, height)
+		if err != nil {
+			return err
+		}
+		if ver <= module.BlockVersion1 {
+			return errors.UnsupportedError.Errorf(
+				"UnsupportedBlockVersion(version=%d,height=%d)", ver, height)
+		}
+		vlBytes, err := walRecordBytesForHeight(dbase, height)
+		if err != nil {
+			return err
+		}
+		// The database is reset after the WAL, so that the command can be
+		// run again if it fails to reset the database.
+		if err := consensus.RebuildCommitWAL(args[0], vlBytes); err != nil {
+			return err
+		}
+		fmt.Printf("Rebuilt commit WAL for height=%d\n", height)
+		if *resetDB {
+			if err := block.ResetDB(dbase, nil, height); err != nil {
+				return errors.Wrapf(err, "fail to reset database (run it again)")
+			}
+			fmt.Printf("Reset last height %d ==> %d\n", last, height)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func walRecordBytesForHeight(dbase db.Database, height int64) ([]byte, error) {
+	bid, err := block.GetBlockHeaderHashByHeight(dbase, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	cvlBytes, err := block.GetCommitVoteListBytesForHeight(dbase, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	result, err := block.GetBlockResultByHeight(dbase, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	bd, err := block.GetBTPDigestFromResult(dbase, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	vl, err := block.GetNextValidatorsByHeight(dbase, nil, height)
+	if err != nil {
+		return nil, err
+	}
+	return consensus.WALRecordBytesFromCommitVoteListBytes(
+		cvlBytes, height, bid, result, vl, bd, dbase, codec.BC,
+	)
+}
+
+func NewWALCmd(c string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   c,
+		Short: "Consensus WAL inspection and repair",
+		Long: "Inspect or repair WALs(round, lock and commit) of the consensus.\n" +
+			"WAL_DIR is the wal directory of the chain.\n" +
+			"The chain should be stopped while it's used.",
+	}
+	cmd.AddCommand(
+		newWALDumpCmd("dump"),
+		newWALCheckCmd("check"),
+		newWALTruncateCmd("truncate"),
+		newWALRebuildCmd("rebuild"),
+	)
+	return cmd
+}
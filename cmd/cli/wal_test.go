@@ -0,0 +1,80 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cli
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/test"
+)
+
+func runWALRebuild(args ...string) error {
+	cmd := newWALRebuildCmd("rebuild")
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func TestWALRebuild_ResetDB(t *testing.T) {
+	dbDir := t.TempDir()
+	walDir := t.TempDir()
+	const dbName = "test"
+
+	dbase, err := db.Open(dbDir, string(db.GoLevelDBBackend), dbName)
+	assert.NoError(t, err)
+	nd := test.NewNode(t, test.UseDB(dbase))
+	nd.ProposeFinalizeBlockWithTX(
+		consensus.NewEmptyCommitVoteList(),
+		test.NewTx().SetValidatorsNode(nd).String(),
+	)
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd.ProposeFinalizeBlock(consensus.NewEmptyCommitVoteList())
+	nd.ProposeFinalizeBlock(nd.NewVoteListForLastBlock())
+	nd.ProposeFinalizeBlock(nd.NewVoteListForLastBlock())
+	nd.Close()
+
+	// the database in use can't be reset, and the WAL is kept
+	err = runWALRebuild(walDir, dbDir, "4", "--name", dbName, "--reset_db")
+	assert.Error(t, err)
+	_, err = consensus.InspectWAL(path.Join(walDir, "commit"), nil)
+	assert.True(t, consensus.IsNotExist(err))
+	assert.NoError(t, dbase.Close())
+
+	// the last block should be kept for the votes
+	err = runWALRebuild(walDir, dbDir, "5", "--name", dbName, "--reset_db")
+	assert.Error(t, err)
+
+	err = runWALRebuild(walDir, dbDir, "4", "--name", dbName, "--reset_db")
+	assert.NoError(t, err)
+
+	cnt, err := consensus.InspectWAL(path.Join(walDir, "commit"), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cnt)
+
+	dbase, err = db.OpenReadOnly(dbDir, string(db.GoLevelDBBackend), dbName)
+	assert.NoError(t, err)
+	defer dbase.Close()
+	last, err := block.GetLastHeight(dbase)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, last)
+}
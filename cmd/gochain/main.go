@@ -21,6 +21,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
 	"github.com/icon-project/goloop/server/metric"
@@ -40,12 +41,16 @@ type GoChainConfig struct {
 	RPCDump       bool   `json:"rpc_dump"`
 	RPCDebug      bool   `json:"rpc_debug"`
 	RPCRosetta    bool   `json:"rpc_rosetta"`
+	RPCEth        bool   `json:"rpc_eth"`
+	RPCGraphQL    bool   `json:"rpc_graphql"`
 	DisableRPC    bool   `json:"disable_rpc"`
 	RPCBatchLimit int    `json:"rpc_batch_limit,omitempty"`
 	EEInstances   int    `json:"ee_instances"`
 	Engines       string `json:"engines"`
 	WSMaxSession  int    `json:"ws_max_session"`
 
+	RPCRateLimit *server.RateLimitConfig `json:"rpc_rate_limit,omitempty"`
+
 	Key          []byte          `json:"key,omitempty"`
 	KeyStoreData json.RawMessage `json:"key_store"`
 	KeyStorePass string          `json:"key_password"`
@@ -91,6 +96,7 @@ var saveFile, saveKeyStore string
 var cfg GoChainConfig
 var cpuProfile, memProfile, blockProfile string
 var chainDir string
+var slashingProtection string
 var eeSocket string
 var modLevels map[string]string
 var lfCfg log.ForwarderConfig
@@ -117,12 +123,16 @@ func main() {
 	flag.BoolVar(&cfg.RPCDump, "rpc_dump", false, "JSON-RPC Request, Response Dump flag")
 	flag.BoolVar(&cfg.RPCDebug, "rpc_debug", false, "JSON-RPC Debug enable")
 	flag.BoolVar(&cfg.RPCRosetta, "rpc_rosetta", false, "JSON-RPC Rosetta enable")
+	flag.BoolVar(&cfg.RPCEth, "rpc_eth", false, "JSON-RPC Ethereum compatible API enable")
+	flag.BoolVar(&cfg.RPCGraphQL, "rpc_graphql", false, "GraphQL API enable")
 	flag.BoolVar(&cfg.DisableRPC, "disable_rpc", false, "disable JSON-RPC API")
 	flag.IntVar(&cfg.RPCBatchLimit, "rpc_batch_limit", 10, "JSON-RPC batch limit")
 	flag.StringVar(&cfg.SeedAddr, "seed", "", "Ip-port of Seed")
 	flag.StringVar(&genesisStorage, "genesis_storage", "", "Genesis storage path")
 	flag.StringVar(&genesisPath, "genesis", "", "Genesis template directory or file")
 	flag.StringVar(&cfg.DBType, "db_type", "goleveldb", fmt.Sprintf("Name of database system (%s)", strings.Join(db.GetSupportedTypes(), ", ")))
+	flag.StringVar(&cfg.DBKeyStore, "db_key_store", "", "KeyStore file for database encryption")
+	flag.StringVar(&cfg.DBKeySecret, "db_key_secret", "", "Secret (password) file for db_key_store")
 	flag.StringVar(&cfg.Platform, "platform", "", "Name of service platform (default: \"\")")
 	flag.UintVar(&cfg.Role, "role", 2, "[0:None, 1:Seed, 2:Validator, 3:Both]")
 	flag.StringVarP(&eeSocket, "ee_socket", "s", "", "Execution engine socket path (default: .chain/<address>/ee.sock)")
@@ -133,6 +143,7 @@ func main() {
 	flag.StringVar(&memProfile, "memprofile", "", "Memory Profiling data file")
 	flag.StringVar(&blockProfile, "blockprofile", "", "Memory Profiling data file")
 	flag.StringVar(&chainDir, "chain_dir", "", "Chain data directory (default: .chain/<address>/<nid>)")
+	flag.StringVar(&slashingProtection, "slashing_protection", "", "Slashing protection file (disabled if empty)")
 	flag.IntVar(&cfg.EEInstances, "ee_instances", 1, "Number of execution engines")
 	flag.IntVar(&cfg.ConcurrencyLevel, "concurrency", 1, "Maximum number of executors to be used for concurrency")
 	flag.IntVar(&cfg.NormalTxPoolSize, "normal_tx_pool", 0, "Normal transaction pool size")
@@ -140,6 +151,8 @@ func main() {
 	flag.IntVar(&cfg.MaxBlockTxBytes, "max_block_tx_bytes", 0, "Maximum size of transactions in a block")
 	flag.StringVar(&cfg.NodeCache, "node_cache", chain.NodeCacheDefault, "Node cache (none,small,large)")
 	flag.BoolVar(&cfg.ValidateTxOnSend, "validate_tx_on_send", false, "Validate transaction on send")
+	flag.BoolVar(&cfg.DBMetrics, "db_metrics", false, "Collect metrics of database operations per bucket")
+	flag.BoolVar(&cfg.TxIndex, "tx_index", false, "Maintain index of transactions by addresses")
 	cfg.ChildrenLimit = flag.Int("children_limit", -1, "Maximum number of child connections (-1: uses system default value)")
 	cfg.NephewsLimit = flag.Int("nephews_limit", -1, "Maximum number of nephew connections (-1: uses system default value)")
 	flag.StringVar(&cfg.LogLevel, "log_level", "debug", "Main log level")
@@ -497,12 +510,22 @@ func Execute(cmd *cobra.Command, args []string) {
 		JSONRPCDump:         cfg.RPCDump,
 		JSONRPCIncludeDebug: cfg.RPCDebug,
 		JSONRPCRosetta:      cfg.RPCRosetta,
+		JSONRPCEth:          cfg.RPCEth,
+		GraphQL:             cfg.RPCGraphQL,
 		JSONRPCBatchLimit:   cfg.RPCBatchLimit,
 		DisableRPC:          cfg.DisableRPC,
 		WSMaxSession:        cfg.WSMaxSession,
+		RateLimit:           cfg.RPCRateLimit,
 	}
 	srv := server.NewManager(config, wallet, logger)
 	hex.EncodeToString(wallet.Address().ID())
+	if slashingProtection != "" {
+		sp, err := consensus.OpenSlashingProtection(slashingProtection)
+		if err != nil {
+			log.Panicf("FAIL to open slashing protection err=%+v", err)
+		}
+		cfg.SlashingProtection = sp
+	}
 	c := chain.NewChain(wallet, nt, srv, pm, logger, &cfg.Config)
 	err = c.Init()
 	if err != nil {
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// signer is a reference implementation of the remote signer serving the key
+// in a keystore file. Validators may use it with "key_signer" of the server
+// configuration.
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/signer"
+)
+
+var (
+	keyStorePath   string
+	keyStorePass   string
+	keyStoreSecret string
+	listenEPs      []string
+	authKeyPath    string
+	tlsCert        string
+	tlsKey         string
+	tlsCA          string
+	statePath      string
+	allowSign      bool
+	logLevel       string
+)
+
+func er(msg interface{}) {
+	_, _ = fmt.Fprintln(os.Stderr, "Error:", msg)
+	os.Exit(1)
+}
+
+func readPassword() ([]byte, error) {
+	if keyStoreSecret != "" {
+		bs, err := os.ReadFile(keyStoreSecret)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strings.TrimSpace(string(bs))), nil
+	}
+	if keyStorePass != "" {
+		return []byte(keyStorePass), nil
+	}
+	return nil, errors.New("keystore password is required")
+}
+
+func run() error {
+	logger := log.New()
+	if lv, err := log.ParseLevel(logLevel); err != nil {
+		return errors.Errorf("invalid log level=%s", logLevel)
+	} else {
+		logger.SetLevel(lv)
+		logger.SetConsoleLevel(lv)
+	}
+
+	ks, err := os.ReadFile(keyStorePath)
+	if err != nil {
+		return errors.Wrapf(err, "fail to read keystore file=%s", keyStorePath)
+	}
+	pw, err := readPassword()
+	if err != nil {
+		return err
+	}
+	w, err := wallet.NewFromKeyStore(ks, pw)
+	if err != nil {
+		return errors.Wrap(err, "fail to decrypt keystore")
+	}
+
+	cfg := &signer.ServerConfig{StatePath: statePath, AllowSign: allowSign}
+	if authKeyPath != "" {
+		if cfg.AuthKey, err = signer.ReadAuthKey(authKeyPath); err != nil {
+			return err
+		}
+	}
+	srv, err := signer.NewServer(w, cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	if len(listenEPs) == 0 {
+		return errors.New("no endpoint to listen")
+	}
+	errCh := make(chan error, len(listenEPs))
+	for _, ep := range listenEPs {
+		network, _, err := signer.ParseEndpoint(ep)
+		if err != nil {
+			return err
+		}
+		var tc *tls.Config
+		if network == "tcp" {
+			if tc, err = signer.LoadTLSConfig(tlsCert, tlsKey, tlsCA, true); err != nil {
+				return err
+			}
+		}
+		l, err := signer.Listen(ep, tc)
+		if err != nil {
+			return err
+		}
+		go func() { errCh <- srv.Serve(l) }()
+		logger.Infof("listening endpoint=%s address=%s", ep, w.Address())
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-sigCh:
+		return srv.Close()
+	case err := <-errCh:
+		srv.Close()
+		return err
+	}
+}
+
+func main() {
+	rootCmd := &cobra.Command{
+		Use:   os.Args[0],
+		Short: "Remote signer for validator keys",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := run(); err != nil {
+				er(err)
+			}
+		},
+	}
+
+	flag := rootCmd.PersistentFlags()
+	flag.StringVar(&keyStorePath, "key_store", "", "KeyStore file for the key")
+	flag.StringVar(&keyStorePass, "key_password", "", "Password for the KeyStore file")
+	flag.StringVar(&keyStoreSecret, "key_secret", "", "Secret (password) file for the KeyStore file")
+	flag.StringArrayVar(&listenEPs, "listen", nil,
+		"Endpoint to listen (unix://<path> or <host>:<port>)")
+	flag.StringVar(&authKeyPath, "auth_key", "", "Authentication key file (required for unix socket)")
+	flag.StringVar(&tlsCert, "tls_cert", "", "TLS certificate file (required for TCP)")
+	flag.StringVar(&tlsKey, "tls_key", "", "TLS private key file (required for TCP)")
+	flag.StringVar(&tlsCA, "tls_ca", "", "CA certificate file for verifying clients (required for TCP)")
+	flag.StringVar(&statePath, "state", "signer_state.json", "File storing the last signed consensus messages")
+	flag.BoolVar(&allowSign, "allow_sign", false,
+		"Allow signing any hash for the key not used for consensus (consensus messages are refused)")
+	flag.StringVar(&logLevel, "log_level", "info", "Log level")
+	_ = rootCmd.MarkPersistentFlagRequired("key_store")
+	if err := rootCmd.Execute(); err != nil {
+		er(err)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+// Batch collects write operations over buckets to write them at once.
+// Operations are applied in the order of calls. Write doesn't reset the
+// batch, so call Reset to reuse it.
+type Batch interface {
+	Set(id BucketID, key, value []byte)
+	Delete(id BucketID, key []byte)
+	Len() int
+	Reset()
+
+	// Write applies the collected operations to the database. If the
+	// database supports it, they are applied atomically.
+	Write() error
+}
+
+// Batcher is implemented by the databases supporting atomic write batch.
+type Batcher interface {
+	NewBatch() Batch
+}
+
+// NewBatch returns a new batch for the database. If the database doesn't
+// support atomic write batch, then the batch applies operations one by one.
+func NewBatch(database Database) Batch {
+	if b, ok := database.(Batcher); ok {
+		return b.NewBatch()
+	}
+	return &sequentialBatch{database: database}
+}
+
+type batchOp struct {
+	id      BucketID
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+type batchOps struct {
+	ops []batchOp
+}
+
+func (b *batchOps) Set(id BucketID, key, value []byte) {
+	b.ops = append(b.ops, batchOp{
+		id:    id,
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	})
+}
+
+func (b *batchOps) Delete(id BucketID, key []byte) {
+	b.ops = append(b.ops, batchOp{
+		id:      id,
+		key:     append([]byte{}, key...),
+		deleted: true,
+	})
+}
+
+func (b *batchOps) Len() int {
+	return len(b.ops)
+}
+
+func (b *batchOps) Reset() {
+	b.ops = nil
+}
+
+// sequentialBatch applies operations through buckets of the database.
+// It's used for the databases without atomic write batch.
+type sequentialBatch struct {
+	batchOps
+	database Database
+}
+
+func (b *sequentialBatch) Write() error {
+	for _, op := range b.ops {
+		bk, err := b.database.GetBucket(op.id)
+		if err != nil {
+			return err
+		}
+		if op.deleted {
+			err = bk.Delete(op.key)
+		} else {
+			err = bk.Set(op.key, op.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -46,6 +46,26 @@ func (bk BucketID) Hasher() Hasher {
 	return hasherMap[bk]
 }
 
+var keySizeMap = map[BucketID]int{
+	BytesByHash:              crypto.HashLen,
+	TransactionLocatorByHash: crypto.HashLen,
+}
+
+// RegisterKeySize declares the size of the keys of the bucket. Iterators
+// of the bucket return only the entries having the key of the size.
+func RegisterKeySize(bk BucketID, size int) {
+	if _, ok := keySizeMap[bk]; ok {
+		panic("Duplicate BucketID")
+	}
+	keySizeMap[bk] = size
+}
+
+// KeySize returns the declared size of the keys of the bucket. It returns
+// zero if the keys don't have fixed size.
+func (bk BucketID) KeySize() int {
+	return keySizeMap[bk]
+}
+
 //	Bucket ID
 const (
 	// MerkleTrie maps RLP encoded data from sha3(data)
@@ -57,6 +77,11 @@ const (
 	// TransactionLocatorByHash maps transaction locator from transaction hash.
 	TransactionLocatorByHash BucketID = "T"
 
+	// TransactionLocatorByAddress maps transaction hash from address and
+	// position of the transaction. It's maintained only for the chains
+	// enabling transaction index.
+	TransactionLocatorByAddress BucketID = "A"
+
 	// BlockHeaderHashByHeight maps hash of encoded block header from height.
 	BlockHeaderHashByHeight BucketID = "H"
 
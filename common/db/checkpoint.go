@@ -0,0 +1,38 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Checkpointer is implemented by the databases which can make a consistent
+// point-in-time copy of themselves while they are in use.
+type Checkpointer interface {
+	// Checkpoint makes a copy of the database, which can be opened with
+	// the same backend type, dir and name. The copy must not exist.
+	Checkpoint(dir, name string) error
+}
+
+// Checkpoint makes a point-in-time copy of the database. It returns
+// UnsupportedError if the database doesn't support it.
+func Checkpoint(database Database, dir, name string) error {
+	if cp, ok := database.(Checkpointer); ok {
+		return cp.Checkpoint(dir, name)
+	}
+	return errors.UnsupportedError.Errorf("CheckpointNotSupported(db=%T)", database)
+}
@@ -75,6 +75,10 @@ func (c *databaseContext) Flags() Flags {
 	return c.flags.Clone()
 }
 
+func (c *databaseContext) NewBatch() Batch {
+	return NewBatch(c.Database)
+}
+
 func WithFlags(database Database, flags Flags) Context {
 	if database == nil {
 		return nil
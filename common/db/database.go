@@ -2,6 +2,7 @@ package db
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/icon-project/goloop/common/errors"
 )
@@ -31,6 +32,14 @@ func registerDBCreator(backend BackendType, creator dbCreator, force bool) {
 	backends[backend] = creator
 }
 
+var readOnlyBackends = map[BackendType]dbCreator{}
+
+// registerReadOnlyDBCreator registers the creator opening an existing
+// database of the backend without modifying it.
+func registerReadOnlyDBCreator(backend BackendType, creator dbCreator) {
+	readOnlyBackends[backend] = creator
+}
+
 func RegisteredBackendTypes() []string {
 	l := make([]string, 0)
 	for k := range backends {
@@ -40,10 +49,43 @@ func RegisteredBackendTypes() []string {
 	return l
 }
 
+// TypeModifierSeparator separates modifiers from the backend type in
+// the database type. ex) "goleveldb+enc"
+const TypeModifierSeparator = "+"
+
+// SplitType returns the backend type and the modifiers of the database type.
+func SplitType(dbtype string) (BackendType, []string) {
+	items := strings.Split(dbtype, TypeModifierSeparator)
+	return BackendType(items[0]), items[1:]
+}
+
+// Open opens the database of the type. Types with modifiers should be
+// opened with their backend type, then modifiers should be applied with
+// WithModifiers.
 func Open(dir, dbtype, name string) (Database, error) {
+	if backend, modifiers := SplitType(dbtype); len(modifiers) > 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"ModifiersNotApplicable(backend=%s,modifiers=%v)", backend, modifiers)
+	}
 	return openDatabase(BackendType(dbtype), name, dir)
 }
 
+// OpenReadOnly opens the existing database of the type only for reading.
+// It fails if the database doesn't exist, and writes to the database
+// return an error. Like Open, modifiers aren't applicable.
+func OpenReadOnly(dir, dbtype, name string) (Database, error) {
+	backend, modifiers := SplitType(dbtype)
+	if len(modifiers) > 0 {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"ModifiersNotApplicable(backend=%s,modifiers=%v)", backend, modifiers)
+	}
+	dbCreator, ok := readOnlyBackends[backend]
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf("ReadOnlyNotSupported(type=%s)", backend)
+	}
+	return dbCreator(name, dir)
+}
+
 func openDatabase(backend BackendType, name string, dir string) (Database, error) {
 	dbCreator, ok := backends[backend]
 	if !ok {
@@ -75,8 +117,11 @@ func (e *errorBucket) Get(key []byte) ([]byte, error)     { return nil, e.error
 func (e *errorBucket) Has(key []byte) (bool, error)       { return false, e.error }
 func (e *errorBucket) Set(key []byte, value []byte) error { return e.error }
 func (e *errorBucket) Delete(key []byte) error            { return e.error }
+func (e *errorBucket) NewIterator(r *Range) Iterator      { return &errorIterator{e.error} }
 
 // BucketOf returns valid bucket always, but it
+// returns errors on any operations if it fails to get the bucket.
+// Use NewIterator with the returned bucket for iteration.
 func BucketOf(database Database, id BucketID) Bucket {
 	if bk, err := database.GetBucket(id); err != nil {
 		return &errorBucket{err}
@@ -17,9 +17,15 @@
 package db
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
 )
 
 func testDatabase_GetSetDelete(t *testing.T, creator dbCreator) {
@@ -258,3 +264,283 @@ func TestDatabase_OperationAfterClose(t *testing.T) {
 		})
 	}
 }
+
+func collectEntries(t *testing.T, it Iterator) [][2]string {
+	defer it.Release()
+	var entries [][2]string
+	for it.Next() {
+		entries = append(entries, [2]string{string(it.Key()), string(it.Value())})
+	}
+	assert.NoError(t, it.Error())
+	return entries
+}
+
+func testDatabase_Iterate(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk, err := testDB.GetBucket("B")
+	assert.NoError(t, err)
+	other, err := testDB.GetBucket("C")
+	assert.NoError(t, err)
+
+	for _, k := range []string{"b2", "a1", "b1", "c1", "b3"} {
+		assert.NoError(t, bk.Set([]byte(k), []byte("v"+k)))
+		assert.NoError(t, other.Set([]byte(k), []byte("o"+k)))
+	}
+	assert.NoError(t, bk.Set([]byte("e"), []byte{}))
+
+	entries := collectEntries(t, NewIterator(bk, nil))
+	assert.Equal(t, [][2]string{
+		{"a1", "va1"}, {"b1", "vb1"}, {"b2", "vb2"}, {"b3", "vb3"},
+		{"c1", "vc1"}, {"e", ""},
+	}, entries)
+
+	entries = collectEntries(t, NewPrefixIterator(bk, []byte("b")))
+	assert.Equal(t, [][2]string{
+		{"b1", "vb1"}, {"b2", "vb2"}, {"b3", "vb3"},
+	}, entries)
+
+	entries = collectEntries(t, NewIterator(bk, &Range{
+		Start: []byte("b2"),
+		Limit: []byte("c1"),
+	}))
+	assert.Equal(t, [][2]string{
+		{"b2", "vb2"}, {"b3", "vb3"},
+	}, entries)
+
+	entries = collectEntries(t, NewPrefixIterator(bk, []byte("x")))
+	assert.Empty(t, entries)
+}
+
+func testDatabase_IterateWithTrieNode(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	trie, err := testDB.GetBucket(MerkleTrie)
+	assert.NoError(t, err)
+
+	// trie node whose hash starts with the id of the bucket having
+	// declared key size
+	hash := crypto.SHA3Sum256([]byte("node"))
+	hash[0] = TransactionLocatorByHash[0]
+	assert.NoError(t, trie.Set(hash, []byte("node")))
+	bk, err := testDB.GetBucket(TransactionLocatorByHash)
+	assert.NoError(t, err)
+	txHash := crypto.SHA3Sum256([]byte("tx"))
+	assert.NoError(t, bk.Set(txHash, []byte("loc")))
+
+	entries := collectEntries(t, NewIterator(bk, nil))
+	assert.Equal(t, [][2]string{{string(txHash), "loc"}}, entries)
+	entries = collectEntries(t, NewPrefixIterator(bk, hash[1:2]))
+	assert.Empty(t, entries)
+
+	// 31 bytes key in the bucket without declared key size has the same
+	// length of internal key as trie nodes.
+	key := bytes.Repeat([]byte{0x01}, crypto.HashLen-len(ChainProperty))
+	cp, err := testDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, cp.Set(key, []byte("v1")))
+	entries = collectEntries(t, NewIterator(cp, nil))
+	assert.Equal(t, [][2]string{{string(key), "v1"}}, entries)
+	entries = collectEntries(t, NewPrefixIterator(cp, key[:1]))
+	assert.Equal(t, [][2]string{{string(key), "v1"}}, entries)
+
+	value, err := trie.Get(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("node"), value)
+}
+
+func TestDatabase_IterateWithTrieNode(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_IterateWithTrieNode(t, be)
+		})
+	}
+}
+
+func TestDatabase_Iterate(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Iterate(t, be)
+		})
+	}
+	t.Run("layerdb", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			origin := NewMapDB()
+			return NewLayerDB(origin), nil
+		}
+		testDatabase_Iterate(t, creator)
+	})
+}
+
+func TestBucketOf_Iterate(t *testing.T) {
+	testDB := NewMapDB()
+	bk := BucketOf(testDB, ChainProperty)
+	assert.NoError(t, bk.Set([]byte("key"), []byte("value")))
+	entries := collectEntries(t, NewIterator(bk, nil))
+	assert.Equal(t, [][2]string{{"key", "value"}}, entries)
+
+	it := NewIterator(&errorBucket{errors.New("TestError")}, nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Error())
+
+	it = NewIterator(&nullBucket{}, nil)
+	assert.False(t, it.Next())
+	assert.Error(t, it.Error())
+}
+
+func testDatabase_Batch(t *testing.T, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk1, err := testDB.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	bk2, err := testDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	assert.NoError(t, bk1.Set([]byte("k0"), []byte("v0")))
+
+	batch := NewBatch(testDB)
+	batch.Set(BytesByHash, []byte("k1"), []byte("v1"))
+	batch.Set(ChainProperty, []byte("k2"), []byte("v2"))
+	batch.Set(ChainProperty, []byte("k3"), nil)
+	batch.Delete(BytesByHash, []byte("k0"))
+	assert.Equal(t, 4, batch.Len())
+
+	// nothing is written before Write
+	has, err := bk1.Has([]byte("k1"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	assert.NoError(t, batch.Write())
+
+	value, err := bk1.Get([]byte("k1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v1"), value)
+	value, err = bk2.Get([]byte("k2"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v2"), value)
+	value, err = bk2.Get([]byte("k3"))
+	assert.NoError(t, err)
+	assert.NotNil(t, value)
+	assert.Zero(t, len(value))
+	has, err = bk1.Has([]byte("k0"))
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	batch.Reset()
+	assert.Equal(t, 0, batch.Len())
+}
+
+func TestDatabase_Batch(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Batch(t, be)
+		})
+	}
+	t.Run("context", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			return WithFlags(NewMapDB(), Flags{"test": true}), nil
+		}
+		testDatabase_Batch(t, creator)
+	})
+}
+
+func testDatabase_Checkpoint(t *testing.T, backend BackendType, creator dbCreator) {
+	dir := t.TempDir()
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	defer testDB.Close()
+
+	bk, err := testDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	k1, v1 := []byte("key1"), []byte("value1")
+	k2, v2 := []byte("key2"), []byte("value2")
+	assert.NoError(t, bk.Set(k1, v1))
+
+	err = Checkpoint(testDB, dir, "checkpoint")
+	if backend == MapDBBackend {
+		assert.True(t, errors.UnsupportedError.Equals(err))
+		return
+	}
+	assert.NoError(t, err)
+
+	// changes after the checkpoint shouldn't be applied.
+	assert.NoError(t, bk.Set(k2, v2))
+	assert.NoError(t, bk.Delete(k1))
+
+	// it should fail if the target already exists.
+	assert.Error(t, Checkpoint(testDB, dir, "checkpoint"))
+
+	cpDB, err := creator("checkpoint", dir)
+	assert.NoError(t, err)
+	defer cpDB.Close()
+
+	cpBK, err := cpDB.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	value, err := cpBK.Get(k1)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, value)
+	has, err := cpBK.Has(k2)
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestDatabase_Checkpoint(t *testing.T) {
+	for name, be := range backends {
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_Checkpoint(t, name, be)
+		})
+	}
+}
+
+func testDatabase_OpenReadOnly(t *testing.T, name BackendType, creator dbCreator) {
+	dir := t.TempDir()
+	key := []byte("hello")
+	value := []byte("world")
+
+	// missing database shall not be created
+	_, err := OpenReadOnly(dir, string(name), "test")
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(dir, "test"))
+	assert.True(t, os.IsNotExist(err))
+
+	testDB, err := creator("test", dir)
+	assert.NoError(t, err)
+	bucket, err := testDB.GetBucket(MerkleTrie)
+	assert.NoError(t, err)
+	assert.NoError(t, bucket.Set(key, value))
+	assert.NoError(t, testDB.Close())
+
+	roDB, err := OpenReadOnly(dir, string(name), "test")
+	assert.NoError(t, err)
+	defer roDB.Close()
+
+	bucket, err = roDB.GetBucket(MerkleTrie)
+	assert.NoError(t, err)
+	bs, err := bucket.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, value, bs)
+
+	// writes shall fail
+	assert.Error(t, bucket.Set(key, []byte("world2")))
+	assert.Error(t, bucket.Delete(key))
+}
+
+func TestDatabase_OpenReadOnly(t *testing.T) {
+	for name := range readOnlyBackends {
+		creator := backends[name]
+		t.Run(string(name), func(t *testing.T) {
+			testDatabase_OpenReadOnly(t, name, creator)
+		})
+	}
+
+	_, err := OpenReadOnly(t.TempDir(), string(MapDBBackend), "test")
+	assert.Error(t, err)
+}
@@ -0,0 +1,366 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	// EncryptionModifier is the database type modifier for encrypting values.
+	EncryptionModifier = "enc"
+
+	// KeyEncryptionModifier is the database type modifier for encrypting
+	// both keys and values.
+	KeyEncryptionModifier = "enckey"
+)
+
+const encryptionNonceSize = 12
+
+// dbCipher encrypts values with AES-256-GCM and random nonce, and encrypts
+// keys deterministically with AES-256-GCM and synthetic nonce derived
+// from HMAC-SHA256 of the key.
+type dbCipher struct {
+	value cipher.AEAD
+	key   cipher.AEAD
+	ivKey []byte
+}
+
+func deriveKey(secret []byte, label string) []byte {
+	return crypto.SHA3Sum256(append([]byte(label), secret...))
+}
+
+func newDBCipher(secret []byte) (*dbCipher, error) {
+	if len(secret) == 0 {
+		return nil, errors.IllegalArgumentError.New("EmptyEncryptionSecret")
+	}
+	newGCM := func(key []byte) (cipher.AEAD, error) {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	}
+	value, err := newGCM(deriveKey(secret, "db.value"))
+	if err != nil {
+		return nil, err
+	}
+	key, err := newGCM(deriveKey(secret, "db.key"))
+	if err != nil {
+		return nil, err
+	}
+	return &dbCipher{
+		value: value,
+		key:   key,
+		ivKey: deriveKey(secret, "db.iv"),
+	}, nil
+}
+
+func (c *dbCipher) encryptValue(aad, value []byte) ([]byte, error) {
+	nonce := make([]byte, encryptionNonceSize, encryptionNonceSize+len(value)+c.value.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.value.Seal(nonce, nonce, value, aad), nil
+}
+
+func (c *dbCipher) decryptValue(aad, data []byte) ([]byte, error) {
+	if len(data) < encryptionNonceSize+c.value.Overhead() {
+		return nil, errors.CriticalFormatError.Errorf("InvalidEncryptedValue(len=%d)", len(data))
+	}
+	value, err := c.value.Open([]byte{}, data[:encryptionNonceSize], data[encryptionNonceSize:], aad)
+	if err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "FailToDecryptValue")
+	}
+	return value, nil
+}
+
+func (c *dbCipher) syntheticNonce(key []byte) []byte {
+	mac := hmac.New(sha256.New, c.ivKey)
+	mac.Write(key)
+	return mac.Sum(nil)[:encryptionNonceSize]
+}
+
+func (c *dbCipher) encryptKey(key []byte) []byte {
+	nonce := c.syntheticNonce(key)
+	return c.key.Seal(nonce, nonce, key, nil)
+}
+
+func (c *dbCipher) decryptKey(data []byte) ([]byte, error) {
+	if len(data) < encryptionNonceSize+c.key.Overhead() {
+		return nil, errors.CriticalFormatError.Errorf("InvalidEncryptedKey(len=%d)", len(data))
+	}
+	nonce := data[:encryptionNonceSize]
+	key, err := c.key.Open([]byte{}, nonce, data[encryptionNonceSize:], nil)
+	if err != nil {
+		return nil, errors.CriticalFormatError.Wrap(err, "FailToDecryptKey")
+	}
+	if !bytes.Equal(nonce, c.syntheticNonce(key)) {
+		return nil, errors.CriticalFormatError.New("InvalidKeyNonce")
+	}
+	return key, nil
+}
+
+type encryptedBucket struct {
+	id     BucketID
+	real   Bucket
+	cipher *dbCipher
+	keys   bool
+}
+
+func (bk *encryptedBucket) realKey(key []byte) []byte {
+	if bk.keys {
+		return bk.cipher.encryptKey(internalKey(bk.id, key))
+	}
+	return key
+}
+
+func (bk *encryptedBucket) Get(key []byte) ([]byte, error) {
+	value, err := bk.real.Get(bk.realKey(key))
+	if value == nil || err != nil {
+		return value, err
+	}
+	return bk.cipher.decryptValue(internalKey(bk.id, key), value)
+}
+
+func (bk *encryptedBucket) Has(key []byte) (bool, error) {
+	return bk.real.Has(bk.realKey(key))
+}
+
+func (bk *encryptedBucket) Set(key []byte, value []byte) error {
+	enc, err := bk.cipher.encryptValue(internalKey(bk.id, key), value)
+	if err != nil {
+		return err
+	}
+	return bk.real.Set(bk.realKey(key), enc)
+}
+
+func (bk *encryptedBucket) Delete(key []byte) error {
+	return bk.real.Delete(bk.realKey(key))
+}
+
+// NewIterator returns an iterator decrypting values. Buckets with encrypted
+// keys don't support iteration because the order of keys isn't preserved.
+func (bk *encryptedBucket) NewIterator(r *Range) Iterator {
+	if bk.keys {
+		return &errorIterator{errors.UnsupportedError.New("IterationWithEncryptedKeys")}
+	}
+	return &encryptedIterator{
+		Iterator: NewIterator(bk.real, r),
+		id:       bk.id,
+		cipher:   bk.cipher,
+	}
+}
+
+type encryptedIterator struct {
+	Iterator
+	id     BucketID
+	cipher *dbCipher
+	value  []byte
+	err    error
+}
+
+func (it *encryptedIterator) Next() bool {
+	it.value = nil
+	if it.err != nil || !it.Iterator.Next() {
+		return false
+	}
+	it.value, it.err = it.cipher.decryptValue(internalKey(it.id, it.Key()), it.Iterator.Value())
+	return it.err == nil
+}
+
+func (it *encryptedIterator) Value() []byte {
+	return it.value
+}
+
+func (it *encryptedIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+type encryptedBatch struct {
+	batchOps
+	database *encryptedDB
+}
+
+func (b *encryptedBatch) Write() error {
+	batch := NewBatch(b.database.real)
+	for _, op := range b.ops {
+		id, key := op.id, op.key
+		if b.database.keys {
+			id, key = MerkleTrie, b.database.cipher.encryptKey(internalKey(op.id, op.key))
+		}
+		if op.deleted {
+			batch.Delete(id, key)
+		} else {
+			enc, err := b.database.cipher.encryptValue(internalKey(op.id, op.key), op.value)
+			if err != nil {
+				return err
+			}
+			batch.Set(id, key, enc)
+		}
+	}
+	return batch.Write()
+}
+
+// encryptedDB encrypts values, and optionally keys, of the real database.
+// Values are bound to their keys, so they can't be moved to other keys.
+// If keys are encrypted, all buckets are stored in MerkleTrie bucket of
+// the real database to hide bucket identifiers.
+type encryptedDB struct {
+	lock    sync.Mutex
+	real    Database
+	cipher  *dbCipher
+	keys    bool
+	buckets map[BucketID]*encryptedBucket
+}
+
+func (edb *encryptedDB) GetBucket(id BucketID) (Bucket, error) {
+	edb.lock.Lock()
+	defer edb.lock.Unlock()
+
+	if bk, ok := edb.buckets[id]; ok {
+		return bk, nil
+	}
+	realID := id
+	if edb.keys {
+		realID = MerkleTrie
+	}
+	real, err := edb.real.GetBucket(realID)
+	if err != nil {
+		return nil, err
+	}
+	bk := &encryptedBucket{
+		id:     id,
+		real:   real,
+		cipher: edb.cipher,
+		keys:   edb.keys,
+	}
+	edb.buckets[id] = bk
+	return bk, nil
+}
+
+func (edb *encryptedDB) NewBatch() Batch {
+	return &encryptedBatch{database: edb}
+}
+
+func (edb *encryptedDB) Close() error {
+	return edb.real.Close()
+}
+
+// WithEncryption returns the database encrypting values of the database
+// with the key derived from the secret. If keys is true, then keys are
+// also encrypted.
+func WithEncryption(database Database, secret []byte, keys bool) (Database, error) {
+	c, err := newDBCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedDB{
+		real:    database,
+		cipher:  c,
+		keys:    keys,
+		buckets: make(map[BucketID]*encryptedBucket),
+	}, nil
+}
+
+// WithModifiers applies modifiers of the database type to the database.
+// secret is used for encryption modifiers.
+func WithModifiers(database Database, modifiers []string, secret []byte) (Database, error) {
+	for _, m := range modifiers {
+		var err error
+		switch m {
+		case EncryptionModifier:
+			database, err = WithEncryption(database, secret, false)
+		case KeyEncryptionModifier:
+			database, err = WithEncryption(database, secret, true)
+		default:
+			err = errors.IllegalArgumentError.Errorf("UnknownModifier(%s)", m)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return database, nil
+}
+
+const convertBatchSize = 4096
+
+// ConvertEncryption copies all entries of src to dst encrypting them if
+// encrypt is true, or decrypting them otherwise. It reads and writes raw
+// entries through MerkleTrie bucket, so it works only with the backends
+// storing all buckets in one key space (ex. goleveldb and pebble).
+func ConvertEncryption(dst, src Database, secret []byte, keys bool, encrypt bool) error {
+	c, err := newDBCipher(secret)
+	if err != nil {
+		return err
+	}
+	sbk, err := src.GetBucket(MerkleTrie)
+	if err != nil {
+		return err
+	}
+	it := NewIterator(sbk, nil)
+	defer it.Release()
+
+	batch := NewBatch(dst)
+	for it.Next() {
+		var key, value []byte
+		if encrypt {
+			// raw key of plain database is the internal key
+			key = it.Key()
+			if value, err = c.encryptValue(key, it.Value()); err != nil {
+				return err
+			}
+			if keys {
+				key = c.encryptKey(key)
+			}
+		} else {
+			key = it.Key()
+			if keys {
+				if key, err = c.decryptKey(key); err != nil {
+					return err
+				}
+			}
+			if value, err = c.decryptValue(key, it.Value()); err != nil {
+				return err
+			}
+		}
+		batch.Set(MerkleTrie, key, value)
+		if batch.Len() >= convertBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,145 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecret = []byte("test secret for encryption")
+
+func TestEncryptedDB_Basic(t *testing.T) {
+	for _, keys := range []bool{false, true} {
+		keys := keys
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			real, err := NewGoLevelDB(name, dir)
+			if err != nil {
+				return nil, err
+			}
+			return WithEncryption(real, testSecret, keys)
+		}
+		name := "values"
+		if keys {
+			name = "keys"
+		}
+		t.Run(name, func(t *testing.T) {
+			testDatabase_GetSetDelete(t, creator)
+			testDatabase_SetReopenGet(t, creator)
+			testDatabase_Batch(t, creator)
+		})
+	}
+}
+
+func TestEncryptedDB_Stored(t *testing.T) {
+	key, value := []byte("key"), []byte("secret value")
+	for _, keys := range []bool{false, true} {
+		real := NewMapDB()
+		edb, err := WithEncryption(real, testSecret, keys)
+		assert.NoError(t, err)
+
+		bk, err := edb.GetBucket(BytesByHash)
+		assert.NoError(t, err)
+		assert.NoError(t, bk.Set(key, value))
+
+		// stored data shouldn't contain plain value
+		rbk, err := real.GetBucket(BytesByHash)
+		assert.NoError(t, err)
+		if keys {
+			rbk, err = real.GetBucket(MerkleTrie)
+			assert.NoError(t, err)
+		}
+		it := NewIterator(rbk, nil)
+		assert.True(t, it.Next())
+		assert.False(t, bytes.Contains(it.Value(), value))
+		assert.Equal(t, !keys, bytes.Equal(it.Key(), key))
+		it.Release()
+
+		// other secret can't read it
+		edb2, err := WithEncryption(real, []byte("other secret"), keys)
+		assert.NoError(t, err)
+		bk2, err := edb2.GetBucket(BytesByHash)
+		assert.NoError(t, err)
+		v, err := bk2.Get(key)
+		if keys {
+			assert.NoError(t, err)
+			assert.Nil(t, v)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+}
+
+func TestConvertEncryption(t *testing.T) {
+	for _, keys := range []bool{false, true} {
+		dir := t.TempDir()
+		plain, err := NewGoLevelDB("plain", dir)
+		assert.NoError(t, err)
+		defer plain.Close()
+
+		entries := []testOperation{
+			{MerkleTrie, []byte("k1"), []byte("v1")},
+			{BytesByHash, []byte("k2"), []byte("v2")},
+			{ChainProperty, []byte("k3"), []byte{}},
+		}
+		runTestScenario(t, plain, entries)
+
+		enc, err := NewGoLevelDB("enc", dir)
+		assert.NoError(t, err)
+		defer enc.Close()
+		assert.NoError(t, ConvertEncryption(enc, plain, testSecret, keys, true))
+
+		edb, err := WithEncryption(enc, testSecret, keys)
+		assert.NoError(t, err)
+		for _, e := range entries {
+			value, err := DoGetWithBucketID(edb, e.bk, e.key)
+			assert.NoError(t, err)
+			assert.Equal(t, e.value, value)
+		}
+
+		plain2, err := NewGoLevelDB("plain2", dir)
+		assert.NoError(t, err)
+		defer plain2.Close()
+		assert.NoError(t, ConvertEncryption(plain2, enc, testSecret, keys, false))
+		for _, e := range entries {
+			value, err := DoGetWithBucketID(plain2, e.bk, e.key)
+			assert.NoError(t, err)
+			assert.Equal(t, e.value, value)
+		}
+	}
+}
+
+func TestWithModifiers(t *testing.T) {
+	backend, modifiers := SplitType("goleveldb+enc")
+	assert.Equal(t, GoLevelDBBackend, backend)
+	assert.Equal(t, []string{EncryptionModifier}, modifiers)
+
+	_, err := Open(t.TempDir(), "goleveldb+enc", "test")
+	assert.Error(t, err)
+
+	_, err = WithModifiers(NewMapDB(), []string{"unknown"}, testSecret)
+	assert.Error(t, err)
+
+	_, err = WithModifiers(NewMapDB(), modifiers, nil)
+	assert.Error(t, err)
+
+	dbase, err := WithModifiers(NewMapDB(), modifiers, testSecret)
+	assert.NoError(t, err)
+	assert.NotNil(t, dbase)
+}
@@ -5,7 +5,9 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 const GoLevelDBBackend BackendType = "goleveldb"
@@ -15,6 +17,12 @@ func init() {
 		return NewGoLevelDB(name, dir)
 	}
 	registerDBCreator(GoLevelDBBackend, dbCreator, false)
+	registerReadOnlyDBCreator(GoLevelDBBackend, func(name string, dir string) (Database, error) {
+		return NewGoLevelDBWithOpts(name, dir, &opt.Options{
+			ReadOnly:       true,
+			ErrorIfMissing: true,
+		})
+	})
 }
 
 func NewGoLevelDB(name string, dir string) (*GoLevelDB, error) {
@@ -79,10 +87,91 @@ func (db *GoLevelDB) Close() error {
 	return nil
 }
 
+// goLevelCheckpointBatchSize is the size of written data in a batch while
+// making a checkpoint.
+const goLevelCheckpointBatchSize = 4 * 1024 * 1024
+
+// Checkpoint makes a checkpoint of the database. goleveldb doesn't support
+// it natively, so it copies all entries in a snapshot of the database to
+// a new database. It may take long for a large database.
+func (db *GoLevelDB) Checkpoint(dir, name string) error {
+	db.lock.Lock()
+	ldb := db.db
+	db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	snapshot, err := ldb.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snapshot.Release()
+
+	cdb, err := leveldb.OpenFile(filepath.Join(dir, name), &opt.Options{
+		ErrorIfExist: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer cdb.Close()
+
+	it := snapshot.NewIterator(nil, nil)
+	defer it.Release()
+	batch := new(leveldb.Batch)
+	size := 0
+	for it.Next() {
+		batch.Put(it.Key(), it.Value())
+		size += len(it.Key()) + len(it.Value())
+		if size >= goLevelCheckpointBatchSize {
+			if err := cdb.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+			size = 0
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := cdb.Write(batch, nil); err != nil {
+		return err
+	}
+	return cdb.Close()
+}
+
+func (db *GoLevelDB) NewBatch() Batch {
+	return &goLevelBatch{db: db}
+}
+
+type goLevelBatch struct {
+	batchOps
+	db *GoLevelDB
+}
+
+func (b *goLevelBatch) Write() error {
+	b.db.lock.Lock()
+	ldb := b.db.db
+	b.db.lock.Unlock()
+
+	if ldb == nil {
+		return leveldb.ErrClosed
+	}
+	batch := new(leveldb.Batch)
+	for _, op := range b.ops {
+		if op.deleted {
+			batch.Delete(internalKey(op.id, op.key))
+		} else {
+			batch.Put(internalKey(op.id, op.key), op.value)
+		}
+	}
+	return ldb.Write(batch, nil)
+}
+
 //----------------------------------------
 // GetBucket
 
-var _ Bucket = (*goLevelBucket)(nil)
+var _ IterableBucket = (*goLevelBucket)(nil)
 
 type goLevelBucket struct {
 	id BucketID
@@ -109,3 +198,37 @@ func (bucket *goLevelBucket) Set(key []byte, value []byte) error {
 func (bucket *goLevelBucket) Delete(key []byte) error {
 	return bucket.db.Delete(internalKey(bucket.id, key), nil)
 }
+
+func (bucket *goLevelBucket) NewIterator(r *Range) Iterator {
+	ir := internalRange(bucket.id, r)
+	return &goLevelIterator{
+		prefix:  len(bucket.id),
+		keySize: internalKeySize(bucket.id),
+		Iterator: bucket.db.NewIterator(&util.Range{
+			Start: ir.Start,
+			Limit: ir.Limit,
+		}, nil),
+	}
+}
+
+type goLevelIterator struct {
+	prefix  int
+	keySize int
+	iterator.Iterator
+}
+
+func (it *goLevelIterator) Next() bool {
+	for it.Iterator.Next() {
+		if it.keySize == 0 || len(it.Iterator.Key()) == it.keySize {
+			return true
+		}
+	}
+	return false
+}
+
+func (it *goLevelIterator) Key() []byte {
+	if key := it.Iterator.Key(); key != nil {
+		return key[it.prefix:]
+	}
+	return nil
+}
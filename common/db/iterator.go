@@ -0,0 +1,184 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Iterator iterates key-value pairs of a bucket in ascending order of keys.
+// It's positioned before the first entry, so Next should be called before
+// accessing Key or Value. Returned slices are valid only until the next
+// call of Next and should not be modified.
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Range specifies a key range of an iterator. Start is inclusive and
+// Limit is exclusive. nil for either of them means no bound.
+type Range struct {
+	Start []byte
+	Limit []byte
+}
+
+// PrefixRange returns the range covering all keys with the prefix.
+func PrefixRange(prefix []byte) *Range {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if c := prefix[i]; c < 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			break
+		}
+	}
+	return &Range{
+		Start: prefix,
+		Limit: limit,
+	}
+}
+
+// Contains returns whether the key is in the range.
+func (r *Range) Contains(key []byte) bool {
+	if r == nil {
+		return true
+	}
+	if r.Start != nil && bytes.Compare(key, r.Start) < 0 {
+		return false
+	}
+	if r.Limit != nil && bytes.Compare(key, r.Limit) >= 0 {
+		return false
+	}
+	return true
+}
+
+// internalRange returns the range for the keys prefixed with the bucket id.
+func internalRange(id BucketID, r *Range) *Range {
+	ir := PrefixRange([]byte(id))
+	if r == nil {
+		return ir
+	}
+	if r.Start != nil {
+		ir.Start = internalKey(id, r.Start)
+	}
+	if r.Limit != nil {
+		ir.Limit = internalKey(id, r.Limit)
+	}
+	return ir
+}
+
+// internalKeySize returns the size of the internal keys of the bucket
+// declared by RegisterKeySize. It returns zero if it's not declared.
+func internalKeySize(id BucketID) int {
+	if size := id.KeySize(); size > 0 {
+		return len(id) + size
+	}
+	return 0
+}
+
+// IterableBucket is a bucket supporting ordered iteration of its entries.
+// Some backends (ex. goleveldb and pebble) store all buckets in one key
+// space, and keys of MerkleTrie bucket are hashes without prefix. In those
+// backends, iterating MerkleTrie bucket returns all raw entries of the
+// database. Iterating a bucket with the key size declared by
+// RegisterKeySize returns only the keys of the size. Otherwise, it may
+// also return trie nodes whose hash starts with the bucket ID, so users
+// of such buckets should check the keys.
+type IterableBucket interface {
+	Bucket
+	NewIterator(r *Range) Iterator
+}
+
+// NewIterator returns an iterator over the entries of the bucket in the range.
+// nil range means all entries. If the bucket doesn't support iteration,
+// it returns an iterator failing with errors.UnsupportedError.
+func NewIterator(bk Bucket, r *Range) Iterator {
+	if ib, ok := bk.(IterableBucket); ok {
+		return ib.NewIterator(r)
+	}
+	return &errorIterator{errors.UnsupportedError.Errorf("NotIterableBucket(%T)", bk)}
+}
+
+// NewPrefixIterator returns an iterator over the entries of the bucket
+// whose keys have the prefix.
+func NewPrefixIterator(bk Bucket, prefix []byte) Iterator {
+	return NewIterator(bk, PrefixRange(prefix))
+}
+
+type errorIterator struct {
+	error
+}
+
+func (e *errorIterator) Next() bool    { return false }
+func (e *errorIterator) Key() []byte   { return nil }
+func (e *errorIterator) Value() []byte { return nil }
+func (e *errorIterator) Error() error  { return e.error }
+func (e *errorIterator) Release()      {}
+
+type kvEntry struct {
+	key   []byte
+	value []byte
+}
+
+// sliceIterator iterates sorted entries in memory.
+type sliceIterator struct {
+	entries []kvEntry
+	index   int
+}
+
+func newSliceIterator(entries []kvEntry) *sliceIterator {
+	return &sliceIterator{entries: entries, index: -1}
+}
+
+func (it *sliceIterator) valid() bool {
+	return it.index >= 0 && it.index < len(it.entries)
+}
+
+func (it *sliceIterator) Next() bool {
+	if it.index < len(it.entries) {
+		it.index += 1
+	}
+	return it.valid()
+}
+
+func (it *sliceIterator) Key() []byte {
+	if it.valid() {
+		return it.entries[it.index].key
+	}
+	return nil
+}
+
+func (it *sliceIterator) Value() []byte {
+	if it.valid() {
+		return it.entries[it.index].value
+	}
+	return nil
+}
+
+func (it *sliceIterator) Error() error {
+	return nil
+}
+
+func (it *sliceIterator) Release() {
+	it.entries = nil
+}
@@ -1,7 +1,9 @@
 package db
 
 import (
+	"bytes"
 	"container/list"
+	"sort"
 	"sync"
 
 	"github.com/icon-project/goloop/common/errors"
@@ -34,6 +36,7 @@ func (l *layerBucketItems) MoveToBack(element *list.Element) {
 
 type layerBucket struct {
 	lock sync.Mutex
+	id   BucketID
 	data map[string]*list.Element
 	list *layerBucketItems
 	real Bucket
@@ -103,6 +106,95 @@ func (bk *layerBucket) Delete(key []byte) error {
 	}
 }
 
+func (bk *layerBucket) NewIterator(r *Range) Iterator {
+	bk.lock.Lock()
+	defer bk.lock.Unlock()
+
+	if bk.data == nil {
+		return NewIterator(bk.real, r)
+	}
+	entries := make([]kvEntry, 0, len(bk.data))
+	for k, element := range bk.data {
+		if key := []byte(k); r.Contains(key) {
+			entries = append(entries, kvEntry{key, element.Value.(*layerBucketItem).value})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return newMergedIterator(newSliceIterator(entries), NewIterator(bk.real, r))
+}
+
+// mergedIterator merges entries of the upper layer with the ones of the
+// lower layer. Entries in the upper layer override the ones in the lower
+// layer, and the ones with nil value are regarded as deleted.
+type mergedIterator struct {
+	upper, lower     Iterator
+	upperOK, lowerOK bool
+	started          bool
+	key, value       []byte
+}
+
+func newMergedIterator(upper, lower Iterator) *mergedIterator {
+	return &mergedIterator{upper: upper, lower: lower}
+}
+
+func (it *mergedIterator) Next() bool {
+	if !it.started {
+		it.started = true
+		it.upperOK = it.upper.Next()
+		it.lowerOK = it.lower.Next()
+	}
+	for it.upperOK || it.lowerOK {
+		var cmp int
+		if !it.lowerOK {
+			cmp = -1
+		} else if !it.upperOK {
+			cmp = 1
+		} else {
+			cmp = bytes.Compare(it.upper.Key(), it.lower.Key())
+		}
+		if cmp > 0 {
+			// slices of the lower are invalidated by Next()
+			it.key = append([]byte{}, it.lower.Key()...)
+			it.value = append([]byte{}, it.lower.Value()...)
+			it.lowerOK = it.lower.Next()
+			return true
+		}
+		key, value := it.upper.Key(), it.upper.Value()
+		it.upperOK = it.upper.Next()
+		if cmp == 0 {
+			it.lowerOK = it.lower.Next()
+		}
+		if value != nil {
+			it.key, it.value = key, value
+			return true
+		}
+	}
+	it.key, it.value = nil, nil
+	return false
+}
+
+func (it *mergedIterator) Key() []byte {
+	return it.key
+}
+
+func (it *mergedIterator) Value() []byte {
+	return it.value
+}
+
+func (it *mergedIterator) Error() error {
+	if err := it.upper.Error(); err != nil {
+		return err
+	}
+	return it.lower.Error()
+}
+
+func (it *mergedIterator) Release() {
+	it.upper.Release()
+	it.lower.Release()
+}
+
 type layerDB struct {
 	lock sync.Mutex
 
@@ -128,6 +220,7 @@ func (ldb *layerDB) GetBucket(id BucketID) (Bucket, error) {
 		return realbk, nil
 	}
 	bk := &layerBucket{
+		id:   id,
 		data: make(map[string]*list.Element),
 		list: &ldb.list,
 		real: realbk,
@@ -159,19 +252,19 @@ func (ldb *layerDB) Flush(write bool) error {
 	}()
 
 	if write {
-		for element := ldb.list.Front() ; element != nil ; element = element.Next() {
+		batch := NewBatch(ldb.real)
+		for element := ldb.list.Front(); element != nil; element = element.Next() {
 			item := element.Value.(*layerBucketItem)
 
 			if item.value != nil {
-				if err := item.bk.real.Set([]byte(item.key), item.value ); err != nil {
-					return err
-				}
+				batch.Set(item.bk.id, []byte(item.key), item.value)
 			} else {
-				if err := item.bk.real.Delete([]byte(item.key)); err != nil {
-					return err
-				}
+				batch.Delete(item.bk.id, []byte(item.key))
 			}
 		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
 		for _, bk := range ldb.buckets {
 			bk.data = nil
 		}
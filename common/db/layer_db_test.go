@@ -147,3 +147,78 @@ func TestLayerDB_FlushInOrder(t *testing.T) {
 
 	assert.Equal(t, Unwrap(ldb), dbase)
 }
+
+func TestLayerDB_IterateMerged(t *testing.T) {
+	origin := NewMapDB()
+	obk, err := origin.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	for _, k := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, obk.Set([]byte(k), []byte("o"+k)))
+	}
+
+	ldb := NewLayerDB(origin)
+	bk, err := ldb.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Set([]byte("b"), []byte("lb")))
+	assert.NoError(t, bk.Delete([]byte("c")))
+	assert.NoError(t, bk.Set([]byte("e"), []byte("le")))
+	assert.NoError(t, bk.Delete([]byte("x")))
+
+	entries := collectEntries(t, NewIterator(bk, nil))
+	assert.Equal(t, [][2]string{
+		{"a", "oa"}, {"b", "lb"}, {"d", "od"}, {"e", "le"},
+	}, entries)
+
+	entries = collectEntries(t, NewIterator(bk, &Range{Start: []byte("b"), Limit: []byte("e")}))
+	assert.Equal(t, [][2]string{
+		{"b", "lb"}, {"d", "od"},
+	}, entries)
+
+	// origin is not changed before flush
+	entries = collectEntries(t, NewIterator(obk, nil))
+	assert.Equal(t, [][2]string{
+		{"a", "oa"}, {"b", "ob"}, {"c", "oc"}, {"d", "od"},
+	}, entries)
+
+	assert.NoError(t, ldb.Flush(true))
+	entries = collectEntries(t, NewIterator(obk, nil))
+	assert.Equal(t, [][2]string{
+		{"a", "oa"}, {"b", "lb"}, {"d", "od"}, {"e", "le"},
+	}, entries)
+}
+
+type testBatchDatabase struct {
+	*testDatabase
+	writes int
+}
+
+type testBatch struct {
+	sequentialBatch
+	dbase *testBatchDatabase
+}
+
+func (b *testBatch) Write() error {
+	b.dbase.writes += 1
+	return b.sequentialBatch.Write()
+}
+
+func (t *testBatchDatabase) NewBatch() Batch {
+	return &testBatch{sequentialBatch{database: t.testDatabase}, t}
+}
+
+func TestLayerDB_FlushWithBatch(t *testing.T) {
+	scenario := []testOperation{
+		{BytesByHash, []byte("key1"), []byte("value1")},
+		{ChainProperty, []byte("key2"), []byte("value2")},
+		{BytesByHash, []byte("key3"), nil},
+	}
+	dbase := &testBatchDatabase{testDatabase: newTestDatabase()}
+	ldb := NewLayerDB(dbase)
+
+	runTestScenario(t, ldb, scenario)
+	assert.Empty(t, dbase.record)
+
+	assert.NoError(t, ldb.Flush(true))
+	assert.Equal(t, 1, dbase.writes)
+	assert.Equal(t, scenario, dbase.record)
+}
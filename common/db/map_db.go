@@ -1,7 +1,9 @@
 package db
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/icon-project/goloop/common/log"
@@ -61,7 +63,7 @@ func (t *mapDatabase) Close() error {
 //----------------------------------------
 // Bucket
 
-var _ Bucket = (*mapBucket)(nil)
+var _ IterableBucket = (*mapBucket)(nil)
 
 type mapBucket struct {
 	id    string
@@ -115,3 +117,19 @@ func (t *mapBucket) Delete(k []byte) error {
 	delete(t.real, string(k))
 	return nil
 }
+
+func (t *mapBucket) NewIterator(r *Range) Iterator {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	entries := make([]kvEntry, 0, len(t.real))
+	for k, v := range t.real {
+		if key := []byte(k); r.Contains(key) {
+			entries = append(entries, kvEntry{key, []byte(v)})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+	return newSliceIterator(entries)
+}
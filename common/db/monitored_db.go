@@ -0,0 +1,182 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"sync"
+	"time"
+)
+
+type Operation int
+
+const (
+	OpGet Operation = iota
+	OpHas
+	OpSet
+	OpDelete
+)
+
+func (op Operation) String() string {
+	switch op {
+	case OpGet:
+		return "get"
+	case OpHas:
+		return "has"
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// OperationMonitor receives the result of each bucket operation.
+// size is the number of bytes read(OpGet) or written(OpSet).
+// hit is whether the entry exists for OpGet and OpHas.
+type OperationMonitor interface {
+	OnOperation(id BucketID, op Operation, d time.Duration, size int, hit bool, err error)
+}
+
+type monitoredBucket struct {
+	id      BucketID
+	real    Bucket
+	monitor OperationMonitor
+}
+
+func (bk *monitoredBucket) Get(key []byte) ([]byte, error) {
+	ts := time.Now()
+	value, err := bk.real.Get(key)
+	bk.monitor.OnOperation(bk.id, OpGet, time.Since(ts), len(value), value != nil, err)
+	return value, err
+}
+
+func (bk *monitoredBucket) Has(key []byte) (bool, error) {
+	ts := time.Now()
+	has, err := bk.real.Has(key)
+	bk.monitor.OnOperation(bk.id, OpHas, time.Since(ts), 0, has, err)
+	return has, err
+}
+
+func (bk *monitoredBucket) Set(key []byte, value []byte) error {
+	ts := time.Now()
+	err := bk.real.Set(key, value)
+	bk.monitor.OnOperation(bk.id, OpSet, time.Since(ts), len(key)+len(value), false, err)
+	return err
+}
+
+func (bk *monitoredBucket) Delete(key []byte) error {
+	ts := time.Now()
+	err := bk.real.Delete(key)
+	bk.monitor.OnOperation(bk.id, OpDelete, time.Since(ts), 0, false, err)
+	return err
+}
+
+func (bk *monitoredBucket) NewIterator(r *Range) Iterator {
+	return NewIterator(bk.real, r)
+}
+
+type monitoredOp struct {
+	id   BucketID
+	op   Operation
+	size int
+}
+
+// monitoredBatch reports the operations of the batch to the monitor when
+// they are written. The batch is written at once, so each operation takes
+// an equal share of the time spent for writing.
+type monitoredBatch struct {
+	Batch
+	monitor OperationMonitor
+	ops     []monitoredOp
+}
+
+func (b *monitoredBatch) Set(id BucketID, key, value []byte) {
+	b.Batch.Set(id, key, value)
+	b.ops = append(b.ops, monitoredOp{id, OpSet, len(key) + len(value)})
+}
+
+func (b *monitoredBatch) Delete(id BucketID, key []byte) {
+	b.Batch.Delete(id, key)
+	b.ops = append(b.ops, monitoredOp{id, OpDelete, 0})
+}
+
+func (b *monitoredBatch) Reset() {
+	b.Batch.Reset()
+	b.ops = nil
+}
+
+func (b *monitoredBatch) Write() error {
+	ts := time.Now()
+	err := b.Batch.Write()
+	if len(b.ops) > 0 {
+		d := time.Since(ts) / time.Duration(len(b.ops))
+		for _, op := range b.ops {
+			b.monitor.OnOperation(op.id, op.op, d, op.size, false, err)
+		}
+	}
+	return err
+}
+
+// monitoredDB reports operations on its buckets to the monitor.
+// Operations of write batches are reported when they are written.
+type monitoredDB struct {
+	lock    sync.Mutex
+	real    Database
+	monitor OperationMonitor
+	buckets map[BucketID]*monitoredBucket
+}
+
+func (mdb *monitoredDB) GetBucket(id BucketID) (Bucket, error) {
+	mdb.lock.Lock()
+	defer mdb.lock.Unlock()
+
+	if bk, ok := mdb.buckets[id]; ok {
+		return bk, nil
+	}
+	real, err := mdb.real.GetBucket(id)
+	if err != nil {
+		return nil, err
+	}
+	bk := &monitoredBucket{
+		id:      id,
+		real:    real,
+		monitor: mdb.monitor,
+	}
+	mdb.buckets[id] = bk
+	return bk, nil
+}
+
+func (mdb *monitoredDB) NewBatch() Batch {
+	return &monitoredBatch{
+		Batch:   NewBatch(mdb.real),
+		monitor: mdb.monitor,
+	}
+}
+
+func (mdb *monitoredDB) Close() error {
+	return mdb.real.Close()
+}
+
+// WithMonitor returns the database reporting bucket operations to the monitor.
+func WithMonitor(database Database, monitor OperationMonitor) Database {
+	return &monitoredDB{
+		real:    database,
+		monitor: monitor,
+		buckets: make(map[BucketID]*monitoredBucket),
+	}
+}
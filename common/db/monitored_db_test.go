@@ -0,0 +1,104 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testOperationRecord struct {
+	id   BucketID
+	op   Operation
+	size int
+	hit  bool
+}
+
+type testMonitor struct {
+	records []testOperationRecord
+}
+
+func (m *testMonitor) OnOperation(id BucketID, op Operation, d time.Duration, size int, hit bool, err error) {
+	m.records = append(m.records, testOperationRecord{id, op, size, hit})
+}
+
+func TestMonitoredDB_Basic(t *testing.T) {
+	t.Run("backend", func(t *testing.T) {
+		var creator dbCreator = func(name string, dir string) (Database, error) {
+			return WithMonitor(NewMapDB(), &testMonitor{}), nil
+		}
+		testDatabase_GetSetDelete(t, creator)
+	})
+
+	monitor := &testMonitor{}
+	dbase := WithMonitor(NewMapDB(), monitor)
+	bk, err := dbase.GetBucket(BytesByHash)
+	assert.NoError(t, err)
+
+	assert.NoError(t, bk.Set([]byte("key"), []byte("value")))
+	_, err = bk.Get([]byte("key"))
+	assert.NoError(t, err)
+	_, err = bk.Get([]byte("none"))
+	assert.NoError(t, err)
+	_, err = bk.Has([]byte("key"))
+	assert.NoError(t, err)
+	assert.NoError(t, bk.Delete([]byte("key")))
+
+	assert.Equal(t, []testOperationRecord{
+		{BytesByHash, OpSet, 8, false},
+		{BytesByHash, OpGet, 5, true},
+		{BytesByHash, OpGet, 0, false},
+		{BytesByHash, OpHas, 0, true},
+		{BytesByHash, OpDelete, 0, false},
+	}, monitor.records)
+}
+
+func TestMonitoredDB_Batch(t *testing.T) {
+	monitor := &testMonitor{}
+	dbase := WithMonitor(NewMapDB(), monitor)
+
+	batch := NewBatch(dbase)
+	batch.Set(BytesByHash, []byte("key"), []byte("value"))
+	batch.Set(ChainProperty, []byte("k"), []byte("v"))
+	batch.Delete(BytesByHash, []byte("old"))
+	assert.Empty(t, monitor.records)
+
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, []testOperationRecord{
+		{BytesByHash, OpSet, 8, false},
+		{ChainProperty, OpSet, 2, false},
+		{BytesByHash, OpDelete, 0, false},
+	}, monitor.records)
+
+	bk, err := dbase.GetBucket(ChainProperty)
+	assert.NoError(t, err)
+	value, err := bk.Get([]byte("k"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("v"), value)
+
+	// reset batch doesn't report dropped operations
+	monitor.records = nil
+	batch.Reset()
+	batch.Delete(ChainProperty, []byte("k"))
+	assert.Equal(t, 1, batch.Len())
+	assert.NoError(t, batch.Write())
+	assert.Equal(t, []testOperationRecord{
+		{ChainProperty, OpDelete, 0, false},
+	}, monitor.records)
+}
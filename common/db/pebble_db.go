@@ -0,0 +1,360 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const PebbleDBBackend BackendType = "pebble"
+
+var errPebbleClosed = errors.InvalidStateError.New("PebbleDBClosed")
+
+// pebbleSyncInterval is the interval of syncing the WAL. Writes aren't
+// synced for performance, but pebble keeps them in memory until the WAL
+// block is filled, so they are synced periodically to survive abnormal
+// termination of the process.
+const pebbleSyncInterval = 200 * time.Millisecond
+
+func init() {
+	dbCreator := func(name string, dir string) (Database, error) {
+		return NewPebbleDB(name, dir)
+	}
+	registerDBCreator(PebbleDBBackend, dbCreator, false)
+	registerReadOnlyDBCreator(PebbleDBBackend, func(name string, dir string) (Database, error) {
+		return NewPebbleDBWithOpts(name, dir, &pebble.Options{
+			ReadOnly:         true,
+			ErrorIfNotExists: true,
+		})
+	})
+}
+
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	return NewPebbleDBWithOpts(name, dir, nil)
+}
+
+func NewPebbleDBWithOpts(name string, dir string, o *pebble.Options) (*PebbleDB, error) {
+	if o == nil {
+		o = &pebble.Options{}
+	}
+	dbPath := filepath.Join(dir, name)
+	db, err := pebble.Open(dbPath, o)
+	if err != nil {
+		return nil, err
+	}
+	database := &PebbleDB{
+		db:      db,
+		buckets: make(map[BucketID]Bucket),
+		stop:    make(chan struct{}),
+	}
+	if !o.ReadOnly {
+		go database.syncLoop()
+	}
+	return database, nil
+}
+
+//----------------------------------------
+// Database
+
+var _ Database = (*PebbleDB)(nil)
+
+type PebbleDB struct {
+	lock    sync.RWMutex
+	db      *pebble.DB
+	buckets map[BucketID]Bucket
+
+	// dirty is 1 if there are writes not synced yet.
+	dirty int32
+	stop  chan struct{}
+}
+
+func (db *PebbleDB) syncLoop() {
+	ticker := time.NewTicker(pebbleSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-db.stop:
+			return
+		case <-ticker.C:
+			if atomic.CompareAndSwapInt32(&db.dirty, 1, 0) {
+				db.sync()
+			}
+		}
+	}
+}
+
+func (db *PebbleDB) sync() {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db != nil {
+		_ = db.db.LogData(nil, pebble.Sync)
+	}
+}
+
+func (db *PebbleDB) GetBucket(id BucketID) (Bucket, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return nil, errPebbleClosed
+	}
+
+	if bk, ok := db.buckets[id]; ok {
+		return bk, nil
+	} else {
+		bk = &pebbleBucket{
+			id: id,
+			db: db,
+		}
+		db.buckets[id] = bk
+		return bk, nil
+	}
+}
+
+func (db *PebbleDB) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.db == nil {
+		return errPebbleClosed
+	}
+	// pebble is closed even if it returns an error, so release the handle
+	// and stop syncing regardless of the result.
+	err := db.db.Close()
+	db.db = nil
+	close(db.stop)
+	return err
+}
+
+func (db *PebbleDB) getValue(k []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, errPebbleClosed
+	}
+	value, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	// value is only valid until closer is closed, and empty value
+	// should be distinguished from absent one.
+	bs := make([]byte, len(value))
+	copy(bs, value)
+	return bs, nil
+}
+
+func (db *PebbleDB) hasValue(k []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return false, errPebbleClosed
+	}
+	_, closer, err := db.db.Get(k)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+func (db *PebbleDB) setValue(k, v []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errPebbleClosed
+	}
+	defer atomic.StoreInt32(&db.dirty, 1)
+	return db.db.Set(k, v, pebble.NoSync)
+}
+
+func (db *PebbleDB) deleteValue(k []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errPebbleClosed
+	}
+	defer atomic.StoreInt32(&db.dirty, 1)
+	return db.db.Delete(k, pebble.NoSync)
+}
+
+func (db *PebbleDB) NewBatch() Batch {
+	return &pebbleBatch{db: db}
+}
+
+type pebbleBatch struct {
+	batchOps
+	db *PebbleDB
+}
+
+func (b *pebbleBatch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return errPebbleClosed
+	}
+	batch := b.db.db.NewBatch()
+	defer batch.Close()
+	for _, op := range b.ops {
+		var err error
+		if op.deleted {
+			err = batch.Delete(internalKey(op.id, op.key), nil)
+		} else {
+			err = batch.Set(internalKey(op.id, op.key), op.value, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	defer atomic.StoreInt32(&b.db.dirty, 1)
+	return batch.Commit(pebble.NoSync)
+}
+
+// Checkpoint makes a checkpoint of the database. Files are hard-linked
+// if possible, so it's better to make it in the same filesystem.
+func (db *PebbleDB) Checkpoint(dir, name string) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return errPebbleClosed
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return db.db.Checkpoint(filepath.Join(dir, name), pebble.WithFlushedWAL())
+}
+
+//----------------------------------------
+// Bucket
+
+var _ IterableBucket = (*pebbleBucket)(nil)
+
+type pebbleBucket struct {
+	id BucketID
+	db *PebbleDB
+}
+
+func (bucket *pebbleBucket) Get(key []byte) ([]byte, error) {
+	return bucket.db.getValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Has(key []byte) (bool, error) {
+	return bucket.db.hasValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) Set(key []byte, value []byte) error {
+	return bucket.db.setValue(internalKey(bucket.id, key), value)
+}
+
+func (bucket *pebbleBucket) Delete(key []byte) error {
+	return bucket.db.deleteValue(internalKey(bucket.id, key))
+}
+
+func (bucket *pebbleBucket) NewIterator(r *Range) Iterator {
+	bucket.db.lock.RLock()
+	defer bucket.db.lock.RUnlock()
+
+	if bucket.db.db == nil {
+		return &errorIterator{errPebbleClosed}
+	}
+	ir := internalRange(bucket.id, r)
+	iter, err := bucket.db.db.NewIter(&pebble.IterOptions{
+		LowerBound: ir.Start,
+		UpperBound: ir.Limit,
+	})
+	if err != nil {
+		return &errorIterator{err}
+	}
+	return &pebbleIterator{
+		prefix:  len(bucket.id),
+		keySize: internalKeySize(bucket.id),
+		iter:    iter,
+	}
+}
+
+type pebbleIterator struct {
+	prefix  int
+	keySize int
+	iter    *pebble.Iterator
+	started bool
+	err     error
+}
+
+func (it *pebbleIterator) Next() bool {
+	if it.iter == nil {
+		return false
+	}
+	for {
+		var ok bool
+		if !it.started {
+			it.started = true
+			ok = it.iter.First()
+		} else {
+			ok = it.iter.Next()
+		}
+		if !ok || it.keySize == 0 || len(it.iter.Key()) == it.keySize {
+			return ok
+		}
+	}
+}
+
+func (it *pebbleIterator) Key() []byte {
+	if it.iter == nil || !it.iter.Valid() {
+		return nil
+	}
+	return it.iter.Key()[it.prefix:]
+}
+
+func (it *pebbleIterator) Value() []byte {
+	if it.iter == nil || !it.iter.Valid() {
+		return nil
+	}
+	return it.iter.Value()
+}
+
+func (it *pebbleIterator) Error() error {
+	if it.iter == nil {
+		return it.err
+	}
+	return it.iter.Error()
+}
+
+func (it *pebbleIterator) Release() {
+	if it.iter != nil {
+		it.err = it.iter.Close()
+		it.iter = nil
+	}
+}
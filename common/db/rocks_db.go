@@ -20,6 +20,7 @@
 package db
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"path"
@@ -46,6 +47,9 @@ func init() {
 		return NewRocksDB(name, dir)
 	}
 	registerDBCreator(RocksDBBackend, dbCreator, false)
+	registerReadOnlyDBCreator(RocksDBBackend, func(name string, dir string) (Database, error) {
+		return newRocksDB(name, dir, true)
+	})
 }
 
 type RocksDB struct {
@@ -59,13 +63,21 @@ type RocksDB struct {
 }
 
 func NewRocksDB(name string, dir string) (*RocksDB, error) {
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		log.Errorln("fail to MkdirAll", err.Error())
-		return nil, err
-	}
+	return newRocksDB(name, dir, false)
+}
+
+// newRocksDB opens the database. If readOnly is true, then it opens
+// the existing database only for reading.
+func newRocksDB(name string, dir string, readOnly bool) (*RocksDB, error) {
 	opts := C.rocksdb_options_create()
-	C.rocksdb_options_set_create_if_missing(opts, C.uchar(1))
-	C.rocksdb_options_set_create_missing_column_families(opts, C.uchar(1))
+	if !readOnly {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			log.Errorln("fail to MkdirAll", err.Error())
+			return nil, err
+		}
+		C.rocksdb_options_set_create_if_missing(opts, C.uchar(1))
+		C.rocksdb_options_set_create_missing_column_families(opts, C.uchar(1))
+	}
 
 	var (
 		cErr    *C.char
@@ -83,7 +95,11 @@ func NewRocksDB(name string, dir string) (*RocksDB, error) {
 
 		// ignore and try open
 		cErr = nil
-		hdl = C.rocksdb_open(opts, cName, &cErr)
+		if readOnly {
+			hdl = C.rocksdb_open_for_read_only(opts, cName, C.uchar(0), &cErr)
+		} else {
+			hdl = C.rocksdb_open(opts, cName, &cErr)
+		}
 		if cErr != nil {
 			errMsg = C.GoString(cErr)
 			defer C.rocksdb_free(unsafe.Pointer(cErr))
@@ -99,14 +115,26 @@ func NewRocksDB(name string, dir string) (*RocksDB, error) {
 			cfOpts[i] = C.rocksdb_options_create()
 		}
 		cfhs := make([]*C.rocksdb_column_family_handle_t, numOfCfs)
-		hdl = C.rocksdb_open_column_families(
-			opts,
-			cName,
-			C.int(numOfCfs),
-			cfs,
-			&cfOpts[0],
-			&cfhs[0],
-			&cErr)
+		if readOnly {
+			hdl = C.rocksdb_open_for_read_only_column_families(
+				opts,
+				cName,
+				C.int(numOfCfs),
+				cfs,
+				&cfOpts[0],
+				&cfhs[0],
+				C.uchar(0),
+				&cErr)
+		} else {
+			hdl = C.rocksdb_open_column_families(
+				opts,
+				cName,
+				C.int(numOfCfs),
+				cfs,
+				&cfOpts[0],
+				&cfhs[0],
+				&cErr)
+		}
 		if cErr != nil {
 			errMsg := C.GoString(cErr)
 			defer C.rocksdb_free(unsafe.Pointer(cErr))
@@ -145,6 +173,38 @@ func NewRocksDB(name string, dir string) (*RocksDB, error) {
 	return rdb, nil
 }
 
+// Checkpoint makes a checkpoint of the database. Files are hard-linked
+// if possible, so it's better to make it in the same filesystem.
+func (db *RocksDB) Checkpoint(dir, name string) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return ErrAlreadyClosed
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	var cErr *C.char
+	cp := C.rocksdb_checkpoint_object_create(db.db, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	defer C.rocksdb_checkpoint_object_destroy(cp)
+
+	cName := C.CString(path.Join(dir, name))
+	defer C.free(unsafe.Pointer(cName))
+	// flush memtables always, to make the checkpoint without WAL files.
+	C.rocksdb_checkpoint_create(cp, cName, 0, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
 func (db *RocksDB) Close() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
@@ -296,6 +356,53 @@ func (db *RocksDB) deleteValue(cf *C.rocksdb_column_family_handle_t, k []byte) e
 	return nil
 }
 
+func (db *RocksDB) NewBatch() Batch {
+	return &rocksBatch{db: db}
+}
+
+type rocksBatch struct {
+	batchOps
+	db *RocksDB
+}
+
+func (b *rocksBatch) Write() error {
+	cfs := make([]*C.rocksdb_column_family_handle_t, len(b.ops))
+	for i, op := range b.ops {
+		bk, err := b.db.GetBucket(op.id)
+		if err != nil {
+			return err
+		}
+		cfs[i] = bk.(*RocksBucket).cf
+	}
+
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return ErrAlreadyClosed
+	}
+	wb := C.rocksdb_writebatch_create()
+	defer C.rocksdb_writebatch_destroy(wb)
+	for i, op := range b.ops {
+		cKey := (*C.char)(unsafePointerOf(op.key))
+		if op.deleted {
+			C.rocksdb_writebatch_delete_cf(wb, cfs[i], cKey, C.size_t(len(op.key)))
+		} else {
+			cValue := (*C.char)(unsafePointerOf(op.value))
+			C.rocksdb_writebatch_put_cf(wb, cfs[i], cKey, C.size_t(len(op.key)), cValue, C.size_t(len(op.value)))
+		}
+	}
+	var cErr *C.char
+	C.rocksdb_write(b.db.db, b.db.wo, wb, &cErr)
+	if cErr != nil {
+		defer C.rocksdb_free(unsafe.Pointer(cErr))
+		return errors.New(C.GoString(cErr))
+	}
+	return nil
+}
+
+var _ IterableBucket = (*RocksBucket)(nil)
+
 type RocksBucket struct {
 	cf *C.rocksdb_column_family_handle_t
 	db *RocksDB
@@ -316,3 +423,99 @@ func (b *RocksBucket) Set(key []byte, value []byte) error {
 func (b *RocksBucket) Delete(key []byte) error {
 	return b.db.deleteValue(b.cf, key)
 }
+
+func (b *RocksBucket) NewIterator(r *Range) Iterator {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.db == nil {
+		return &errorIterator{ErrAlreadyClosed}
+	}
+	it := &rocksIterator{
+		db:   b.db,
+		iter: C.rocksdb_create_iterator_cf(b.db.db, b.db.ro, b.cf),
+	}
+	if r != nil {
+		it.start, it.limit = r.Start, r.Limit
+	}
+	return it
+}
+
+// rocksIterator iterates entries of a column family. It should be released
+// before the database is closed.
+type rocksIterator struct {
+	db           *RocksDB
+	iter         *C.rocksdb_iterator_t
+	start, limit []byte
+	started      bool
+	key, value   []byte
+	err          error
+}
+
+func (it *rocksIterator) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	it.key, it.value = nil, nil
+	if it.iter == nil || it.err != nil {
+		return false
+	}
+	if it.db.db == nil {
+		it.err = ErrAlreadyClosed
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if len(it.start) > 0 {
+			C.rocksdb_iter_seek(it.iter, (*C.char)(unsafePointerOf(it.start)), C.size_t(len(it.start)))
+		} else {
+			C.rocksdb_iter_seek_to_first(it.iter)
+		}
+	} else {
+		C.rocksdb_iter_next(it.iter)
+	}
+	if C.rocksdb_iter_valid(it.iter) == 0 {
+		var cErr *C.char
+		C.rocksdb_iter_get_error(it.iter, &cErr)
+		if cErr != nil {
+			defer C.rocksdb_free(unsafe.Pointer(cErr))
+			it.err = errors.New(C.GoString(cErr))
+		}
+		return false
+	}
+	var kLen, vLen C.size_t
+	cKey := C.rocksdb_iter_key(it.iter, &kLen)
+	key := C.GoBytes(unsafe.Pointer(cKey), C.int(kLen))
+	if it.limit != nil && bytes.Compare(key, it.limit) >= 0 {
+		return false
+	}
+	cValue := C.rocksdb_iter_value(it.iter, &vLen)
+	it.key = key
+	it.value = C.GoBytes(unsafe.Pointer(cValue), C.int(vLen))
+	return true
+}
+
+func (it *rocksIterator) Key() []byte {
+	return it.key
+}
+
+func (it *rocksIterator) Value() []byte {
+	return it.value
+}
+
+func (it *rocksIterator) Error() error {
+	return it.err
+}
+
+func (it *rocksIterator) Release() {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	if it.iter != nil {
+		if it.db.db != nil {
+			C.rocksdb_iter_destroy(it.iter)
+		}
+		it.iter = nil
+	}
+	it.key, it.value = nil, nil
+}
@@ -34,25 +34,55 @@ func New(database db.Database) trie.Manager {
 
 type BytesDifferenceHandler func(diff int, key, expect, real []byte)
 
+// compareKeys compares current keys of iterators. Exhausted iterator is
+// treated as it has the largest key.
+func compareKeys(hasE bool, ke []byte, hasR bool, kr []byte) int {
+	switch {
+	case !hasE:
+		return 1
+	case !hasR:
+		return -1
+	default:
+		return bytes.Compare(ke, kr)
+	}
+}
+
 func CompareImmutable(exp, real trie.Immutable, handler BytesDifferenceHandler) error {
+	return CompareImmutableWithError(exp, real, func(op int, key, expect, real []byte) error {
+		handler(op, key, expect, real)
+		return nil
+	})
+}
+
+// CompareImmutableWithError is same as CompareImmutable except that it
+// stops comparing and returns the error if the handler returns an error.
+func CompareImmutableWithError(exp, real trie.Immutable, handler func(op int, key, expect, real []byte) error) error {
 	for ie, ir := exp.Iterator(), real.Iterator(); ie.Has() || ir.Has(); {
-		ve, ke, err := ie.Get()
-		if err != nil {
-			return err
+		var ve, ke, vr, kr []byte
+		var err error
+		if ie.Has() {
+			if ve, ke, err = ie.Get(); err != nil {
+				return err
+			}
 		}
-		vr, kr, err := ir.Get()
-		if err != nil {
-			return err
+		if ir.Has() {
+			if vr, kr, err = ir.Get(); err != nil {
+				return err
+			}
 		}
-		switch bytes.Compare(ke, kr) {
+		switch compareKeys(ie.Has(), ke, ir.Has(), kr) {
 		case -1:
-			handler(-1, ke, ve, nil)
+			if err := handler(-1, ke, ve, nil); err != nil {
+				return err
+			}
 			if err := ie.Next(); err != nil {
 				return err
 			}
 		case 0:
 			if !bytes.Equal(ve, vr) {
-				handler(0, ke, ve, vr)
+				if err := handler(0, ke, ve, vr); err != nil {
+					return err
+				}
 			}
 			if err := ie.Next(); err != nil {
 				return err
@@ -61,7 +91,9 @@ func CompareImmutable(exp, real trie.Immutable, handler BytesDifferenceHandler)
 				return err
 			}
 		case 1:
-			handler(1, kr, nil, vr)
+			if err := handler(1, kr, nil, vr); err != nil {
+				return err
+			}
 			if err := ir.Next(); err != nil {
 				return err
 			}
@@ -73,24 +105,43 @@ func CompareImmutable(exp, real trie.Immutable, handler BytesDifferenceHandler)
 type ObjectDifferenceHandler func(op int, key []byte, expect, real trie.Object)
 
 func CompareImmutableForObject(exp, real trie.ImmutableForObject, handler ObjectDifferenceHandler) error {
+	return CompareImmutableForObjectWithError(exp, real, func(op int, key []byte, expect, real trie.Object) error {
+		handler(op, key, expect, real)
+		return nil
+	})
+}
+
+// CompareImmutableForObjectWithError is same as CompareImmutableForObject
+// except that it stops comparing and returns the error if the handler
+// returns an error.
+func CompareImmutableForObjectWithError(exp, real trie.ImmutableForObject, handler func(op int, key []byte, expect, real trie.Object) error) error {
 	for ie, ir := exp.Iterator(), real.Iterator(); ie.Has() || ir.Has(); {
-		ve, ke, err := ie.Get()
-		if err != nil {
-			return err
+		var ve, vr trie.Object
+		var ke, kr []byte
+		var err error
+		if ie.Has() {
+			if ve, ke, err = ie.Get(); err != nil {
+				return err
+			}
 		}
-		vr, kr, err := ir.Get()
-		if err != nil {
-			return err
+		if ir.Has() {
+			if vr, kr, err = ir.Get(); err != nil {
+				return err
+			}
 		}
-		switch bytes.Compare(ke, kr) {
+		switch compareKeys(ie.Has(), ke, ir.Has(), kr) {
 		case -1:
-			handler(-1, ke, ve, nil)
+			if err := handler(-1, ke, ve, nil); err != nil {
+				return err
+			}
 			if err := ie.Next(); err != nil {
 				return err
 			}
 		case 0:
 			if !bytes.Equal(ve.Bytes(), vr.Bytes()) {
-				handler(0, ke, ve, vr)
+				if err := handler(0, ke, ve, vr); err != nil {
+					return err
+				}
 			}
 			if err := ie.Next(); err != nil {
 				return err
@@ -99,7 +150,9 @@ func CompareImmutableForObject(exp, real trie.ImmutableForObject, handler Object
 				return err
 			}
 		case 1:
-			handler(1, kr, nil, vr)
+			if err := handler(1, kr, nil, vr); err != nil {
+				return err
+			}
 			if err := ir.Next(); err != nil {
 				return err
 			}
@@ -1043,3 +1043,38 @@ func TestOldValue_MutableSetDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareImmutable(t *testing.T) {
+	dbase := db.NewMapDB()
+	t1 := NewMutable(dbase, nil)
+	t2 := NewMutable(dbase, nil)
+	t1.Set([]byte("a"), []byte("1"))
+	t1.Set([]byte("b"), []byte("2"))
+	t2.Set([]byte("b"), []byte("3"))
+	t2.Set([]byte("c"), []byte("4"))
+	t2.Set([]byte("d"), []byte("5"))
+
+	type diff struct {
+		op        int
+		key       string
+		exp, real []byte
+	}
+	var diffs []diff
+	err := CompareImmutable(t1.GetSnapshot(), t2.GetSnapshot(), func(op int, key, exp, real []byte) {
+		diffs = append(diffs, diff{op, string(key), exp, real})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []diff{
+		{-1, "a", []byte("1"), nil},
+		{0, "b", []byte("2"), []byte("3")},
+		{1, "c", nil, []byte("4")},
+		{1, "d", nil, []byte("5")},
+	}, diffs)
+
+	diffs = nil
+	err = CompareImmutable(t2.GetSnapshot(), NewImmutable(dbase, nil), func(op int, key, exp, real []byte) {
+		diffs = append(diffs, diff{op, string(key), exp, real})
+	})
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 3)
+}
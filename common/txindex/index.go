@@ -0,0 +1,227 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package txindex maintains the index of transactions by addresses.
+//
+// Transactions are indexed for the sender, the receiver and the addresses
+// used as indexed parameters of the events. Entries are stored in
+// db.TransactionLocatorByAddress with the key
+//
+//	address(21 bytes) | ^height(8 bytes) | ^index(4 bytes)
+//
+// so that the latest transaction of the address comes first. The value
+// of the entry is the hash of the transaction.
+package txindex
+
+import (
+	"encoding/binary"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+const (
+	// KeyTopHeight is the key of the chain property for the highest
+	// indexed height.
+	KeyTopHeight = "txindex.top"
+
+	// KeyBottomHeight is the key of the chain property for the lowest
+	// indexed height.
+	KeyBottomHeight = "txindex.bottom"
+)
+
+const (
+	addressSize  = common.AddressBytes
+	positionSize = 8 + 4
+	keySize      = addressSize + positionSize
+)
+
+func init() {
+	db.RegisterKeySize(db.TransactionLocatorByAddress, keySize)
+}
+
+// Entry is a transaction related to the address.
+type Entry struct {
+	Height int64
+	Index  int
+	TxHash []byte
+}
+
+// Cursor is the position of a transaction in the index.
+type Cursor struct {
+	Height int64
+	Index  int
+}
+
+func keyOf(addr module.Address, height int64, index int) []byte {
+	key := make([]byte, keySize)
+	copy(key, addr.Bytes())
+	binary.BigEndian.PutUint64(key[addressSize:], ^uint64(height))
+	binary.BigEndian.PutUint32(key[addressSize+8:], ^uint32(index))
+	return key
+}
+
+func cursorOf(key []byte) Cursor {
+	return Cursor{
+		Height: int64(^binary.BigEndian.Uint64(key[addressSize:])),
+		Index:  int(^binary.BigEndian.Uint32(key[addressSize+8:])),
+	}
+}
+
+// AddressesOf returns the addresses related to the transaction without
+// duplication. The receipt may be nil if it's not available.
+func AddressesOf(tx module.Transaction, r module.Receipt) []module.Address {
+	var addrs []module.Address
+	add := func(addr module.Address) {
+		if addr == nil {
+			return
+		}
+		for _, a := range addrs {
+			if a.Equal(addr) {
+				return
+			}
+		}
+		addrs = append(addrs, addr)
+	}
+	add(tx.From())
+	if r == nil {
+		return addrs
+	}
+	add(r.To())
+	for it := r.EventLogIterator(); it.Has(); _ = it.Next() {
+		el, err := it.Get()
+		if err != nil {
+			break
+		}
+		indexed := el.Indexed()
+		if len(indexed) < 2 {
+			continue
+		}
+		_, pts := txresult.DecomposeEventSignature(string(indexed[0]))
+		for i, v := range indexed[1:] {
+			if i >= len(pts) || pts[i] != "Address" {
+				continue
+			}
+			if addr, err := common.NewAddress(v); err == nil {
+				add(addr)
+			}
+		}
+	}
+	return addrs
+}
+
+// IndexBlock adds the entries of the transactions in the block to
+// the batch. rl is the list of receipts of the transactions, which comes
+// from the result of the next block.
+func IndexBlock(batch db.Batch, blk module.Block, rl module.ReceiptList) error {
+	idx := 0
+	for it := blk.NormalTransactions().Iterator(); it.Has(); _, idx = it.Next(), idx+1 {
+		tx, _, err := it.Get()
+		if err != nil {
+			return err
+		}
+		var r module.Receipt
+		if rl != nil {
+			if r, err = rl.Get(idx); err != nil {
+				return err
+			}
+		}
+		for _, addr := range AddressesOf(tx, r) {
+			batch.Set(db.TransactionLocatorByAddress,
+				keyOf(addr, blk.Height(), idx), tx.ID())
+		}
+	}
+	return nil
+}
+
+// GetTransactions returns the transactions related to the address from
+// the latest one. If cursor is not nil, then it starts from the position.
+// If there are more transactions than limit, then it also returns the
+// position of the next transaction.
+func GetTransactions(
+	dbase db.Database, addr module.Address, cursor *Cursor, limit int,
+) ([]*Entry, *Cursor, error) {
+	if limit <= 0 {
+		return nil, nil, errors.IllegalArgumentError.Errorf("InvalidLimit(limit=%d)", limit)
+	}
+	bk, err := dbase.GetBucket(db.TransactionLocatorByAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := db.PrefixRange(addr.Bytes())
+	if cursor != nil {
+		r.Start = keyOf(addr, cursor.Height, cursor.Index)
+	}
+	it := db.NewIterator(bk, r)
+	defer it.Release()
+
+	entries := []*Entry{}
+	for it.Next() {
+		key := it.Key()
+		if len(key) != keySize {
+			// entries of other buckets sharing the prefix
+			continue
+		}
+		c := cursorOf(key)
+		if len(entries) >= limit {
+			return entries, &c, nil
+		}
+		entries = append(entries, &Entry{
+			Height: c.Height,
+			Index:  c.Index,
+			TxHash: append([]byte{}, it.Value()...),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, nil, err
+	}
+	return entries, nil, nil
+}
+
+func getHeight(bk db.Bucket, key string) (int64, bool, error) {
+	bs, err := bk.Get([]byte(key))
+	if err != nil || bs == nil {
+		return 0, false, err
+	}
+	var height int64
+	if _, err := codec.BC.UnmarshalFromBytes(bs, &height); err != nil {
+		return 0, false, errors.CriticalFormatError.Wrapf(err, "InvalidProperty(key=%s)", key)
+	}
+	return height, true, nil
+}
+
+// GetIndexedRange returns the range of indexed heights. If nothing is
+// indexed, then bottom is greater than top. It returns false if the index
+// hasn't been built at all.
+func GetIndexedRange(dbase db.Database) (int64, int64, bool, error) {
+	bk, err := dbase.GetBucket(db.ChainProperty)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	top, ok, err := getHeight(bk, KeyTopHeight)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	bottom, ok, err := getHeight(bk, KeyBottomHeight)
+	if err != nil || !ok {
+		return 0, 0, false, err
+	}
+	return bottom, top, true, nil
+}
@@ -0,0 +1,347 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txindex
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+var (
+	testAddr1 = common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	testAddr2 = common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	testAddr3 = common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+	testSCORE = common.MustNewAddressFromString("cx0000000000000000000000000000000000000001")
+)
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+}
+
+func (e *testEventLog) Address() module.Address { return e.addr }
+func (e *testEventLog) Indexed() [][]byte       { return e.indexed }
+func (e *testEventLog) Data() [][]byte          { return nil }
+
+type testEventLogIterator struct {
+	logs []*testEventLog
+}
+
+func (it *testEventLogIterator) Has() bool { return len(it.logs) > 0 }
+func (it *testEventLogIterator) Next() error {
+	it.logs = it.logs[1:]
+	return nil
+}
+func (it *testEventLogIterator) Get() (module.EventLog, error) {
+	return it.logs[0], nil
+}
+
+type testReceipt struct {
+	module.Receipt
+	to   module.Address
+	logs []*testEventLog
+}
+
+func (r *testReceipt) To() module.Address { return r.to }
+func (r *testReceipt) EventLogIterator() module.EventLogIterator {
+	return &testEventLogIterator{r.logs}
+}
+
+type testReceiptList struct {
+	module.ReceiptList
+	receipts []*testReceipt
+}
+
+func (l *testReceiptList) Get(i int) (module.Receipt, error) {
+	if i < 0 || i >= len(l.receipts) {
+		return nil, errors.ErrNotFound
+	}
+	return l.receipts[i], nil
+}
+
+type testTransaction struct {
+	module.Transaction
+	id   []byte
+	from module.Address
+}
+
+func (tx *testTransaction) ID() []byte           { return tx.id }
+func (tx *testTransaction) From() module.Address { return tx.from }
+
+type testTransactionIterator struct {
+	txs []*testTransaction
+	idx int
+}
+
+func (it *testTransactionIterator) Has() bool { return it.idx < len(it.txs) }
+func (it *testTransactionIterator) Next() error {
+	it.idx++
+	return nil
+}
+func (it *testTransactionIterator) Get() (module.Transaction, int, error) {
+	return it.txs[it.idx], it.idx, nil
+}
+
+type testTransactionList struct {
+	module.TransactionList
+	txs []*testTransaction
+}
+
+func (l *testTransactionList) Iterator() module.TransactionIterator {
+	return &testTransactionIterator{txs: l.txs}
+}
+
+type testBlock struct {
+	module.Block
+	height int64
+	txs    []*testTransaction
+	rl     *testReceiptList
+}
+
+func (b *testBlock) Height() int64  { return b.height }
+func (b *testBlock) Result() []byte { return []byte(fmt.Sprint(b.height)) }
+func (b *testBlock) NormalTransactions() module.TransactionList {
+	return &testTransactionList{txs: b.txs}
+}
+
+func transferLog(from, to module.Address) *testEventLog {
+	return &testEventLog{
+		addr: testSCORE,
+		indexed: [][]byte{
+			[]byte("Transfer(Address,Address,int)"),
+			from.Bytes(),
+			to.Bytes(),
+			{0x10},
+		},
+	}
+}
+
+// newTestBlock returns a block with transactions from testAddr1. Each
+// transaction at odd index transfers tokens of testSCORE to testAddr3.
+func newTestBlock(height int64, n int) *testBlock {
+	blk := &testBlock{
+		height: height,
+		rl:     new(testReceiptList),
+	}
+	for i := 0; i < n; i++ {
+		blk.txs = append(blk.txs, &testTransaction{
+			id:   []byte(fmt.Sprintf("tx%d-%d", height, i)),
+			from: testAddr1,
+		})
+		r := &testReceipt{to: testAddr2}
+		if i%2 == 1 {
+			r.to = testSCORE
+			r.logs = []*testEventLog{transferLog(testAddr1, testAddr3)}
+		}
+		blk.rl.receipts = append(blk.rl.receipts, r)
+	}
+	return blk
+}
+
+func TestAddressesOf(t *testing.T) {
+	tx := &testTransaction{from: testAddr1}
+	assert.Equal(t, []module.Address{testAddr1}, AddressesOf(tx, nil))
+
+	r := &testReceipt{
+		to: testSCORE,
+		logs: []*testEventLog{
+			transferLog(testAddr1, testAddr2),
+			transferLog(testAddr2, testAddr3),
+			{
+				addr: testSCORE,
+				indexed: [][]byte{
+					[]byte("Message(str,Address)"),
+					testAddr3.Bytes(),
+					testAddr3.Bytes(),
+				},
+			},
+		},
+	}
+	assert.Equal(t,
+		[]module.Address{testAddr1, testSCORE, testAddr2, testAddr3},
+		AddressesOf(tx, r))
+}
+
+func testTransactionsOf(
+	t *testing.T, dbase db.Database, addr module.Address, limit int,
+) []string {
+	var txs []string
+	var cursor *Cursor
+	for {
+		entries, next, err := GetTransactions(dbase, addr, cursor, limit)
+		assert.NoError(t, err)
+		if next != nil {
+			assert.Len(t, entries, limit)
+		}
+		for _, e := range entries {
+			assert.Equal(t, fmt.Sprintf("tx%d-%d", e.Height, e.Index), string(e.TxHash))
+			txs = append(txs, string(e.TxHash))
+		}
+		if next == nil {
+			return txs
+		}
+		cursor = next
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	dbase := db.NewMapDB()
+	batch := db.NewBatch(dbase)
+	for h := int64(1); h <= 3; h++ {
+		blk := newTestBlock(h, int(h))
+		assert.NoError(t, IndexBlock(batch, blk, blk.rl))
+	}
+	assert.NoError(t, batch.Write())
+
+	all := []string{"tx3-2", "tx3-1", "tx3-0", "tx2-1", "tx2-0", "tx1-0"}
+	for _, limit := range []int{1, 2, 4, 10} {
+		assert.Equal(t, all, testTransactionsOf(t, dbase, testAddr1, limit))
+	}
+	assert.Equal(t, []string{"tx3-2", "tx3-0", "tx2-0", "tx1-0"},
+		testTransactionsOf(t, dbase, testAddr2, 3))
+	assert.Equal(t, []string{"tx3-1", "tx2-1"},
+		testTransactionsOf(t, dbase, testAddr3, 1))
+	assert.Equal(t, []string{"tx3-1", "tx2-1"},
+		testTransactionsOf(t, dbase, testSCORE, 10))
+
+	entries, next, err := GetTransactions(dbase, testAddr1, &Cursor{Height: 2, Index: 0}, 10)
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Len(t, entries, 2)
+
+	_, _, err = GetTransactions(dbase, testAddr1, nil, 0)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
+
+type testBlockManager struct {
+	module.BlockManager
+	lock   sync.Mutex
+	blocks map[int64]*testBlock
+	last   int64
+	waitCh chan module.Block
+}
+
+func (bm *testBlockManager) addBlock(blk *testBlock) {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+	bm.blocks[blk.height] = blk
+}
+
+func (bm *testBlockManager) block(height int64) (*testBlock, bool) {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+	blk, ok := bm.blocks[height]
+	return blk, ok
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+	return bm.blocks[bm.last], nil
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if blk, ok := bm.block(height); ok {
+		return blk, nil
+	}
+	return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+}
+
+func (bm *testBlockManager) WaitForBlock(height int64) (<-chan module.Block, error) {
+	ch := make(chan module.Block, 1)
+	if blk, ok := bm.block(height); ok {
+		ch <- blk
+		return ch, nil
+	}
+	return bm.waitCh, nil
+}
+
+type testServiceManager struct {
+	module.ServiceManager
+	bm *testBlockManager
+}
+
+func (sm *testServiceManager) ReceiptListFromResult(result []byte, g module.TransactionGroup) (module.ReceiptList, error) {
+	var height int64
+	fmt.Sscan(string(result), &height)
+	// receipts of the transactions in the previous block
+	blk, _ := sm.bm.block(height - 1)
+	return blk.rl, nil
+}
+
+func TestIndexer(t *testing.T) {
+	dbase := db.NewMapDB()
+	bm := &testBlockManager{
+		blocks: map[int64]*testBlock{},
+		waitCh: make(chan module.Block, 1),
+	}
+	for h := int64(0); h <= 2; h++ {
+		bm.blocks[h] = newTestBlock(h, 1)
+	}
+	bm.last = 2
+	sm := &testServiceManager{bm: bm}
+
+	ix := NewIndexer(dbase, bm, sm, 0, log.GlobalLogger())
+	assert.NoError(t, ix.Start())
+
+	// backfills the blocks before the start
+	assert.Eventually(t, func() bool {
+		bottom, top, err := ix.Status()
+		return err == nil && bottom == 0 && top == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// indexes the block on finalization of the next block
+	blk := newTestBlock(3, 2)
+	bm.addBlock(blk)
+	bm.waitCh <- blk
+	assert.Eventually(t, func() bool {
+		_, top, _ := ix.Status()
+		return top == 2
+	}, time.Second, 10*time.Millisecond)
+	ix.Term()
+
+	bottom, top, ok, err := GetIndexedRange(dbase)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), bottom)
+	assert.Equal(t, int64(2), top)
+	assert.Equal(t, []string{"tx2-0", "tx1-0", "tx0-0"},
+		testTransactionsOf(t, dbase, testAddr1, 2))
+
+	// continues from the last indexed block
+	bm.addBlock(newTestBlock(4, 1))
+	bm.last = 4
+	ix = NewIndexer(dbase, bm, sm, 0, log.GlobalLogger())
+	assert.NoError(t, ix.Start())
+	assert.Eventually(t, func() bool {
+		_, top, _ := ix.Status()
+		return top == 3
+	}, time.Second, 10*time.Millisecond)
+	ix.Term()
+	assert.Equal(t, []string{"tx3-1", "tx3-0", "tx2-0", "tx1-0", "tx0-0"},
+		testTransactionsOf(t, dbase, testAddr1, 10))
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package txindex
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+// Indexer maintains the index while the chain is running. It indexes
+// finalized blocks and backfills the blocks finalized before the index
+// is enabled in the background.
+type Indexer struct {
+	dbase db.Database
+	bm    module.BlockManager
+	sm    module.ServiceManager
+	base  int64
+	log   log.Logger
+
+	lock   sync.Mutex
+	top    int64
+	bottom int64
+	err    error
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewIndexer returns a new indexer. base is the lowest height of the blocks
+// available in the chain.
+func NewIndexer(
+	dbase db.Database, bm module.BlockManager, sm module.ServiceManager,
+	base int64, logger log.Logger,
+) *Indexer {
+	return &Indexer{
+		dbase: dbase,
+		bm:    bm,
+		sm:    sm,
+		base:  base,
+		log:   logger,
+	}
+}
+
+// Start starts to index blocks. If the index is empty, then it starts from
+// the last finalized block. Transactions in a block are indexed after
+// the next block is finalized, because their receipts are in the result
+// of the next block.
+func (ix *Indexer) Start() error {
+	bottom, top, ok, err := GetIndexedRange(ix.dbase)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		blk, err := ix.bm.GetLastBlock()
+		if err != nil {
+			return err
+		}
+		bottom = blk.Height()
+		top = bottom - 1
+		batch := db.NewBatch(ix.dbase)
+		setHeight(batch, KeyTopHeight, top)
+		setHeight(batch, KeyBottomHeight, bottom)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	ix.top, ix.bottom = top, bottom
+	ix.stop = make(chan struct{})
+	ix.log.Infof("TxIndex START indexed=[%d,%d]", bottom, top)
+
+	ix.wg.Add(2)
+	go ix.follow()
+	go ix.backfill()
+	return nil
+}
+
+// Term stops indexing and waits for the workers.
+func (ix *Indexer) Term() {
+	if ix.stop == nil {
+		return
+	}
+	close(ix.stop)
+	ix.wg.Wait()
+	ix.stop = nil
+}
+
+// Status returns the range of indexed heights and the error stopping
+// the workers.
+func (ix *Indexer) Status() (int64, int64, error) {
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+	return ix.bottom, ix.top, ix.err
+}
+
+func setHeight(batch db.Batch, key string, height int64) {
+	batch.Set(db.ChainProperty, []byte(key), codec.BC.MustMarshalToBytes(height))
+}
+
+func (ix *Indexer) indexBlock(blk, rblk module.Block, key string) error {
+	rl, err := ix.sm.ReceiptListFromResult(rblk.Result(), module.TransactionGroupNormal)
+	if err != nil {
+		return err
+	}
+	batch := db.NewBatch(ix.dbase)
+	if err := IndexBlock(batch, blk, rl); err != nil {
+		return err
+	}
+	setHeight(batch, key, blk.Height())
+	return batch.Write()
+}
+
+func (ix *Indexer) setError(err error) {
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+	if ix.err == nil {
+		ix.err = err
+	}
+}
+
+// follow indexes finalized blocks.
+func (ix *Indexer) follow() {
+	defer ix.wg.Done()
+	for {
+		ix.lock.Lock()
+		height := ix.top + 1
+		ix.lock.Unlock()
+
+		bch, err := ix.bm.WaitForBlock(height + 1)
+		if err != nil {
+			ix.log.Warnf("TxIndex fail to wait block height=%d err=%+v", height+1, err)
+			ix.setError(err)
+			return
+		}
+		var rblk module.Block
+		select {
+		case rblk = <-bch:
+			if rblk == nil {
+				return
+			}
+		case <-ix.stop:
+			return
+		}
+		blk, err := ix.bm.GetBlockByHeight(height)
+		if err == nil {
+			err = ix.indexBlock(blk, rblk, KeyTopHeight)
+		}
+		if err != nil {
+			ix.log.Warnf("TxIndex fail to index height=%d err=%+v", height, err)
+			ix.setError(err)
+			return
+		}
+		ix.lock.Lock()
+		ix.top = height
+		ix.lock.Unlock()
+	}
+}
+
+// backfill indexes the blocks finalized before the indexer started.
+func (ix *Indexer) backfill() {
+	defer ix.wg.Done()
+	for {
+		ix.lock.Lock()
+		height := ix.bottom - 1
+		ix.lock.Unlock()
+
+		if height < ix.base {
+			ix.log.Infof("TxIndex BACKFILL DONE height=%d", height+1)
+			return
+		}
+		select {
+		case <-ix.stop:
+			return
+		default:
+		}
+		blk, err := ix.bm.GetBlockByHeight(height)
+		var rblk module.Block
+		if err == nil {
+			rblk, err = ix.bm.GetBlockByHeight(height + 1)
+		}
+		if err == nil {
+			err = ix.indexBlock(blk, rblk, KeyBottomHeight)
+		}
+		if err != nil {
+			if errors.NotFoundError.Equals(err) {
+				// blocks before the base of pruned chains.
+				ix.log.Infof("TxIndex BACKFILL STOP height=%d err=%v", height, err)
+			} else {
+				ix.log.Warnf("TxIndex fail to backfill height=%d err=%+v", height, err)
+				ix.setError(err)
+			}
+			return
+		}
+		ix.lock.Lock()
+		ix.bottom = height
+		ix.lock.Unlock()
+	}
+}
@@ -0,0 +1,95 @@
+package common
+
+import (
+	"sync"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Watchers delivers values to registered channels. Notify may be called
+// while the caller holds its own lock, so it never blocks on the channels.
+// A channel which is full is closed and unregistered.
+type Watchers[T any] struct {
+	lock   sync.Mutex
+	closed bool
+	chans  []chan<- T
+}
+
+// Watch registers ch and returns a function to unregister it. It fails
+// after Close.
+func (ws *Watchers[T]) Watch(ch chan<- T) (func(), error) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if ws.closed {
+		return nil, errors.InvalidStateError.New("WatchersClosed")
+	}
+	ws.chans = append(ws.chans, ch)
+	return func() {
+		ws.lock.Lock()
+		defer ws.lock.Unlock()
+		ws.removeInLock(ch)
+	}, nil
+}
+
+func (ws *Watchers[T]) removeInLock(ch chan<- T) bool {
+	for i, e := range ws.chans {
+		if e == ch {
+			last := len(ws.chans) - 1
+			ws.chans[i] = ws.chans[last]
+			ws.chans[last] = nil
+			ws.chans = ws.chans[:last]
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of registered channels.
+func (ws *Watchers[T]) Len() int {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	return len(ws.chans)
+}
+
+// Notify sends v to the registered channels.
+func (ws *Watchers[T]) Notify(v T) {
+	ws.NotifyFunc(func() T { return v })
+}
+
+// NotifyFunc sends the value returned by f to the registered channels.
+// f is called only if there is a registered channel.
+func (ws *Watchers[T]) NotifyFunc(f func() T) {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if len(ws.chans) == 0 {
+		return
+	}
+	v := f()
+	var overflows []chan<- T
+	for _, ch := range ws.chans {
+		select {
+		case ch <- v:
+		default:
+			overflows = append(overflows, ch)
+		}
+	}
+	for _, ch := range overflows {
+		ws.removeInLock(ch)
+		close(ch)
+	}
+}
+
+// Close closes all registered channels. Following Watch calls fail.
+func (ws *Watchers[T]) Close() {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	for _, ch := range ws.chans {
+		close(ch)
+	}
+	ws.chans = nil
+	ws.closed = true
+}
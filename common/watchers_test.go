@@ -0,0 +1,56 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchers(t *testing.T) {
+	var ws Watchers[int]
+
+	ch1 := make(chan int, 1)
+	cancel1, err := ws.Watch(ch1)
+	assert.NoError(t, err)
+	ch2 := make(chan int, 2)
+	cancel2, err := ws.Watch(ch2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ws.Len())
+
+	ws.Notify(1)
+	assert.Equal(t, 1, <-ch1)
+	assert.Equal(t, 1, <-ch2)
+
+	// ch1 is full on the second notification, so it's closed.
+	ws.Notify(2)
+	ws.Notify(3)
+	assert.Equal(t, 2, <-ch1)
+	_, ok := <-ch1
+	assert.False(t, ok)
+	assert.Equal(t, 2, <-ch2)
+	assert.Equal(t, 3, <-ch2)
+	assert.Equal(t, 1, ws.Len())
+	cancel1()
+
+	// no more notification after cancel
+	cancel2()
+	assert.Equal(t, 0, ws.Len())
+	called := false
+	ws.NotifyFunc(func() int {
+		called = true
+		return 4
+	})
+	assert.False(t, called)
+	assert.Len(t, ch2, 0)
+
+	// channels are closed on close
+	ch3 := make(chan int, 1)
+	_, err = ws.Watch(ch3)
+	assert.NoError(t, err)
+	ws.Close()
+	_, ok = <-ch3
+	assert.False(t, ok)
+
+	_, err = ws.Watch(make(chan int, 1))
+	assert.Error(t, err)
+}
@@ -96,6 +96,8 @@ type consensus struct {
 	bpmCache       bpmCache
 	timeoutPropose time.Duration
 	dsmLog         dsmLog
+	timeouts       module.ConsensusTimeouts
+	stepWatchers   common.Watchers[*module.ConsensusStepEvent]
 
 	lastBlock          module.Block
 	validators         module.ValidatorList
@@ -131,6 +133,9 @@ type consensus struct {
 	metric *metric.ConsensusMetric
 
 	lastVoteData *LastVoteData
+
+	// wallet for signing proposals and votes
+	wallet module.Wallet
 }
 
 func NewConsensus(
@@ -177,6 +182,18 @@ func New(
 	cs.log = c.Logger().WithFields(log.Fields{
 		log.FieldKeyModule: "CS",
 	})
+	cs.timeouts = cs.timeoutsFor(nil)
+	wallet := c.Wallet()
+	if spc, ok := c.(SlashingProtectedChain); ok {
+		if sp := spc.SlashingProtection(); sp != nil {
+			wallet = &protectedWallet{
+				Wallet: wallet,
+				sp:     sp,
+				log:    cs.log,
+			}
+		}
+	}
+	cs.wallet = NewChainWallet(wallet, c.NID())
 
 	return cs
 }
@@ -212,6 +229,7 @@ func (cs *consensus) _resetForNewHeight(prevBlock module.Block, votes *voteSet)
 	}
 	cs.minimizeBlockGen = cs.c.ServiceManager().GetMinimizeBlockGen(cs.lastBlock.Result())
 	cs.roundLimit = int32(cs.c.ServiceManager().GetRoundLimit(cs.lastBlock.Result(), cs.validators.Len()))
+	cs.timeouts = cs.timeoutsFor(cs.c.ServiceManager().GetConsensusTimeouts(cs.lastBlock.Result()))
 	cs.sentPatch = false
 	cs.lastVotes = votes
 	cs.hvs.reset(cs.validators.Len())
@@ -291,6 +309,7 @@ func (cs *consensus) beginStep(step step) {
 	}
 	cs.step = step
 	cs.log.Debugf("enterStep %v\n", cs.hrs)
+	cs.notifyStep()
 }
 
 func (cs *consensus) OnReceive(
@@ -515,6 +534,9 @@ func (cs *consensus) ReceiveVoteMessage(msg *VoteMessage, unicast bool) (int, er
 	if !added {
 		return -1, nil
 	}
+	if msg.Round == cs.round {
+		cs.recordVoteMetric()
+	}
 	if !unicast {
 		cs.consumedNonunicast = true
 	}
@@ -629,14 +651,14 @@ func (cs *consensus) enterPropose() {
 
 	now := time.Now()
 	if int(cs.round) > cs.validators.Len()*configRoundTimeoutThresholdFactor {
-		cs.nextProposeTime = now.Add(timeoutNewRound)
+		cs.nextProposeTime = now.Add(roundTimeout(cs.timeouts.NewRound, cs.timeouts.NewRoundDelta, cs.round))
 	} else {
 		cs.nextProposeTime = now
 	}
 	cs.c.Regulator().OnPropose(now)
 
 	hrs := cs.hrs
-	cs.timer = time.AfterFunc(cs.timeoutPropose, func() {
+	cs.timer = time.AfterFunc(roundTimeout(cs.timeouts.Propose, cs.timeouts.ProposeDelta, cs.round), func() {
 		cs.mutex.Lock()
 		defer cs.mutex.Unlock()
 
@@ -822,7 +844,7 @@ func (cs *consensus) enterPrevoteWait() {
 		cs.enterPrecommit()
 	} else {
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrevote, func() {
+		cs.timer = time.AfterFunc(roundTimeout(cs.timeouts.Prevote, cs.timeouts.PrevoteDelta, cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -922,7 +944,7 @@ func (cs *consensus) enterPrecommitWait() {
 	} else {
 		cs.log.Traceln("enterPrecommitWait: start timer")
 		hrs := cs.hrs
-		cs.timer = time.AfterFunc(timeoutPrecommit, func() {
+		cs.timer = time.AfterFunc(roundTimeout(cs.timeouts.Precommit, cs.timeouts.PrecommitDelta, cs.round), func() {
 			cs.mutex.Lock()
 			defer cs.mutex.Unlock()
 
@@ -1117,7 +1139,7 @@ func (cs *consensus) doSendProposal(blockParts PartSet, polRound int32) error {
 	msg.BlockPartSetID = blockParts.ID()
 	msg.POLRound = polRound
 	msg.NID = cs.nidForCSMessage()
-	err := msg.Sign(cs.c.Wallet())
+	err := msg.Sign(cs.wallet)
 	if err != nil {
 		return err
 	}
@@ -1280,7 +1302,7 @@ func (cs *consensus) doSendVote(vt VoteType, blockParts *blockPartSet) error {
 	}
 	msg.Timestamp = cs.voteTimestamp()
 
-	err := msg.Sign(cs.c.Wallet())
+	err := msg.Sign(cs.wallet)
 	if err != nil {
 		return err
 	}
@@ -1908,6 +1930,7 @@ func (cs *consensus) Term() {
 	if cs.commitWAL != nil {
 		cs.log.Must(cs.commitWAL.Close())
 	}
+	cs.stepWatchers.Close()
 
 	if cs.log != nil {
 		cs.log.Infof("Term consensus.\n")
@@ -15,8 +15,37 @@ import (
 const (
 	configSendInterval      = time.Millisecond * 100
 	configTimeout           = time.Millisecond * 3500
-	configMaxPendingResults = 10
-	configMaxActive         = 3
+	configMaxPendingResults = 64
+	configMaxActive         = 8
+
+	// configMaxWindow is the maximum number of outstanding requests for
+	// a peer. Server ignores requests more than maxNextItems.
+	configMaxWindow = maxNextItems
+
+	// configWindowDuration is the duration of block transfers to keep in
+	// flight for a peer. Window of a peer is the number of blocks it can
+	// send in the duration.
+	configWindowDuration = time.Second
+
+	// configMinSamples is the number of received blocks to measure
+	// throughput of a peer before comparing it with others.
+	configMinSamples = 4
+
+	// configSlowPeerRatio is the ratio of throughput of the best peer to
+	// the throughput of a peer to be evicted as a slow peer.
+	configSlowPeerRatio = 8
+
+	// configMinPeers is the number of peers kept regardless of their
+	// throughput.
+	configMinPeers = 2
+
+	// configBanDuration is the duration of excluding a peer sent invalid
+	// block from fetch requests.
+	configBanDuration = time.Minute
+
+	configEWMAWeight    = 0.25
+	configRateSamples   = 32
+	configRateMinPeriod = time.Millisecond
 )
 
 type client struct {
@@ -28,6 +57,10 @@ type client struct {
 
 	fetchID uint16
 	fr      *fetchRequest
+
+	// banned is the time until which the peer is excluded from fetch
+	// requests.
+	banned map[string]time.Time
 }
 
 type blockResult struct {
@@ -58,6 +91,7 @@ func (br *blockResult) Consume() {
 	}
 
 	fr.consumeOffset++
+	fr.recordConsume(time.Now())
 	copy(fr.pendingResults, fr.pendingResults[1:])
 	fr.pendingResults[len(fr.pendingResults)-1] = nil
 	fr._reschedule()
@@ -84,28 +118,13 @@ func (br *blockResult) Reject() {
 		return
 	}
 
-	for i, p := range fr.validPeers {
-		if p.id.Equal(br.id) {
-			last := len(fr.validPeers) - 1
-			fr.validPeers[i] = fr.validPeers[last]
-			fr.validPeers[last] = nil
-			fr.validPeers = fr.validPeers[:last]
-			if p.f != nil {
-				p.f.cancel()
-				fr.nActivePeers--
-				fr._reschedule()
-			}
-			break
-		}
+	cl._ban(br.id)
+	if _, p := fr._findPeer(br.id); p != nil {
+		fr._evict(p)
 	}
 	fr.pendingResults[0] = nil
 	fr.heightSet.add(br.blk.Height())
-	for i := 1; i < len(fr.pendingResults); i++ {
-		if fr.pendingResults[i] != nil && fr.pendingResults[i].id.Equal(br.id) {
-			fr.heightSet.add(fr.pendingResults[i].blk.Height())
-			fr.pendingResults[i] = nil
-		}
-	}
+	fr._dropResultsFrom(br.id)
 	if len(fr.validPeers) != 0 {
 		fr._reschedule()
 	} else {
@@ -119,7 +138,96 @@ func (br *blockResult) Reject() {
 type peer struct {
 	id        module.PeerID
 	requestID uint16
-	f         *fetcher
+
+	// fetchers are outstanding requests in order of sending. Server
+	// handles requests in order, so the first one is being served.
+	fetchers []*fetcher
+
+	// samples is the number of blocks received from the peer.
+	samples int
+	// blockTime is the moving average of time to receive a block.
+	blockTime time.Duration
+	// throughput is the moving average of received bytes per second.
+	throughput float64
+}
+
+func newPeer(id module.PeerID) *peer {
+	return &peer{id: id}
+}
+
+// window returns the number of requests to keep in flight for the peer.
+// It starts with one request, and doubles on each received block up to
+// the number of blocks the peer can send in configWindowDuration.
+func (p *peer) window() int {
+	if p.samples == 0 || p.blockTime <= 0 {
+		return 1
+	}
+	w := 1 + int(configWindowDuration/p.blockTime)
+	if p.samples < 8 && w > 1<<p.samples {
+		w = 1 << p.samples
+	}
+	if w > configMaxWindow {
+		return configMaxWindow
+	}
+	return w
+}
+
+// cost returns the expected time for the peer to deliver a new request.
+// For the peer without samples, avg is used as its block time.
+func (p *peer) cost(avg time.Duration) time.Duration {
+	bt := p.blockTime
+	if p.samples == 0 {
+		bt = avg
+	}
+	return time.Duration(len(p.fetchers)+1) * bt
+}
+
+func (p *peer) canRequest() bool {
+	if len(p.fetchers) >= p.window() {
+		return false
+	}
+	// keep order of requests in the server queue
+	if l := len(p.fetchers); l > 0 && p.fetchers[l-1].step == fstepSend {
+		return false
+	}
+	return true
+}
+
+func (p *peer) onBlock(size int, d time.Duration) {
+	if d < configRateMinPeriod {
+		d = configRateMinPeriod
+	}
+	tp := float64(size) / d.Seconds()
+	if p.samples == 0 {
+		p.blockTime = d
+		p.throughput = tp
+	} else {
+		p.blockTime = time.Duration(float64(p.blockTime)*(1-configEWMAWeight) + float64(d)*configEWMAWeight)
+		p.throughput = p.throughput*(1-configEWMAWeight) + tp*configEWMAWeight
+	}
+	p.samples++
+}
+
+func (p *peer) removeFetcher(f *fetcher) bool {
+	for i, pf := range p.fetchers {
+		if pf == f {
+			p.fetchers = append(p.fetchers[:i], p.fetchers[i+1:]...)
+			if i == 0 && len(p.fetchers) > 0 {
+				p.fetchers[0]._onHead()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (p *peer) fetcherFor(requestID uint32) *fetcher {
+	for _, f := range p.fetchers {
+		if f.requestID == requestID {
+			return f
+		}
+	}
+	return nil
 }
 
 type fetchRequest struct {
@@ -127,11 +235,15 @@ type fetchRequest struct {
 	heightSet *heightSet
 	cb        FetchCallback
 	maxActive int
+	begin     int64
 
 	validPeers     []*peer
-	nActivePeers   int
 	consumeOffset  int64
 	pendingResults []*blockResult
+
+	// consumeTimes are the last times of consuming blocks for measuring
+	// the rate.
+	consumeTimes []time.Time
 }
 
 func newClient(nm module.NetworkManager, ph module.ProtocolHandler,
@@ -141,9 +253,26 @@ func newClient(nm module.NetworkManager, ph module.ProtocolHandler,
 	cl.ph = ph
 	cl.bm = bm
 	cl.log = logger
+	cl.banned = make(map[string]time.Time)
 	return cl
 }
 
+func (cl *client) _ban(id module.PeerID) {
+	cl.log.Debugf("ban peer:%s\n", common.HexPre(id.Bytes()))
+	cl.banned[string(id.Bytes())] = time.Now().Add(configBanDuration)
+}
+
+func (cl *client) _isBanned(id module.PeerID) bool {
+	key := string(id.Bytes())
+	if until, ok := cl.banned[key]; ok {
+		if time.Now().Before(until) {
+			return true
+		}
+		delete(cl.banned, key)
+	}
+	return false
+}
+
 func (cl *client) fetchBlocks(
 	begin int64,
 	end int64,
@@ -165,20 +294,51 @@ func (cl *client) fetchBlocks(
 	fr.heightSet = newHeightSet(begin, end)
 	fr.cb = cb
 	fr.maxActive = configMaxActive
+	fr.begin = begin
 
 	peerIDs := cl.ph.GetPeers()
-	fr.validPeers = make([]*peer, len(peerIDs))
-	for i, id := range peerIDs {
-		fr.validPeers[i] = &peer{id, 0, nil}
+	fr.validPeers = make([]*peer, 0, len(peerIDs))
+	for _, id := range peerIDs {
+		if !cl._isBanned(id) {
+			fr.validPeers = append(fr.validPeers, newPeer(id))
+		}
 	}
-	fr.nActivePeers = 0
 	fr.consumeOffset = begin
 	fr.pendingResults = make([]*blockResult, configMaxPendingResults)
-	fr._reschedule()
 	cl.fr = fr
+	fr._reschedule()
 	return fr, nil
 }
 
+func (cl *client) fetchStatus() *FetchStatus {
+	cl.Lock()
+	defer cl.Unlock()
+
+	fr := cl.fr
+	if fr == nil {
+		return nil
+	}
+	st := &FetchStatus{
+		Begin: fr.begin,
+		End:   fr.heightSet.end,
+		Next:  fr.consumeOffset,
+		Peers: len(fr.validPeers),
+		Rate:  fr.rate(),
+	}
+	for _, p := range fr.validPeers {
+		if len(p.fetchers) > 0 {
+			st.ActivePeers++
+			st.Requests += len(p.fetchers)
+		}
+	}
+	for _, r := range fr.pendingResults {
+		if r != nil {
+			st.Buffered++
+		}
+	}
+	return st
+}
+
 func (cl *client) onReceive(pi module.ProtocolInfo, b []byte, id module.PeerID) {
 	cl.Lock()
 	defer cl.Unlock()
@@ -187,11 +347,27 @@ func (cl *client) onReceive(pi module.ProtocolInfo, b []byte, id module.PeerID)
 	if fr == nil {
 		return
 	}
-	for _, p := range fr.validPeers {
-		if p.f != nil && p.id.Equal(id) {
-			p.f.onReceive(pi, b)
+	_, p := fr._findPeer(id)
+	if p == nil || len(p.fetchers) == 0 {
+		return
+	}
+	switch pi {
+	case ProtoBlockMetadata:
+		var msg BlockMetadata
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
 			return
 		}
+		if f := p.fetcherFor(msg.RequestID); f != nil {
+			f.onBlockMetadata(&msg)
+		}
+	case ProtoBlockData:
+		var msg BlockData
+		if _, err := codec.UnmarshalFromBytes(b, &msg); err != nil {
+			return
+		}
+		if f := p.fetcherFor(msg.RequestID); f != nil {
+			f.onBlockData(&msg)
+		}
 	}
 }
 
@@ -203,13 +379,13 @@ func (cl *client) onJoin(id module.PeerID) {
 	if fr == nil {
 		return
 	}
-	for _, p := range fr.validPeers {
-		if p.id.Equal(id) {
-			return
-		}
+	if _, p := fr._findPeer(id); p != nil {
+		return
+	}
+	if cl._isBanned(id) {
+		return
 	}
-	peer := &peer{id, 0, nil}
-	fr.validPeers = append(fr.validPeers, peer)
+	fr.validPeers = append(fr.validPeers, newPeer(id))
 	fr._reschedule()
 }
 
@@ -221,60 +397,149 @@ func (cl *client) onLeave(id module.PeerID) {
 	if fr == nil {
 		return
 	}
+	if _, p := fr._findPeer(id); p != nil {
+		fr._evict(p)
+		fr._reschedule()
+	}
+}
+
+func (fr *fetchRequest) _findPeer(id module.PeerID) (int, *peer) {
 	for i, p := range fr.validPeers {
 		if p.id.Equal(id) {
-			last := len(fr.validPeers) - 1
-			fr.validPeers[i] = fr.validPeers[last]
-			fr.validPeers[last] = nil
-			fr.validPeers = fr.validPeers[:last]
-			if p.f != nil {
-				p.f.cancel()
-				fr.heightSet.add(p.f.height)
-				fr.nActivePeers--
-				fr._reschedule()
-			}
-			return
+			return i, p
 		}
 	}
+	return -1, nil
 }
 
-func (fr *fetchRequest) _reschedule() {
-	for {
-		if fr.nActivePeers >= fr.maxActive {
-			return
+// _evict removes the peer from valid peers, and cancels its requests.
+// Heights of the requests are returned to the height set.
+func (fr *fetchRequest) _evict(p *peer) {
+	i, _ := fr._findPeer(p.id)
+	if i < 0 {
+		return
+	}
+	last := len(fr.validPeers) - 1
+	fr.validPeers[i] = fr.validPeers[last]
+	fr.validPeers[last] = nil
+	fr.validPeers = fr.validPeers[:last]
+	if len(p.fetchers) > 0 {
+		for _, f := range p.fetchers {
+			fr.heightSet.add(f.height)
 		}
-		if len(fr.validPeers) == fr.nActivePeers {
-			return
+		fr.cl._cancelPeer(p)
+	}
+}
+
+// _dropResultsFrom drops pending results from the peer except the first
+// one, and returns their heights to the height set.
+func (fr *fetchRequest) _dropResultsFrom(id module.PeerID) {
+	for i := 1; i < len(fr.pendingResults); i++ {
+		ri := fr.pendingResults[i]
+		if ri != nil && ri.id.Equal(id) {
+			fr.pendingResults[i] = nil
+			fr.heightSet.add(ri.blk.Height())
+		}
+	}
+}
+
+func (fr *fetchRequest) _activePeers() int {
+	cnt := 0
+	for _, p := range fr.validPeers {
+		if len(p.fetchers) > 0 {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// _pickPeer returns the peer expected to deliver a new request earliest.
+func (fr *fetchRequest) _pickPeer() *peer {
+	var sum time.Duration
+	var cnt int
+	for _, p := range fr.validPeers {
+		if p.samples > 0 {
+			sum += p.blockTime
+			cnt++
 		}
+	}
+	var avg time.Duration
+	if cnt > 0 {
+		avg = sum / time.Duration(cnt)
+	}
+	active := fr._activePeers()
+	var best *peer
+	var bestCost time.Duration
+	for _, p := range fr.validPeers {
+		if !p.canRequest() {
+			continue
+		}
+		if len(p.fetchers) == 0 && active >= fr.maxActive {
+			continue
+		}
+		c := p.cost(avg)
+		if best == nil || c < bestCost {
+			best = p
+			bestCost = c
+		}
+	}
+	return best
+}
+
+func (fr *fetchRequest) _reschedule() {
+	for {
 		l, ok := fr.heightSet.getLowest()
 		if !ok || fr.consumeOffset+int64(len(fr.pendingResults)) <= l {
 			return
 		}
-		var peer *peer
-		for _, p := range fr.validPeers {
-			if p.f == nil {
-				peer = p
-				break
-			}
-		}
-		if peer == nil {
-			panic("wrong validPeers state")
+		p := fr._pickPeer()
+		if p == nil {
+			return
 		}
-		requestID := uint32(fr.cl.fetchID)<<16 | uint32(peer.requestID)
-		peer.f = fr.newFetcher(peer.id, l, requestID)
-		peer.requestID++
 		fr.heightSet.popLowest()
-		fr.nActivePeers++
+		requestID := uint32(fr.cl.fetchID)<<16 | uint32(p.requestID)
+		p.requestID++
+		f := fr.newFetcher(p, l, requestID)
+		p.fetchers = append(p.fetchers, f)
+		f._doSend()
 	}
 }
 
-func (cl *client) _findPeerByFetcher(f *fetcher) (int, *peer) {
-	for i, p := range cl.fr.validPeers {
-		if p.f == f {
-			return i, p
+// _isSlow returns whether the peer is too slow comparing with the best
+// peer.
+func (fr *fetchRequest) _isSlow(p *peer) bool {
+	if p.samples < configMinSamples || len(fr.validPeers) <= configMinPeers {
+		return false
+	}
+	var best float64
+	for _, op := range fr.validPeers {
+		if op.samples >= configMinSamples && op.throughput > best {
+			best = op.throughput
 		}
 	}
-	return -1, nil
+	return p.throughput*configSlowPeerRatio < best
+}
+
+func (fr *fetchRequest) recordConsume(t time.Time) {
+	if len(fr.consumeTimes) >= configRateSamples {
+		copy(fr.consumeTimes, fr.consumeTimes[1:])
+		fr.consumeTimes[len(fr.consumeTimes)-1] = t
+	} else {
+		fr.consumeTimes = append(fr.consumeTimes, t)
+	}
+}
+
+// rate returns the number of consumed blocks per second.
+func (fr *fetchRequest) rate() float64 {
+	n := len(fr.consumeTimes)
+	if n < 2 {
+		return 0
+	}
+	d := fr.consumeTimes[n-1].Sub(fr.consumeTimes[0])
+	if d < configRateMinPeriod {
+		d = configRateMinPeriod
+	}
+	return float64(n-1) / d.Seconds()
 }
 
 func (cl *client) onResult(f *fetcher, err error, blk module.BlockData, votes []byte) {
@@ -292,25 +557,17 @@ func (cl *client) onResult(f *fetcher, err error, blk module.BlockData, votes []
 		return
 	}
 
+	p := f.p
+	if _, vp := fr._findPeer(p.id); vp != p {
+		return
+	}
 	if err != nil {
-		i, p := cl._findPeerByFetcher(f)
-		if p == nil {
-			return
+		if isBadData(err) {
+			cl._ban(p.id)
 		}
-		fr.nActivePeers--
-		last := len(fr.validPeers) - 1
-		fr.validPeers[i] = fr.validPeers[last]
-		fr.validPeers[last] = nil
-		fr.validPeers = fr.validPeers[:last]
-		fr.heightSet.add(f.height)
+		fr._evict(p)
 		if !isNoBlock(err) {
-			for i := 1; i < len(fr.pendingResults); i++ {
-				ri := fr.pendingResults[i]
-				if fr.pendingResults[i] != nil && fr.pendingResults[i].id.Equal(f.id) {
-					fr.pendingResults[i] = nil
-					fr.heightSet.add(ri.blk.Height())
-				}
-			}
+			fr._dropResultsFrom(p.id)
 		}
 		if len(fr.validPeers) != 0 {
 			fr._reschedule()
@@ -324,21 +581,23 @@ func (cl *client) onResult(f *fetcher, err error, blk module.BlockData, votes []
 		}
 		return
 	}
-	_, p := cl._findPeerByFetcher(f)
-	if p == nil {
+	if !p.removeFetcher(f) {
 		return
 	}
-	cl.log.Tracef("height=%d consumeOffset=%d\n", f.height, fr.consumeOffset)
+	p.onBlock(f.size, time.Since(f.startTime))
+	cl.log.Tracef("height=%d consumeOffset=%d window=%d\n", f.height, fr.consumeOffset, p.window())
 	offset := f.height - fr.consumeOffset
 	fr.pendingResults[offset] = &blockResult{
-		id:    f.id,
+		id:    p.id,
 		blk:   blk,
 		votes: votes,
 		cl:    cl,
 		fr:    fr,
 	}
-	fr.nActivePeers--
-	p.f = nil
+	if fr._isSlow(p) {
+		cl.log.Debugf("evict slow peer:%s throughput=%.0f\n", common.HexPre(p.id.Bytes()), p.throughput)
+		fr._evict(p)
+	}
 
 	fr._reschedule()
 	if offset == 0 {
@@ -354,12 +613,37 @@ func (cl *client) notifyBlockResult() {
 	go cb.OnBlock(br)
 }
 
+// _cancelPeer cancels all requests for the peer.
+func (cl *client) _cancelPeer(p *peer) {
+	for _, f := range p.fetchers {
+		f.cancel()
+	}
+	p.fetchers = nil
+	var msg CancelAllBlockRequests
+	bs := codec.MustMarshalToBytes(&msg)
+	for {
+		err := cl.ph.Unicast(ProtoCancelAllBlockRequests, bs, p.id)
+		if err == nil || !isTemporary(err) {
+			return
+		}
+		time.Sleep(configSendInterval)
+	}
+}
+
 var errNoBlock = errors.New("errNoBlock")
 
 func isNoBlock(err error) bool {
 	return errors.Is(err, errNoBlock)
 }
 
+var errBadData = errors.New("errBadData")
+
+// isBadData returns whether the error is caused by invalid data from the
+// peer.
+func isBadData(err error) bool {
+	return errors.Is(err, errBadData)
+}
+
 type fstep byte
 
 //goland:noinspection GoUnusedConst
@@ -377,25 +661,32 @@ type fetcher struct {
 	requestID uint32
 	fr        *fetchRequest
 	cl        *client
+	p         *peer
 
 	step     fstep
 	timer    *time.Timer
 	left     int32
+	size     int
 	voteList []byte
 	dataList [][]byte
+
+	// startTime is the time when the server is expected to start serving
+	// the request. It's the time when the request is sent, or the time when
+	// it becomes the first one of the peer since the server serves requests
+	// in order.
+	startTime time.Time
 }
 
-func (fr *fetchRequest) newFetcher(id module.PeerID, height int64, requestID uint32) *fetcher {
+func (fr *fetchRequest) newFetcher(p *peer, height int64, requestID uint32) *fetcher {
 	f := &fetcher{
 		Mutex:     &fr.cl.Mutex,
-		id:        id,
+		id:        p.id,
 		height:    height,
 		requestID: requestID,
 		fr:        fr,
 		cl:        fr.cl,
+		p:         p,
 	}
-
-	f._doSend()
 	return f
 }
 
@@ -405,8 +696,8 @@ func (fr *fetchRequest) _cancel() bool {
 	}
 
 	for _, p := range fr.validPeers {
-		if p.f != nil {
-			p.f.cancel()
+		if len(p.fetchers) > 0 {
+			fr.cl._cancelPeer(p)
 		}
 	}
 
@@ -420,6 +711,37 @@ func (fr *fetchRequest) cancel() bool {
 	return fr._cancel()
 }
 
+func (f *fetcher) isHead() bool {
+	return len(f.p.fetchers) > 0 && f.p.fetchers[0] == f
+}
+
+// _onHead is called when the fetcher becomes the first one of the peer.
+// It starts the timer for the response if the request is sent.
+func (f *fetcher) _onHead() {
+	if f.step == fstepWaitResp || f.step == fstepWaitData {
+		f.startTime = time.Now()
+		f._startTimer()
+	}
+}
+
+func (f *fetcher) _startTimer() {
+	if f.timer != nil {
+		f.timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(configTimeout, func() {
+		f.Lock()
+		defer f.Unlock()
+
+		if f.timer != timer {
+			return
+		}
+		f.timer = nil
+		f.cl.onResult(f, errors.Errorf("Timed out"), nil, nil)
+	})
+	f.timer = timer
+}
+
 func (f *fetcher) _doSend() {
 	var msg BlockRequest
 	msg.RequestID = f.requestID
@@ -434,19 +756,10 @@ func (f *fetcher) _doSend() {
 	err := f.cl.ph.Unicast(ProtoBlockRequest, bs, f.id)
 	if err == nil {
 		f.step = fstepWaitResp
-		var timer *time.Timer
-		timer = time.AfterFunc(configTimeout, func() {
-			f.Lock()
-			defer f.Unlock()
-
-			if f.timer != timer {
-				return
-			}
-			f.timer = nil
-			f.cancel()
-			f.cl.onResult(f, errors.Errorf("Timed out"), nil, nil)
-		})
-		f.timer = timer
+		f.startTime = time.Now()
+		if f.isHead() {
+			f._startTimer()
+		}
 	} else if isTemporary(err) {
 		var timer *time.Timer
 		timer = time.AfterFunc(configSendInterval, func() {
@@ -460,7 +773,6 @@ func (f *fetcher) _doSend() {
 		})
 		f.timer = timer
 	} else {
-		f.cancel()
 		f.cl.onResult(f, err, nil, nil)
 	}
 }
@@ -470,85 +782,58 @@ func (f *fetcher) cancel() {
 		f.timer.Stop()
 		f.timer = nil
 	}
-	var msg CancelAllBlockRequests
-	bs := codec.MustMarshalToBytes(&msg)
 	f.step = fstepFin
-	for {
-		err := f.cl.ph.Unicast(ProtoCancelAllBlockRequests, bs, f.id)
-		if err == nil || !isTemporary(err) {
-			return
-		}
-		time.Sleep(configSendInterval)
+}
+
+func (f *fetcher) _finish() {
+	f.step = fstepFin
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
 	}
 }
 
-func (f *fetcher) onReceive(pi module.ProtocolInfo, b []byte) {
-	if f.step == fstepWaitResp {
-		if pi != ProtoBlockMetadata {
-			return
-		}
-		var msg BlockMetadata
-		_, err := codec.UnmarshalFromBytes(b, &msg)
-		if err != nil {
-			return
-		}
-		if msg.RequestID != f.requestID {
-			return
-		}
-		f.cl.log.Tracef("onReceive BlockMetadata rid=%d, len=%d\n", msg.RequestID, msg.BlockLength)
-		if msg.BlockLength < 0 {
-			f.step = fstepFin
-			if f.timer != nil {
-				f.timer.Stop()
-				f.timer = nil
-			}
-			f.cl.onResult(f, errNoBlock, nil, nil)
-		}
-		f.left = msg.BlockLength
-		f.voteList = msg.Proof
-		f.step = fstepWaitData
-	} else if f.step == fstepWaitData {
-		if pi != ProtoBlockData {
-			return
+func (f *fetcher) onBlockMetadata(msg *BlockMetadata) {
+	if f.step != fstepWaitResp {
+		return
+	}
+	f.cl.log.Tracef("onReceive BlockMetadata rid=%d, len=%d\n", msg.RequestID, msg.BlockLength)
+	if msg.BlockLength < 0 {
+		f._finish()
+		f.cl.onResult(f, errNoBlock, nil, nil)
+		return
+	}
+	f.left = msg.BlockLength
+	f.size = int(msg.BlockLength)
+	f.voteList = msg.Proof
+	f.step = fstepWaitData
+}
+
+func (f *fetcher) onBlockData(msg *BlockData) {
+	if f.step != fstepWaitData {
+		return
+	}
+	f.dataList = append(f.dataList, msg.Data)
+	f.left -= int32(len(msg.Data))
+	f.cl.log.Tracef("onReceive BlockData rid=%d, data len=%d left=%d\n", msg.RequestID, len(msg.Data), f.left)
+	if f.left == 0 {
+		f._finish()
+		bufs := make([]io.Reader, len(f.dataList))
+		for i, d := range f.dataList {
+			bufs[i] = bytes.NewReader(d)
 		}
-		var msg BlockData
-		_, err := codec.UnmarshalFromBytes(b, &msg)
+		r := io.MultiReader(bufs...)
+		blk, err := f.cl.bm.NewBlockDataFromReader(r)
 		if err != nil {
-			return
-		}
-		if msg.RequestID != f.requestID {
-			return
-		}
-		f.dataList = append(f.dataList, msg.Data)
-		f.left -= int32(len(msg.Data))
-		f.cl.log.Tracef("onReceive BlockData rid=%d, data len=%d left=%d\n", msg.RequestID, len(msg.Data), f.left)
-		if f.left == 0 {
-			f.step = fstepFin
-			if f.timer != nil {
-				f.timer.Stop()
-				f.timer = nil
-			}
-			bufs := make([]io.Reader, len(f.dataList))
-			for i, d := range f.dataList {
-				bufs[i] = bytes.NewReader(d)
-			}
-			r := io.MultiReader(bufs...)
-			blk, err := f.cl.bm.NewBlockDataFromReader(r)
-			if err != nil {
-				f.cl.onResult(f, err, nil, nil)
-			} else if blk.Height() != f.height {
-				f.cl.onResult(f, errors.Errorf("bad Height"), nil, nil)
-			} else {
-				f.cl.onResult(f, nil, blk, f.voteList)
-			}
-		} else if f.left < 0 {
-			f.step = fstepFin
-			if f.timer != nil {
-				f.timer.Stop()
-				f.timer = nil
-			}
-			f.cl.onResult(f, errors.Errorf("bad data"), nil, nil)
+			f.cl.onResult(f, errors.Wrapf(errBadData, "invalid block err=%v", err), nil, nil)
+		} else if blk.Height() != f.height {
+			f.cl.onResult(f, errors.Wrapf(errBadData, "bad Height"), nil, nil)
+		} else {
+			f.cl.onResult(f, nil, blk, f.voteList)
 		}
+	} else if f.left < 0 {
+		f._finish()
+		f.cl.onResult(f, errors.Wrapf(errBadData, "bad data"), nil, nil)
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -175,3 +176,195 @@ func TestClient_SuccessMulti(t *testing.T) {
 	ev2 = <-s.cb.ch
 	s.assertEndEvent(nil, ev2)
 }
+
+func TestClient_Pipelining(t *testing.T) {
+	s := newClientTestSetUp(t, 2)
+	_, err := s.m.FetchBlocks(1, 4, s.cb)
+	assert.Nil(t, err)
+
+	ev := <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 1}, s.nms[0].ID, ev)
+	s.respondBlockRequest(s.phs[1], 0x10000, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+
+	// window grows after receiving a block
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10001, 2}, s.nms[0].ID, ev)
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10002, 3}, s.nms[0].ID, ev)
+
+	ev2 := <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[1], ev2)
+	ev2.(tOnBlockEvent).br.Consume()
+
+	s.respondBlockRequest(s.phs[1], 0x10001, s.rawBlocks[2], s.votes[3], s.nms[0].ID)
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10003, 4}, s.nms[0].ID, ev)
+	s.respondBlockRequest(s.phs[1], 0x10002, s.rawBlocks[3], s.votes[4], s.nms[0].ID)
+	s.respondBlockRequest(s.phs[1], 0x10003, s.rawBlocks[4], s.votes[5], s.nms[0].ID)
+
+	for h := 2; h <= 4; h++ {
+		ev2 = <-s.cb.ch
+		s.assertBlockEvent(s.rawBlocks[h], ev2)
+		ev2.(tOnBlockEvent).br.Consume()
+	}
+	ev2 = <-s.cb.ch
+	s.assertEndEvent(nil, ev2)
+}
+
+func TestClient_NonHeadLatency(t *testing.T) {
+	s := newClientTestSetUp(t, 2)
+	_, err := s.m.FetchBlocks(1, 4, s.cb)
+	assert.Nil(t, err)
+
+	ev := <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 1}, s.nms[0].ID, ev)
+	s.respondBlockRequest(s.phs[1], 0x10000, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10001, 2}, s.nms[0].ID, ev)
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10002, 3}, s.nms[0].ID, ev)
+
+	// the second request in flight is answered first
+	s.respondBlockRequest(s.phs[1], 0x10002, s.rawBlocks[3], s.votes[4], s.nms[0].ID)
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10003, 4}, s.nms[0].ID, ev)
+
+	cl := s.m.(*manager).client
+	cl.Lock()
+	p := cl.fr.validPeers[0]
+	assert.Equal(t, 2, p.samples)
+	assert.Less(t, p.blockTime, configTimeout)
+	cl.Unlock()
+
+	s.respondBlockRequest(s.phs[1], 0x10001, s.rawBlocks[2], s.votes[3], s.nms[0].ID)
+	s.respondBlockRequest(s.phs[1], 0x10003, s.rawBlocks[4], s.votes[5], s.nms[0].ID)
+	for h := 1; h <= 4; h++ {
+		ev2 := <-s.cb.ch
+		s.assertBlockEvent(s.rawBlocks[h], ev2)
+		ev2.(tOnBlockEvent).br.Consume()
+	}
+	ev2 := <-s.cb.ch
+	s.assertEndEvent(nil, ev2)
+}
+
+func TestClient_FetchStatus(t *testing.T) {
+	s := newClientTestSetUp(t, 3)
+	assert.Nil(t, s.m.FetchStatus())
+	_, err := s.m.FetchBlocks(1, 2, s.cb)
+	assert.Nil(t, err)
+
+	ev := <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 1}, s.nms[0].ID, ev)
+	ev = <-s.reactors[2].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 2}, s.nms[0].ID, ev)
+
+	st := s.m.FetchStatus()
+	assert.Equal(t, &FetchStatus{
+		Begin:       1,
+		End:         2,
+		Next:        1,
+		Peers:       2,
+		ActivePeers: 2,
+		Requests:    2,
+	}, st)
+
+	s.respondBlockRequest(s.phs[2], 0x10000, s.rawBlocks[2], s.votes[3], s.nms[0].ID)
+	s.respondBlockRequest(s.phs[1], 0x10000, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+	ev2 := <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[1], ev2)
+
+	st = s.m.FetchStatus()
+	assert.EqualValues(t, 1, st.Next)
+	assert.Equal(t, 0, st.Requests)
+	assert.Equal(t, 2, st.Buffered)
+
+	ev2.(tOnBlockEvent).br.Consume()
+	ev2 = <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[2], ev2)
+	st = s.m.FetchStatus()
+	assert.EqualValues(t, 2, st.Next)
+	assert.True(t, st.Rate == 0)
+
+	ev2.(tOnBlockEvent).br.Consume()
+	ev2 = <-s.cb.ch
+	s.assertEndEvent(nil, ev2)
+	assert.Nil(t, s.m.FetchStatus())
+}
+
+func TestClient_BadDataBansPeer(t *testing.T) {
+	s := newClientTestSetUp(t, 3)
+	_, err := s.m.FetchBlocks(1, 1, s.cb)
+	assert.Nil(t, err)
+
+	ev := <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 1}, s.nms[0].ID, ev)
+	s.respondBlockRequest(s.phs[1], 0x10000, []byte{0xff, 0xff, 0xff}, nil, s.nms[0].ID)
+
+	ev = <-s.reactors[1].ch
+	s.assertEqualReceiveEvent(ProtoCancelAllBlockRequests, &CancelAllBlockRequests{}, s.nms[0].ID, ev)
+	ev = <-s.reactors[2].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x10000, 1}, s.nms[0].ID, ev)
+	s.respondBlockRequest(s.phs[2], 0x10000, s.rawBlocks[1], s.votes[2], s.nms[0].ID)
+
+	ev2 := <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[1], ev2)
+	ev2.(tOnBlockEvent).br.Consume()
+	ev2 = <-s.cb.ch
+	s.assertEndEvent(nil, ev2)
+
+	// banned peer is excluded from the next request
+	_, err = s.m.FetchBlocks(2, 2, s.cb)
+	assert.Nil(t, err)
+	ev = <-s.reactors[2].ch
+	s.assertEqualReceiveEvent(ProtoBlockRequest, &BlockRequestV1{0x20000, 2}, s.nms[0].ID, ev)
+	assert.Equal(t, 1, s.m.FetchStatus().Peers)
+	s.respondBlockRequest(s.phs[2], 0x20000, s.rawBlocks[2], s.votes[3], s.nms[0].ID)
+	ev2 = <-s.cb.ch
+	s.assertBlockEvent(s.rawBlocks[2], ev2)
+	ev2.(tOnBlockEvent).br.Consume()
+	ev2 = <-s.cb.ch
+	s.assertEndEvent(nil, ev2)
+	s.assertNoEvent(s.reactors[1].ch)
+}
+
+func TestPeer_Window(t *testing.T) {
+	p := newPeer(nil)
+	assert.Equal(t, 1, p.window())
+
+	// slow start
+	for i, w := range []int{2, 4, 8, configMaxWindow, configMaxWindow} {
+		p.onBlock(1000, time.Millisecond*10)
+		assert.Equal(t, w, p.window(), "samples=%d", i+1)
+	}
+
+	// bounded by throughput
+	p = newPeer(nil)
+	for i := 0; i < configMinSamples; i++ {
+		p.onBlock(1000, configWindowDuration/2)
+	}
+	assert.Equal(t, 3, p.window())
+}
+
+func TestFetchRequest_IsSlow(t *testing.T) {
+	newTPeer := func(d time.Duration) *peer {
+		p := newPeer(nil)
+		for i := 0; i < configMinSamples; i++ {
+			p.onBlock(1000, d)
+		}
+		return p
+	}
+	fast := newTPeer(time.Millisecond * 10)
+	normal := newTPeer(time.Millisecond * 20)
+	slow := newTPeer(time.Millisecond * 100)
+	fresh := newPeer(nil)
+
+	fr := &fetchRequest{validPeers: []*peer{fast, normal, slow, fresh}}
+	assert.False(t, fr._isSlow(fast))
+	assert.False(t, fr._isSlow(normal))
+	assert.True(t, fr._isSlow(slow))
+	assert.False(t, fr._isSlow(fresh))
+
+	// keeps minimum number of peers
+	fr = &fetchRequest{validPeers: []*peer{fast, slow}}
+	assert.False(t, fr._isSlow(slow))
+}
@@ -28,6 +28,24 @@ type FetchCallback interface {
 	OnEnd(err error)
 }
 
+// FetchStatus is the progress of the fetch request.
+type FetchStatus struct {
+	Begin int64
+	End   int64
+	// Next is the height of the next block to be delivered.
+	Next int64
+	// Peers is the number of peers available for the request.
+	Peers int
+	// ActivePeers is the number of peers having outstanding requests.
+	ActivePeers int
+	// Requests is the number of outstanding requests.
+	Requests int
+	// Buffered is the number of received blocks waiting for delivery.
+	Buffered int
+	// Rate is the number of delivered blocks per second.
+	Rate float64
+}
+
 type Manager interface {
 	StartServer()
 	StopServer()
@@ -36,6 +54,9 @@ type Manager interface {
 		end int64,
 		cb FetchCallback,
 	) (canceler func() bool, err error)
+	// FetchStatus returns the progress of the current fetch request.
+	// It returns nil if there is no fetch request.
+	FetchStatus() *FetchStatus
 	Term()
 }
 
@@ -90,6 +111,10 @@ func (m *manager) FetchBlocks(
 	}, nil
 }
 
+func (m *manager) FetchStatus() *FetchStatus {
+	return m.client.fetchStatus()
+}
+
 func (m *manager) Term() {
 	if m.nm != nil {
 		err := m.nm.UnregisterReactor(m)
@@ -156,6 +156,10 @@ func NewProposalMessage() *ProposalMessage {
 	return msg
 }
 
+func (msg *ProposalMessage) dsType() string {
+	return module.DSTProposal
+}
+
 func (msg *ProposalMessage) Cost() int {
 	return int(unsafe.Sizeof(ProposalMessage{})) + 256 + 40
 }
@@ -271,6 +275,10 @@ func (v *blockVoteByteser) bytes() []byte {
 	return msgCodec.MustMarshalToBytes(&bv)
 }
 
+func (v *blockVoteByteser) dsType() string {
+	return module.DSTVote
+}
+
 type VoteMessage struct {
 	signedBase
 	voteBase
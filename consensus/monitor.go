@@ -0,0 +1,135 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func maskString(vs *voteSet) string {
+	var sb strings.Builder
+	for _, msg := range vs.msgs {
+		if msg != nil {
+			sb.WriteByte('x')
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+// blockIDForOverTwoThirds returns the block ID having over two thirds of
+// the votes. ok is false if there is no such decision.
+func blockIDForOverTwoThirds(vs *voteSet) (id []byte, ok bool) {
+	rdd, _, ok := vs.getOverTwoThirdsRoundDecisionDigest()
+	if !ok || rdd == nil {
+		return nil, ok
+	}
+	for _, msg := range vs.msgs {
+		if msg != nil && bytes.Equal(msg.RoundDecisionDigest(), rdd) {
+			return msg.BlockID, true
+		}
+	}
+	return nil, true
+}
+
+func (cs *consensus) proposerAddress() module.Address {
+	if cs.validators == nil || cs.validators.Len() == 0 {
+		return nil
+	}
+	v, _ := cs.validators.Get(cs.getProposerIndex(cs.height, cs.round))
+	if v == nil {
+		return nil
+	}
+	return v.Address()
+}
+
+func (cs *consensus) notifyStep() {
+	cs.metric.OnStep(int(cs.step), cs.lockedRound, cs.isProposer())
+	cs.recordVoteMetric()
+	cs.stepWatchers.Notify(&module.ConsensusStepEvent{
+		Height:    cs.height,
+		Round:     cs.round,
+		Step:      cs.step.Name(),
+		Proposer:  cs.proposerAddress(),
+		Timestamp: time.Now(),
+	})
+}
+
+func (cs *consensus) recordVoteMetric() {
+	prevotes, precommits := cs.hvs.countsFor(cs.round)
+	cs.metric.OnVotes(prevotes, precommits)
+}
+
+func (cs *consensus) GetSyncStatus() *module.ConsensusSyncStatus {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if !cs.started || cs.syncer == nil {
+		return nil
+	}
+	return cs.syncer.GetSyncStatus()
+}
+
+func (cs *consensus) GetConsensusRoundState() (*module.ConsensusRoundState, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+
+	if !cs.started {
+		return nil, errors.InvalidStateError.New("ConsensusNotStarted")
+	}
+	rs := &module.ConsensusRoundState{
+		Height:      cs.height,
+		Round:       cs.round,
+		Step:        cs.step.Name(),
+		Proposer:    cs.proposerAddress(),
+		LockedRound: cs.lockedRound,
+		ValidRound:  -1,
+	}
+	if cs.lockedBlockParts.HasBlockData() {
+		rs.LockedBlockID = cs.lockedBlockParts.block.ID()
+	}
+	for _, round := range cs.hvs.rounds() {
+		rvs := cs.hvs._votes[round]
+		rv := &module.ConsensusRoundVotes{Round: round}
+		if vs := rvs[VoteTypePrevote]; vs != nil {
+			rv.Prevotes = maskString(vs)
+			if id, ok := blockIDForOverTwoThirds(vs); ok && id != nil && round > rs.ValidRound {
+				rs.ValidRound = round
+				rs.ValidBlockID = id
+			}
+		}
+		if vs := rvs[VoteTypePrecommit]; vs != nil {
+			rv.Precommits = maskString(vs)
+		}
+		rs.Votes = append(rs.Votes, rv)
+	}
+	return rs, nil
+}
+
+func (cs *consensus) WatchSteps(ch chan<- *module.ConsensusStepEvent) (func(), error) {
+	cancel, err := cs.stepWatchers.Watch(ch)
+	if err != nil {
+		return nil, errors.InvalidStateError.New("ConsensusTerminated")
+	}
+	return cancel, nil
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep_Name(t *testing.T) {
+	assert.Equal(t, "newHeight", stepNewHeight.Name())
+	assert.Equal(t, "prevoteWait", stepPrevoteWait.Name())
+	assert.Equal(t, "commit", stepCommit.Name())
+}
+
+func TestHeightVoteSet_Monitor(t *testing.T) {
+	var hvs heightVoteSet
+	hvs.reset(4)
+
+	vs := hvs.votesFor(1, VoteTypePrevote)
+	vs.add(0, &VoteMessage{voteBase: voteBase{blockVoteBase: blockVoteBase{
+		_HR: _HR{Height: 1, Round: 1}, Type: VoteTypePrevote,
+	}}})
+	vs.add(2, &VoteMessage{voteBase: voteBase{blockVoteBase: blockVoteBase{
+		_HR: _HR{Height: 1, Round: 1}, Type: VoteTypePrevote,
+	}}})
+	hvs.votesFor(0, VoteTypePrecommit)
+
+	assert.Equal(t, []int32{0, 1}, hvs.rounds())
+	assert.Equal(t, "x_x_", maskString(vs))
+	prevotes, precommits := hvs.countsFor(1)
+	assert.Equal(t, 2, prevotes)
+	assert.Equal(t, 0, precommits)
+}
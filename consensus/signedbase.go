@@ -55,10 +55,45 @@ func (s *signedBase) verify() error {
 	return nil
 }
 
+// dsTyper is implemented by messages which are checked for double signing.
+type dsTyper interface {
+	dsType() string
+}
+
+// chainWallet is the wallet of the consensus of a chain. It passes the
+// network ID of the chain to module.ConsensusSigner, so that the signer
+// can check messages of each chain separately.
+type chainWallet struct {
+	module.Wallet
+	nid int
+}
+
+// NewChainWallet returns the wallet signing consensus messages of the chain
+// of the network ID with w.
+func NewChainWallet(w module.Wallet, nid int) module.Wallet {
+	return &chainWallet{Wallet: w, nid: nid}
+}
+
+func (w *chainWallet) signConsensusMessage(t string, msg []byte) ([]byte, error) {
+	if cs, ok := w.Wallet.(module.ConsensusSigner); ok {
+		return cs.SignConsensusMessage(w.nid, t, msg)
+	}
+	return w.Wallet.Sign(crypto.SHA3Sum256(msg))
+}
+
+func (s *signedBase) signBytes(wallet module.Wallet) ([]byte, error) {
+	if cw, ok := wallet.(*chainWallet); ok {
+		if dt, ok := s._byteser.(dsTyper); ok {
+			return cw.signConsensusMessage(dt.dsType(), s._byteser.bytes())
+		}
+	}
+	return wallet.Sign(s.hash())
+}
+
 func (s *signedBase) Sign(wallet module.Wallet) error {
 	s._hash = nil
 	s._publicKey = nil
-	sigBS, err := wallet.Sign(s.hash())
+	sigBS, err := s.signBytes(wallet)
 	if err != nil {
 		return errors.Errorf("sendVote : %v", err)
 	}
@@ -0,0 +1,121 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+// SignStep is the step of a consensus message in a round. Messages of a
+// validator are signed in the order of height, round and step.
+type SignStep int8
+
+const (
+	SignStepPropose SignStep = iota
+	SignStepPrevote
+	SignStepPrecommit
+)
+
+func (s SignStep) String() string {
+	switch s {
+	case SignStepPropose:
+		return "Propose"
+	case SignStepPrevote:
+		return "PreVote"
+	case SignStepPrecommit:
+		return "PreCommit"
+	default:
+		return "Unknown"
+	}
+}
+
+// SignInfo is the position of a consensus message to be signed.
+type SignInfo struct {
+	Height int64
+	Round  int32
+	Step   SignStep
+}
+
+// Compare returns negative, zero or positive if the message is signed
+// before, at the same position or after the other.
+func (si *SignInfo) Compare(other *SignInfo) int {
+	if si.Height != other.Height {
+		return cmpInt64(si.Height, other.Height)
+	}
+	if si.Round != other.Round {
+		return cmpInt64(int64(si.Round), int64(other.Round))
+	}
+	return cmpInt64(int64(si.Step), int64(other.Step))
+}
+
+func (si *SignInfo) String() string {
+	return fmt.Sprintf("{H:%d R:%d S:%s}", si.Height, si.Round, si.Step)
+}
+
+func cmpInt64(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+func unmarshalExact(bs []byte, v interface{}) error {
+	remain, err := msgCodec.UnmarshalFromBytes(bs, v)
+	if err != nil {
+		return err
+	}
+	if len(remain) > 0 {
+		return errors.Errorf("TrailingBytes(len=%d)", len(remain))
+	}
+	return nil
+}
+
+// SignInfoOf returns the position of the consensus message bytes passed to
+// module.ConsensusSigner. t is module.DSTProposal or module.DSTVote.
+func SignInfoOf(t string, msg []byte) (*SignInfo, error) {
+	switch t {
+	case module.DSTProposal:
+		var p proposal
+		if err := unmarshalExact(msg, &p); err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidProposal")
+		}
+		return &SignInfo{Height: p.Height, Round: p.Round, Step: SignStepPropose}, nil
+	case module.DSTVote:
+		var bv struct {
+			blockVoteBase
+			Timestamp int64
+		}
+		if err := unmarshalExact(msg, &bv); err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidVote")
+		}
+		switch bv.Type {
+		case VoteTypePrevote:
+			return &SignInfo{Height: bv.Height, Round: bv.Round, Step: SignStepPrevote}, nil
+		case VoteTypePrecommit:
+			return &SignInfo{Height: bv.Height, Round: bv.Round, Step: SignStepPrecommit}, nil
+		default:
+			return nil, errors.IllegalArgumentError.Errorf("InvalidVoteType(type=%d)", bv.Type)
+		}
+	default:
+		return nil, errors.IllegalArgumentError.Errorf("InvalidMessageType(type=%s)", t)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/module"
+)
+
+type testConsensusSigner struct {
+	module.Wallet
+	nids  []int
+	infos []*SignInfo
+}
+
+func (w *testConsensusSigner) SignConsensusMessage(nid int, t string, msg []byte) ([]byte, error) {
+	si, err := SignInfoOf(t, msg)
+	if err != nil {
+		return nil, err
+	}
+	w.nids = append(w.nids, nid)
+	w.infos = append(w.infos, si)
+	return w.Sign(crypto.SHA3Sum256(msg))
+}
+
+func TestSignInfoOf_ConsensusSigner(t *testing.T) {
+	cs := &testConsensusSigner{Wallet: wallet.New()}
+	w := &chainWallet{Wallet: cs, nid: 3}
+
+	pm := NewProposalMessage()
+	pm.Height = 10
+	pm.Round = 2
+	pm.POLRound = -1
+	pm.NID = 3
+	assert.NoError(t, pm.Sign(w))
+	assert.Equal(t, w.Address(), pm.address())
+
+	vm := NewVoteMessage(w, VoteTypePrevote, 10, 2, make([]byte, 32), nil, 100, nil, nil, 0)
+	assert.NoError(t, vm.Verify(theNilVerifyCtx))
+	assert.Equal(t, w.Address(), vm.address())
+	NewPrecommitMessage(w, 10, 2, make([]byte, 32), nil, 100)
+
+	assert.Equal(t, []*SignInfo{
+		{Height: 10, Round: 2, Step: SignStepPropose},
+		{Height: 10, Round: 2, Step: SignStepPrevote},
+		{Height: 10, Round: 2, Step: SignStepPrecommit},
+	}, cs.infos)
+	assert.Equal(t, []int{3, 3, 3}, cs.nids)
+	assert.True(t, cs.infos[0].Compare(cs.infos[1]) < 0)
+	assert.True(t, cs.infos[2].Compare(cs.infos[1]) > 0)
+	assert.Equal(t, 0, cs.infos[2].Compare(&SignInfo{Height: 10, Round: 2, Step: SignStepPrecommit}))
+	assert.True(t, cs.infos[2].Compare(&SignInfo{Height: 10, Round: 3}) < 0)
+	assert.True(t, cs.infos[2].Compare(&SignInfo{Height: 9, Round: 5}) > 0)
+}
+
+func TestSignInfoOf_Invalid(t *testing.T) {
+	pm := NewProposalMessage()
+	pm.Height = 10
+	bs := pm.bytes()
+
+	_, err := SignInfoOf(module.DSTVote, bs)
+	assert.Error(t, err)
+	_, err = SignInfoOf("unknown", bs)
+	assert.Error(t, err)
+	_, err = SignInfoOf(module.DSTProposal, append(bs, 0x80))
+	assert.Error(t, err)
+	_, err = SignInfoOf(module.DSTProposal, []byte{0x01})
+	assert.Error(t, err)
+}
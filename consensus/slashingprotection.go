@@ -0,0 +1,414 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	SlashingProtectionVersion = 1
+
+	// configSlashingProtectionHeights is the number of heights of which
+	// messages are kept for each signer. Signing messages below them is
+	// refused.
+	configSlashingProtectionHeights = 16
+)
+
+const (
+	spTypeProposal  = "proposal"
+	spTypePrevote   = "prevote"
+	spTypePrecommit = "precommit"
+)
+
+func spTypeOf(s SignStep) string {
+	switch s {
+	case SignStepPropose:
+		return spTypeProposal
+	case SignStepPrevote:
+		return spTypePrevote
+	case SignStepPrecommit:
+		return spTypePrecommit
+	default:
+		return ""
+	}
+}
+
+func spStepOf(t string) (SignStep, error) {
+	switch t {
+	case spTypeProposal:
+		return SignStepPropose, nil
+	case spTypePrevote:
+		return SignStepPrevote, nil
+	case spTypePrecommit:
+		return SignStepPrecommit, nil
+	default:
+		return 0, errors.IllegalArgumentError.Errorf("InvalidMessageType(type=%s)", t)
+	}
+}
+
+// SlashingProtectionMessage is a consensus message signed by a signer.
+type SlashingProtectionMessage struct {
+	Type   string          `json:"type"`
+	Height common.HexInt64 `json:"height"`
+	Round  common.HexInt32 `json:"round"`
+	Hash   common.HexBytes `json:"hash"`
+}
+
+// SlashingProtectionSigner is the consensus messages signed by a signer
+// for a network. Signing messages below MinHeight is refused.
+type SlashingProtectionSigner struct {
+	Address   common.Address              `json:"address"`
+	NID       common.HexInt32             `json:"nid"`
+	MinHeight common.HexInt64             `json:"minHeight"`
+	Messages  []SlashingProtectionMessage `json:"messages"`
+}
+
+// SlashingProtectionData is the interchange format of SlashingProtection.
+type SlashingProtectionData struct {
+	Version int                        `json:"version"`
+	Signers []SlashingProtectionSigner `json:"signers"`
+}
+
+type spKey struct {
+	address string
+	nid     int32
+}
+
+type spSigner struct {
+	address   common.Address
+	nid       int32
+	minHeight int64
+	messages  map[SignInfo][]byte
+}
+
+func (s *spSigner) prune() {
+	for info := range s.messages {
+		if info.Height < s.minHeight {
+			delete(s.messages, info)
+		}
+	}
+}
+
+// add adds the message signed by the signer. It returns false if it
+// conflicts with the messages signed before. Like dsVote.IsConflictWith
+// and dsProposal.IsConflictWith, two messages of the same type at the same
+// height and round conflict with each other if their hashes are different.
+func (s *spSigner) add(info SignInfo, hash []byte) (bool, error) {
+	if info.Height < s.minHeight {
+		return false, errors.InvalidStateError.Errorf(
+			"SlashingProtection(msg=%s,minHeight=%d)", &info, s.minHeight)
+	}
+	if old, ok := s.messages[info]; ok {
+		if bytes.Equal(old, hash) {
+			return false, nil
+		}
+		return false, errors.InvalidStateError.Errorf(
+			"SlashingProtection(msg=%s,hash=%#x,signed=%#x)", &info, hash, old)
+	}
+	s.messages[info] = hash
+	if h := info.Height - configSlashingProtectionHeights + 1; h > s.minHeight {
+		s.minHeight = h
+		s.prune()
+	}
+	return true, nil
+}
+
+// SlashingProtection keeps consensus messages signed by validators, and
+// refuses to sign a message conflicting with them. It's kept in a file
+// separated from the chain database, so that it's not affected by
+// restoring chain data or resetting WAL.
+type SlashingProtection struct {
+	lock     sync.Mutex
+	path     string
+	lockFile *os.File
+	signers  map[spKey]*spSigner
+}
+
+// OpenSlashingProtection opens the slashing protection store in the file.
+// The file is locked until it's closed. If path is empty, the store is
+// kept only in memory.
+func OpenSlashingProtection(path string) (*SlashingProtection, error) {
+	sp := &SlashingProtection{
+		path:    path,
+		signers: make(map[spKey]*spSigner),
+	}
+	if path == "" {
+		return sp, nil
+	}
+	lf, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open lock file for file=%s", path)
+	}
+	if err := syscall.Flock(int(lf.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lf.Close()
+		return nil, errors.InvalidStateError.Wrapf(err, "SlashingProtectionInUse(file=%s)", path)
+	}
+	sp.lockFile = lf
+	bs, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sp, nil
+	} else if err != nil {
+		sp.Close()
+		return nil, errors.Wrapf(err, "fail to read file=%s", path)
+	}
+	var data SlashingProtectionData
+	if err := json.Unmarshal(bs, &data); err != nil {
+		sp.Close()
+		return nil, errors.Wrapf(err, "invalid slashing protection file=%s", path)
+	}
+	if _, err := sp.merge(&data); err != nil {
+		sp.Close()
+		return nil, err
+	}
+	return sp, nil
+}
+
+// Close releases the lock of the file.
+func (sp *SlashingProtection) Close() error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if sp.lockFile != nil {
+		err := sp.lockFile.Close()
+		sp.lockFile = nil
+		return err
+	}
+	return nil
+}
+
+func (sp *SlashingProtection) signerFor(addr module.Address, nid int32) *spSigner {
+	key := spKey{string(addr.Bytes()), nid}
+	s, ok := sp.signers[key]
+	if !ok {
+		s = &spSigner{
+			address:  *common.AddressToPtr(addr),
+			nid:      nid,
+			messages: make(map[SignInfo][]byte),
+		}
+		sp.signers[key] = s
+	}
+	return s
+}
+
+func (sp *SlashingProtection) export() *SlashingProtectionData {
+	data := &SlashingProtectionData{
+		Version: SlashingProtectionVersion,
+		Signers: make([]SlashingProtectionSigner, 0, len(sp.signers)),
+	}
+	for _, s := range sp.signers {
+		infos := make([]SignInfo, 0, len(s.messages))
+		for info := range s.messages {
+			infos = append(infos, info)
+		}
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].Compare(&infos[j]) < 0
+		})
+		msgs := make([]SlashingProtectionMessage, 0, len(infos))
+		for _, info := range infos {
+			msgs = append(msgs, SlashingProtectionMessage{
+				Type:   spTypeOf(info.Step),
+				Height: common.HexInt64{Value: info.Height},
+				Round:  common.HexInt32{Value: info.Round},
+				Hash:   s.messages[info],
+			})
+		}
+		data.Signers = append(data.Signers, SlashingProtectionSigner{
+			Address:   s.address,
+			NID:       common.HexInt32{Value: s.nid},
+			MinHeight: common.HexInt64{Value: s.minHeight},
+			Messages:  msgs,
+		})
+	}
+	sort.Slice(data.Signers, func(i, j int) bool {
+		si, sj := &data.Signers[i], &data.Signers[j]
+		if c := bytes.Compare(si.Address.Bytes(), sj.Address.Bytes()); c != 0 {
+			return c < 0
+		}
+		return si.NID.Value < sj.NID.Value
+	})
+	return data
+}
+
+// merge merges the data. Messages conflicting with the ones already kept
+// are ignored, and the higher minimum height is used.
+func (sp *SlashingProtection) merge(data *SlashingProtectionData) (bool, error) {
+	if data.Version != SlashingProtectionVersion {
+		return false, errors.IllegalArgumentError.Errorf(
+			"UnsupportedVersion(version=%d)", data.Version)
+	}
+	for i := range data.Signers {
+		ds := &data.Signers[i]
+		for _, m := range ds.Messages {
+			if _, err := spStepOf(m.Type); err != nil {
+				return false, err
+			}
+			if len(m.Hash) != crypto.HashLen {
+				return false, errors.IllegalArgumentError.Errorf(
+					"InvalidHash(hash=%#x)", []byte(m.Hash))
+			}
+		}
+	}
+	updated := false
+	for i := range data.Signers {
+		ds := &data.Signers[i]
+		s := sp.signerFor(&ds.Address, ds.NID.Value)
+		if ds.MinHeight.Value > s.minHeight {
+			s.minHeight = ds.MinHeight.Value
+			s.prune()
+			updated = true
+		}
+		for _, m := range ds.Messages {
+			step, _ := spStepOf(m.Type)
+			info := SignInfo{Height: m.Height.Value, Round: m.Round.Value, Step: step}
+			if added, _ := s.add(info, m.Hash); added {
+				updated = true
+			}
+		}
+	}
+	return updated, nil
+}
+
+func (sp *SlashingProtection) store() error {
+	if sp.path == "" {
+		return nil
+	}
+	if sp.lockFile == nil {
+		return errors.InvalidStateError.New("Closed")
+	}
+	bs, err := json.MarshalIndent(sp.export(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := sp.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, sp.path)
+}
+
+// Export returns the data for migrating to other nodes.
+func (sp *SlashingProtection) Export() *SlashingProtectionData {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	return sp.export()
+}
+
+// Import merges the data exported from other nodes, and stores it.
+func (sp *SlashingProtection) Import(data *SlashingProtectionData) error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if updated, err := sp.merge(data); err != nil {
+		return err
+	} else if updated {
+		return sp.store()
+	}
+	return nil
+}
+
+// Reset drops the messages and the minimum height of the signer for the
+// network, so that it can sign messages of any height. It's used when the
+// chain of the network is reset or started again from the genesis.
+func (sp *SlashingProtection) Reset(addr module.Address, nid int) error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	key := spKey{string(addr.Bytes()), int32(nid)}
+	if _, ok := sp.signers[key]; !ok {
+		return nil
+	}
+	delete(sp.signers, key)
+	if err := sp.store(); err != nil {
+		return errors.Wrap(err, "fail to store slashing protection")
+	}
+	return nil
+}
+
+// Record checks whether the consensus message with the hash conflicts with
+// the messages signed by the signer for the network. If it doesn't, the
+// message is stored, so that it's checked before signing other messages.
+func (sp *SlashingProtection) Record(
+	addr module.Address, nid int, info *SignInfo, hash []byte,
+) error {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	s := sp.signerFor(addr, int32(nid))
+	if added, err := s.add(*info, hash); err != nil {
+		return err
+	} else if added {
+		if err := sp.store(); err != nil {
+			return errors.Wrap(err, "fail to store slashing protection")
+		}
+	}
+	return nil
+}
+
+// protectedWallet signs consensus messages after recording them to
+// SlashingProtection.
+type protectedWallet struct {
+	module.Wallet
+	sp  *SlashingProtection
+	log log.Logger
+}
+
+func (w *protectedWallet) SignConsensusMessage(nid int, t string, msg []byte) ([]byte, error) {
+	info, err := SignInfoOf(t, msg)
+	if err != nil {
+		return nil, err
+	}
+	hash := crypto.SHA3Sum256(msg)
+	if err := w.sp.Record(w.Address(), nid, info, hash); err != nil {
+		w.log.Warnf("refuse to sign msg=%s err=%+v", info, err)
+		return nil, err
+	}
+	if cs, ok := w.Wallet.(module.ConsensusSigner); ok {
+		return cs.SignConsensusMessage(nid, t, msg)
+	}
+	return w.Wallet.Sign(hash)
+}
+
+// SlashingProtectedChain is implemented by chains protecting consensus
+// messages with SlashingProtection.
+type SlashingProtectedChain interface {
+	SlashingProtection() *SlashingProtection
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/json"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+)
+
+func testHash(b byte) []byte {
+	return crypto.SHA3Sum256([]byte{b})
+}
+
+func TestSlashingProtection_Record(t *testing.T) {
+	sp, err := OpenSlashingProtection("")
+	assert.NoError(t, err)
+	addr := wallet.New().Address()
+
+	info := &SignInfo{Height: 10, Round: 0, Step: SignStepPrevote}
+	assert.NoError(t, sp.Record(addr, 1, info, testHash(1)))
+	assert.NoError(t, sp.Record(addr, 1, info, testHash(1)))
+	assert.Error(t, sp.Record(addr, 1, info, testHash(2)))
+
+	// other step, round, network and signer don't conflict
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: 10, Round: 0, Step: SignStepPrecommit}, testHash(2)))
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: 10, Round: 1, Step: SignStepPrevote}, testHash(2)))
+	assert.NoError(t, sp.Record(addr, 2, info, testHash(2)))
+	assert.NoError(t, sp.Record(wallet.New().Address(), 1, info, testHash(2)))
+
+	// old heights are refused after they are pruned
+	h := int64(10 + configSlashingProtectionHeights)
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: h, Round: 0, Step: SignStepPropose}, testHash(3)))
+	assert.Error(t, sp.Record(addr, 1, &SignInfo{Height: 10, Round: 5, Step: SignStepPrevote}, testHash(3)))
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: 11, Round: 5, Step: SignStepPrevote}, testHash(3)))
+	assert.NoError(t, sp.Record(addr, 2, &SignInfo{Height: 10, Round: 5, Step: SignStepPrevote}, testHash(3)))
+}
+
+func TestSlashingProtection_File(t *testing.T) {
+	file := path.Join(t.TempDir(), "sp.json")
+	sp, err := OpenSlashingProtection(file)
+	assert.NoError(t, err)
+	addr := wallet.New().Address()
+	info := &SignInfo{Height: 3, Round: 1, Step: SignStepPropose}
+	assert.NoError(t, sp.Record(addr, 1, info, testHash(1)))
+
+	_, err = OpenSlashingProtection(file)
+	assert.Error(t, err)
+
+	assert.NoError(t, sp.Close())
+	assert.Error(t, sp.Record(addr, 1, &SignInfo{Height: 4}, testHash(1)))
+
+	sp, err = OpenSlashingProtection(file)
+	assert.NoError(t, err)
+	defer sp.Close()
+	assert.NoError(t, sp.Record(addr, 1, info, testHash(1)))
+	assert.Error(t, sp.Record(addr, 1, info, testHash(2)))
+}
+
+func TestSlashingProtection_Reset(t *testing.T) {
+	file := path.Join(t.TempDir(), "sp.json")
+	sp, err := OpenSlashingProtection(file)
+	assert.NoError(t, err)
+	addr := wallet.New().Address()
+
+	h := int64(100 + configSlashingProtectionHeights)
+	info := &SignInfo{Height: h, Round: 0, Step: SignStepPrevote}
+	assert.NoError(t, sp.Record(addr, 1, info, testHash(1)))
+	assert.NoError(t, sp.Record(addr, 2, info, testHash(1)))
+
+	// the chain is reset and restarts from the lower height
+	info2 := &SignInfo{Height: 2, Round: 0, Step: SignStepPrevote}
+	assert.Error(t, sp.Record(addr, 1, info2, testHash(2)))
+	assert.NoError(t, sp.Reset(addr, 1))
+	assert.NoError(t, sp.Record(addr, 1, info2, testHash(2)))
+
+	// signer of other network is kept
+	assert.Error(t, sp.Record(addr, 2, info2, testHash(2)))
+	assert.Error(t, sp.Record(addr, 2, info, testHash(2)))
+
+	// resetting unknown signer does nothing
+	assert.NoError(t, sp.Reset(wallet.New().Address(), 1))
+
+	// reset is stored
+	assert.NoError(t, sp.Close())
+	sp, err = OpenSlashingProtection(file)
+	assert.NoError(t, err)
+	defer sp.Close()
+	assert.NoError(t, sp.Record(addr, 1, info2, testHash(2)))
+	assert.Error(t, sp.Record(addr, 1, info2, testHash(3)))
+}
+
+func TestSlashingProtection_ExportImport(t *testing.T) {
+	sp, _ := OpenSlashingProtection("")
+	addr := wallet.New().Address()
+	h := int64(5 + configSlashingProtectionHeights)
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: 5, Round: 0, Step: SignStepPrevote}, testHash(1)))
+	assert.NoError(t, sp.Record(addr, 1, &SignInfo{Height: h, Round: 2, Step: SignStepPrecommit}, testHash(2)))
+
+	bs, err := json.Marshal(sp.Export())
+	assert.NoError(t, err)
+	var data SlashingProtectionData
+	assert.NoError(t, json.Unmarshal(bs, &data))
+	assert.Equal(t, SlashingProtectionVersion, data.Version)
+	assert.Len(t, data.Signers, 1)
+	assert.EqualValues(t, 6, data.Signers[0].MinHeight.Value)
+	assert.Len(t, data.Signers[0].Messages, 1)
+	assert.Equal(t, "precommit", data.Signers[0].Messages[0].Type)
+
+	sp2, _ := OpenSlashingProtection("")
+	info := &SignInfo{Height: 20, Round: 0, Step: SignStepPrevote}
+	assert.NoError(t, sp2.Record(addr, 1, info, testHash(3)))
+	assert.NoError(t, sp2.Import(&data))
+	assert.Error(t, sp2.Record(addr, 1, &SignInfo{Height: h, Round: 2, Step: SignStepPrecommit}, testHash(3)))
+	assert.Error(t, sp2.Record(addr, 1, &SignInfo{Height: 5, Round: 0, Step: SignStepPrevote}, testHash(1)))
+	assert.Error(t, sp2.Record(addr, 1, info, testHash(4)))
+	assert.Len(t, sp2.Export().Signers[0].Messages, 2)
+
+	data.Version = 2
+	assert.Error(t, sp2.Import(&data))
+	data.Version = SlashingProtectionVersion
+	data.Signers[0].Messages[0].Type = "unknown"
+	assert.Error(t, sp2.Import(&data))
+}
+
+func TestSlashingProtection_Wallet(t *testing.T) {
+	sp, _ := OpenSlashingProtection("")
+	cs := &testConsensusSigner{Wallet: wallet.New()}
+	w := &chainWallet{
+		Wallet: &protectedWallet{Wallet: cs, sp: sp, log: log.GlobalLogger()},
+		nid:    1,
+	}
+
+	pm := NewProposalMessage()
+	pm.Height = 10
+	pm.Round = 2
+	pm.POLRound = -1
+	assert.NoError(t, pm.Sign(w))
+	assert.Equal(t, w.Address(), pm.address())
+	assert.NoError(t, pm.Sign(w))
+	assert.Len(t, cs.infos, 2)
+
+	pm.POLRound = 1
+	assert.Error(t, pm.Sign(w))
+	assert.Len(t, cs.infos, 2)
+
+	vm := NewPrecommitMessage(w, 10, 2, make([]byte, 32), nil, 100)
+	assert.NoError(t, vm.Verify(theNilVerifyCtx))
+	vm = NewPrecommitMessage(w, 10, 2, make([]byte, 32), nil, 101)
+	assert.Error(t, vm.Verify(theNilVerifyCtx))
+}
@@ -1,6 +1,9 @@
 package consensus
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type step int
 
@@ -40,3 +43,9 @@ func (step step) String() string {
 		return fmt.Sprintf("step %d", step)
 	}
 }
+
+// Name returns the name of the step for monitoring interfaces.
+func (step step) Name() string {
+	name := strings.TrimPrefix(step.String(), "step")
+	return strings.ToLower(name[:1]) + name[1:]
+}
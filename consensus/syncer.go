@@ -43,6 +43,7 @@ type Syncer interface {
 	Start() error
 	Stop()
 	OnEngineStepChange()
+	GetSyncStatus() *module.ConsensusSyncStatus
 }
 
 var SyncerProtocols = []module.ProtocolInfo{
@@ -263,6 +264,9 @@ type syncer struct {
 	lastSendTime  time.Time
 	running       bool
 	fetchCanceler func() bool
+
+	// targetHeight is the highest height of peers' round states.
+	targetHeight int64
 }
 
 func newSyncer(e Engine, logger log.Logger, nm module.NetworkManager, bm module.BlockManager, mutex *common.Mutex, addr module.Address) (Syncer, error) {
@@ -335,6 +339,9 @@ func (s *syncer) OnReceive(sp module.ProtocolInfo, bs []byte,
 			}
 		}
 	case *RoundStateMessage:
+		if m.Height > s.targetHeight {
+			s.targetHeight = m.Height
+		}
 		for _, p := range s.peers {
 			if p.id.Equal(id) {
 				p.setRoundState(&m.peerRoundState)
@@ -484,6 +491,26 @@ func (s *syncer) Stop() {
 	s.fsm.Term()
 }
 
+func (s *syncer) GetSyncStatus() *module.ConsensusSyncStatus {
+	if s.fetchCanceler == nil {
+		return nil
+	}
+	fs := s.fsm.FetchStatus()
+	if fs == nil {
+		return nil
+	}
+	target := s.targetHeight
+	if target < fs.Next {
+		target = fs.Next
+	}
+	return &module.ConsensusSyncStatus{
+		Height:       fs.Next,
+		TargetHeight: target,
+		Peers:        fs.Peers,
+		Rate:         fs.Rate,
+	}
+}
+
 func (s *syncer) OnBlock(br fastsync.BlockResult) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
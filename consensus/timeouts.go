@@ -0,0 +1,70 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/icon-project/goloop/module"
+)
+
+// timeoutsFor returns timeouts of round steps applying the chain
+// configuration over the defaults.
+func (cs *consensus) timeoutsFor(to *module.ConsensusTimeouts) module.ConsensusTimeouts {
+	res := module.ConsensusTimeouts{
+		Propose:   cs.timeoutPropose,
+		Prevote:   timeoutPrevote,
+		Precommit: timeoutPrecommit,
+		NewRound:  timeoutNewRound,
+	}
+	if to == nil {
+		return res
+	}
+	if to.Propose > 0 {
+		res.Propose = to.Propose
+	}
+	if to.Prevote > 0 {
+		res.Prevote = to.Prevote
+	}
+	if to.Precommit > 0 {
+		res.Precommit = to.Precommit
+	}
+	if to.NewRound > 0 {
+		res.NewRound = to.NewRound
+	}
+	res.ProposeDelta = to.ProposeDelta
+	res.PrevoteDelta = to.PrevoteDelta
+	res.PrecommitDelta = to.PrecommitDelta
+	res.NewRoundDelta = to.NewRoundDelta
+	return res
+}
+
+// maxRoundTimeout is the limit of timeout of a step growing with rounds.
+const maxRoundTimeout = 10 * time.Minute
+
+// roundTimeout returns timeout of a step in the round. It's limited by
+// maxRoundTimeout.
+func roundTimeout(base, delta time.Duration, round int32) time.Duration {
+	if base >= maxRoundTimeout {
+		return maxRoundTimeout
+	}
+	if delta > 0 && round > 0 &&
+		time.Duration(round) > (maxRoundTimeout-base)/delta {
+		return maxRoundTimeout
+	}
+	return base + delta*time.Duration(round)
+}
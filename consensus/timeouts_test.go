@@ -0,0 +1,62 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/module"
+)
+
+func TestConsensus_TimeoutsFor(t *testing.T) {
+	cs := &consensus{timeoutPropose: 3 * time.Second}
+
+	to := cs.timeoutsFor(nil)
+	assert.Equal(t, module.ConsensusTimeouts{
+		Propose:   3 * time.Second,
+		Prevote:   timeoutPrevote,
+		Precommit: timeoutPrecommit,
+		NewRound:  timeoutNewRound,
+	}, to)
+
+	to = cs.timeoutsFor(&module.ConsensusTimeouts{
+		Prevote:        200 * time.Millisecond,
+		PrevoteDelta:   50 * time.Millisecond,
+		PrecommitDelta: 100 * time.Millisecond,
+	})
+	assert.Equal(t, module.ConsensusTimeouts{
+		Propose:        3 * time.Second,
+		Prevote:        200 * time.Millisecond,
+		PrevoteDelta:   50 * time.Millisecond,
+		Precommit:      timeoutPrecommit,
+		PrecommitDelta: 100 * time.Millisecond,
+		NewRound:       timeoutNewRound,
+	}, to)
+
+	assert.Equal(t, 200*time.Millisecond, roundTimeout(to.Prevote, to.PrevoteDelta, 0))
+	assert.Equal(t, 350*time.Millisecond, roundTimeout(to.Prevote, to.PrevoteDelta, 3))
+	assert.Equal(t, timeoutPrecommit+time.Second, roundTimeout(to.Precommit, to.PrecommitDelta, 10))
+
+	// it's limited for large rounds
+	assert.Equal(t, maxRoundTimeout, roundTimeout(to.Prevote, to.PrevoteDelta, 100_000))
+	assert.Equal(t, maxRoundTimeout, roundTimeout(to.Prevote, 10*time.Second, math.MaxInt32))
+	assert.Equal(t, maxRoundTimeout, roundTimeout(time.Hour, 0, 0))
+}
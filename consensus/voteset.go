@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"bytes"
+	"sort"
 
 	"github.com/icon-project/goloop/module"
 )
@@ -269,6 +270,30 @@ func (hvs *heightVoteSet) getRoundEvidences(minRound int32, nid []byte) *VoteLis
 	return nil
 }
 
+// rounds returns rounds having votes in ascending order.
+func (hvs *heightVoteSet) rounds() []int32 {
+	rounds := make([]int32, 0, len(hvs._votes))
+	for round := range hvs._votes {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		return rounds[i] < rounds[j]
+	})
+	return rounds
+}
+
+// countsFor returns the number of prevotes and precommits of the round.
+func (hvs *heightVoteSet) countsFor(round int32) (prevotes, precommits int) {
+	rvs := hvs._votes[round]
+	if vs := rvs[VoteTypePrevote]; vs != nil {
+		prevotes = vs.count
+	}
+	if vs := rvs[VoteTypePrecommit]; vs != nil {
+		precommits = vs.count
+	}
+	return
+}
+
 // remove votes.
 func (hvs *heightVoteSet) removeLowerRoundExcept(lower int32, except int32) {
 	for round := range hvs._votes {
@@ -434,7 +434,7 @@ func (w *walReader) CloseAndRepair() error {
 				}
 			}
 			for i := idx + 1; i <= w.wi.tailIdx; i++ {
-				if err := os.Remove(fileFor(w.id, idx)); err != nil {
+				if err := os.Remove(fileFor(w.id, i)); err != nil {
 					return errors.WithStack(err)
 				}
 			}
@@ -483,7 +483,6 @@ func (wm *walManager) OpenForWrite(id string, cfg *WALConfig) (WALWriter, error)
 }
 
 func ResetWAL(height int64, dir string, voteListBytes []byte) error {
-	wm := defaultWALManager
 	var err error
 	if err = os.RemoveAll(dir); err != nil {
 		return err
@@ -491,16 +490,20 @@ func ResetWAL(height int64, dir string, voteListBytes []byte) error {
 	if voteListBytes == nil {
 		return nil
 	}
-	ww, err := wm.OpenForWrite(path.Join(dir, configCommitWALID), &WALConfig{
+	return writeCommitWAL(dir, voteListBytes)
+}
+
+func writeCommitWAL(dir string, voteListBytes []byte) error {
+	ww, err := defaultWALManager.OpenForWrite(path.Join(dir, configCommitWALID), &WALConfig{
 		FileLimit:  configCommitWALDataSize,
 		TotalLimit: configCommitWALDataSize * 3,
 	})
-	defer func() {
-		log.Must(ww.Close())
-	}()
 	if err != nil {
 		return err
 	}
+	defer func() {
+		log.Must(ww.Close())
+	}()
 	if _, err = ww.WriteBytes(voteListBytes); err != nil {
 		return err
 	}
@@ -74,3 +74,88 @@ func TestWAL(t *testing.T) {
 	err = wr.Close()
 	assert.NoError(t, err)
 }
+
+func writeTestWAL(t *testing.T, id string, n int) {
+	ww, err := consensus.OpenWALForWrite(id, &consensus.WALConfig{
+		FileLimit:  12 * 3,
+		TotalLimit: 12 * 100,
+	})
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(i))
+		_, err = ww.WriteBytes(buf[:])
+		assert.NoError(t, err)
+		if i%3 == 2 {
+			assert.NoError(t, ww.(interface{ Shift() error }).Shift())
+		}
+	}
+	assert.NoError(t, ww.Close())
+}
+
+func TestWAL_Inspect(t *testing.T) {
+	base := t.TempDir()
+	id := base + "/testwal"
+	writeTestWAL(t, id, 10)
+
+	var offsets []int64
+	cnt, err := consensus.InspectWAL(id, func(rec *consensus.WALRecord) bool {
+		assert.Equal(t, len(offsets), rec.Index)
+		assert.Equal(t, 12, rec.Size)
+		assert.Error(t, rec.DecodeError)
+		offsets = append(offsets, rec.Offset)
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, cnt)
+	assert.EqualValues(t, 12*9, offsets[9])
+
+	assert.Error(t, consensus.TruncateWAL(id, 11))
+	assert.NoError(t, consensus.TruncateWAL(id, 4))
+	cnt, err = consensus.InspectWAL(id, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, cnt)
+
+	// corrupt the last record
+	f, err := os.OpenFile(id+"_1", os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	_, err = f.WriteAt([]byte{0xff}, 11)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	cnt, err = consensus.InspectWAL(id, nil)
+	assert.True(t, consensus.IsCorruptedWAL(err))
+	assert.Equal(t, 3, cnt)
+
+	repaired, err := consensus.RepairWAL(id)
+	assert.NoError(t, err)
+	assert.True(t, repaired)
+	repaired, err = consensus.RepairWAL(id)
+	assert.NoError(t, err)
+	assert.False(t, repaired)
+	cnt, err = consensus.InspectWAL(id, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cnt)
+}
+
+func TestWAL_RebuildCommitWAL(t *testing.T) {
+	base := t.TempDir()
+	writeTestWAL(t, base+"/commit", 5)
+	writeTestWAL(t, base+"/lock", 2)
+
+	assert.NoError(t, consensus.RebuildCommitWAL(base, []byte{0, 1, 2}))
+
+	var payloads int
+	cnt, err := consensus.InspectWAL(base+"/commit", func(rec *consensus.WALRecord) bool {
+		payloads++
+		assert.EqualValues(t, 0, rec.Offset)
+		return true
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cnt)
+	assert.Equal(t, 1, payloads)
+
+	cnt, err = consensus.InspectWAL(base+"/lock", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cnt)
+}
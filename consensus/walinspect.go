@@ -0,0 +1,150 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package consensus
+
+import (
+	"encoding/binary"
+	"os"
+	"path"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+)
+
+// WALIDs are the ids of the WALs in the WAL directory of the consensus.
+var WALIDs = []string{configRoundWALID, configLockWALID, configCommitWALID}
+
+// WALRecord is a record read from the WAL for inspection.
+type WALRecord struct {
+	// Index is the index of the record in the WAL.
+	Index int
+	// Offset is the offset of the record from the beginning of the WAL.
+	Offset int64
+	// Size is the size of the record including the header.
+	Size int
+	// Message is the decoded message. It's nil if DecodeError is not nil.
+	Message Message
+	// DecodeError is the error on decoding the payload.
+	DecodeError error
+}
+
+// DecodeWALPayload decodes the payload of the WAL record, which is
+// a subprotocol followed by the message.
+func DecodeWALPayload(bs []byte) (Message, error) {
+	if len(bs) < 2 {
+		return nil, errors.Errorf("too short wal message len=%v", len(bs))
+	}
+	sp := binary.BigEndian.Uint16(bs[0:2])
+	return UnmarshalMessage(sp, bs[2:])
+}
+
+// InspectWAL reads records of the WAL and calls fn for each valid record
+// until fn returns false. It returns the number of records read and the
+// error stopped reading. It returns nil error on end of the WAL, and
+// CorruptedWAL or UnexpectedEOF error on the broken record.
+func InspectWAL(id string, fn func(rec *WALRecord) bool) (int, error) {
+	wr, err := OpenWALForRead(id)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		log.Must(wr.Close())
+	}()
+	return inspectWAL(wr.(*walReader), -1, fn)
+}
+
+func inspectWAL(w *walReader, limit int, fn func(rec *WALRecord) bool) (int, error) {
+	for cnt := 0; limit < 0 || cnt < limit; cnt++ {
+		offset := w.validOffset
+		bs, err := w.ReadBytes()
+		if IsEOF(err) {
+			return cnt, nil
+		} else if err != nil {
+			return cnt, err
+		}
+		if fn == nil {
+			continue
+		}
+		rec := &WALRecord{
+			Index:  cnt,
+			Offset: offset,
+			Size:   int(w.validOffset - offset),
+		}
+		rec.Message, rec.DecodeError = DecodeWALPayload(bs)
+		if !fn(rec) {
+			return cnt + 1, nil
+		}
+	}
+	return limit, nil
+}
+
+// TruncateWAL keeps the first n records of the WAL and removes the others.
+// It returns an error if the WAL has less than n valid records.
+func TruncateWAL(id string, n int) error {
+	if n < 0 {
+		return errors.IllegalArgumentError.Errorf("InvalidRecordCount(n=%d)", n)
+	}
+	wr, err := OpenWALForRead(id)
+	if err != nil {
+		return err
+	}
+	w := wr.(*walReader)
+	cnt, err := inspectWAL(w, n, nil)
+	if err == nil && cnt < n {
+		err = errors.NotFoundError.Errorf("NotEnoughRecords(n=%d,records=%d)", n, cnt)
+	}
+	if err != nil {
+		log.Must(w.Close())
+		return err
+	}
+	return w.CloseAndRepair()
+}
+
+// RepairWAL truncates the WAL at the first broken record. It returns
+// true if the WAL had a broken record.
+func RepairWAL(id string) (bool, error) {
+	wr, err := OpenWALForRead(id)
+	if err != nil {
+		return false, err
+	}
+	w := wr.(*walReader)
+	_, err = inspectWAL(w, -1, nil)
+	if IsCorruptedWAL(err) || IsUnexpectedEOF(err) {
+		return true, w.CloseAndRepair()
+	}
+	log.Must(w.Close())
+	return false, err
+}
+
+// RebuildCommitWAL replaces the commit WAL in the directory with the
+// record of the votes, which can be made by
+// WALRecordBytesFromCommitVoteListBytes. Other WALs are kept.
+func RebuildCommitWAL(dir string, voteListBytes []byte) error {
+	id := path.Join(dir, configCommitWALID)
+	wi, err := readWALInfo(id)
+	if err != nil && !IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		for idx := wi.headIdx; idx <= wi.tailIdx; idx++ {
+			if err := os.Remove(fileFor(id, idx)); err != nil && !os.IsNotExist(err) {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return writeCommitWAL(dir, voteListBytes)
+}
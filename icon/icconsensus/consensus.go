@@ -141,3 +141,33 @@ func (c *wrapper) Term() {
 		c.Consensus.Term()
 	}
 }
+
+func (c *wrapper) GetConsensusRoundState() (*module.ConsensusRoundState, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cm, ok := c.Consensus.(module.ConsensusMonitor); ok {
+		return cm.GetConsensusRoundState()
+	}
+	return nil, errors.InvalidStateError.New("ConsensusNotStarted")
+}
+
+func (c *wrapper) WatchSteps(ch chan<- *module.ConsensusStepEvent) (func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cm, ok := c.Consensus.(module.ConsensusMonitor); ok {
+		return cm.WatchSteps(ch)
+	}
+	return nil, errors.InvalidStateError.New("ConsensusNotStarted")
+}
+
+func (c *wrapper) GetSyncStatus() *module.ConsensusSyncStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if sm, ok := c.Consensus.(module.ConsensusSyncMonitor); ok {
+		return sm.GetSyncStatus()
+	}
+	return nil
+}
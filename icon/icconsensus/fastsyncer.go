@@ -133,6 +133,29 @@ func (f *fastSyncer) GetStatus() *module.ConsensusStatus {
 	}
 }
 
+func (f *fastSyncer) GetSyncStatus() *module.ConsensusSyncStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.running || f.fsm == nil {
+		return nil
+	}
+	fs := f.fsm.FetchStatus()
+	if fs == nil {
+		return nil
+	}
+	target := f.to
+	if target < fs.Next {
+		target = fs.Next
+	}
+	return &module.ConsensusSyncStatus{
+		Height:       fs.Next,
+		TargetHeight: target,
+		Peers:        fs.Peers,
+		Rate:         fs.Rate,
+	}
+}
+
 func (f *fastSyncer) GetVotesByHeight(height int64) (module.CommitVoteSet, error) {
 	return nil, errors.NotFoundError.New("not found")
 }
@@ -250,6 +250,10 @@ func (sm *ServiceManager) GetRoundLimit(result []byte, vl int) int64 {
 	return 0
 }
 
+func (sm *ServiceManager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	return nil
+}
+
 func (sm *ServiceManager) GetMinimizeBlockGen(result []byte) bool {
 	return true
 }
@@ -270,6 +274,22 @@ func (sm *ServiceManager) WaitTransactionResult(id []byte) (<-chan interface{},
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) WatchPendingTransactions(ch chan<- *module.PendingTransaction) func() {
+	return func() {}
+}
+
+func (sm *ServiceManager) GetPendingTransactions(group module.TransactionGroup, from module.Address, skip, limit int) ([]module.Transaction, int, error) {
+	return nil, 0, errors.ErrInvalidState
+}
+
+func (sm *ServiceManager) GetTxPoolStatus() ([]*module.TxPoolStatus, error) {
+	return nil, errors.ErrInvalidState
+}
+
+func (sm *ServiceManager) EvictTransaction(id []byte) error {
+	return errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) ExportResult(result []byte, vh []byte, dst db.Database) error {
 	return errors.ErrInvalidState
 }
@@ -282,6 +302,10 @@ func (sm *ServiceManager) ExecuteTransaction(result []byte, vh []byte, js []byte
 	return nil, errors.ErrInvalidState
 }
 
+func (sm *ServiceManager) SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi module.BlockInfo, overrides []*module.AccountOverride) ([]*module.SimulationResult, error) {
+	return nil, errors.ErrInvalidState
+}
+
 func (sm *ServiceManager) AddSyncRequest(id db.BucketID, key []byte) error {
 	return errors.ErrInvalidState
 }
@@ -38,6 +38,9 @@ type Chain interface {
 	ChildrenLimit() int
 	NephewsLimit() int
 	ValidateTxOnSend() bool
+	// TxIndexEnabled returns whether the chain maintains the index of
+	// transactions by addresses.
+	TxIndexEnabled() bool
 	Genesis() []byte
 	GenesisStorage() GenesisStorage
 	CommitVoteSetDecoder() CommitVoteSetDecoder
@@ -56,7 +59,13 @@ type Chain interface {
 	Stop() error
 	Import(src string, height int64) error
 	Prune(gs string, dbt string, height int64) error
-	Backup(file string, extra []string) error
+	// Backup stores chain data into the file. If base is not empty, then
+	// it stores only the files changed since the base backup.
+	Backup(file, base string, extra []string) error
+	// HotBackup stores chain data into the file like Backup, but it
+	// doesn't stop the chain. It's available only while the chain is
+	// running.
+	HotBackup(file, base string, extra []string) error
 	RunTask(task string, params json.RawMessage) error
 	Term() error
 	State() (string, int64, error)
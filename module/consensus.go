@@ -1,17 +1,101 @@
 package module
 
+import "time"
+
 type ConsensusStatus struct {
 	Height   int64
 	Round    int32
 	Proposer bool
 }
 
+// ConsensusTimeouts is a set of timeouts for round steps. Timeout of a step
+// in round r is Step + r*StepDelta. Zero value of a step means the default
+// of the consensus.
+type ConsensusTimeouts struct {
+	Propose        time.Duration
+	ProposeDelta   time.Duration
+	Prevote        time.Duration
+	PrevoteDelta   time.Duration
+	Precommit      time.Duration
+	PrecommitDelta time.Duration
+	NewRound       time.Duration
+	NewRoundDelta  time.Duration
+}
+
 const (
 	FlagNextProofContext = 0x1
 	FlagBTPBlockHeader   = 0x2
 	FlagBTPBlockProof    = 0x4
 )
 
+// ConsensusRoundVotes is the votes of a round received by the consensus.
+// Each character of Prevotes and Precommits is for the validator of the
+// index, 'x' for a received vote and '_' for a missing one.
+type ConsensusRoundVotes struct {
+	Round      int32
+	Prevotes   string
+	Precommits string
+}
+
+// ConsensusRoundState is a snapshot of the consensus state machine.
+type ConsensusRoundState struct {
+	Height        int64
+	Round         int32
+	Step          string
+	Proposer      Address
+	LockedRound   int32
+	LockedBlockID []byte
+
+	// ValidRound is the latest round having over two thirds of prevotes
+	// for a block in Votes, and ValidBlockID is the block. They are not
+	// kept by the consensus.
+	ValidRound   int32
+	ValidBlockID []byte
+
+	Votes []*ConsensusRoundVotes
+}
+
+// ConsensusStepEvent is delivered on each step transition of the consensus.
+type ConsensusStepEvent struct {
+	Height    int64
+	Round     int32
+	Step      string
+	Proposer  Address
+	Timestamp time.Time
+}
+
+// ConsensusMonitor is implemented by Consensus providing its internal
+// state for monitoring.
+type ConsensusMonitor interface {
+	// GetConsensusRoundState returns the current state of the consensus.
+	GetConsensusRoundState() (*ConsensusRoundState, error)
+
+	// WatchSteps registers the channel to receive step transitions. The
+	// channel is closed if it's full or the consensus is terminated.
+	WatchSteps(ch chan<- *ConsensusStepEvent) (cancel func(), err error)
+}
+
+// ConsensusSyncStatus is the status of the consensus catching up with
+// peers by fetching blocks.
+type ConsensusSyncStatus struct {
+	// Height is the height of the next block to be fetched.
+	Height int64
+	// TargetHeight is the highest height known from peers.
+	TargetHeight int64
+	// Peers is the number of peers serving blocks.
+	Peers int
+	// Rate is the number of fetched blocks per second.
+	Rate float64
+}
+
+// ConsensusSyncMonitor is implemented by Consensus providing its progress
+// of catching up with peers.
+type ConsensusSyncMonitor interface {
+	// GetSyncStatus returns the status of fetching blocks. It returns nil
+	// if the consensus is not fetching blocks.
+	GetSyncStatus() *ConsensusSyncStatus
+}
+
 type Consensus interface {
 	Start() error
 	Term()
@@ -42,4 +42,20 @@ type DoubleSignContextRoot interface {
 	ContextOf(tn string) (DoubleSignContext, error)
 }
 
+// ConsensusSigner is implemented by wallets checking consensus messages
+// before signing them (e.g. for protection against double signing).
+// nid is the network ID of the chain, t is DSTProposal or DSTVote, and msg
+// is the bytes of the message. It returns the signature for SHA3-256 hash
+// of msg.
+type ConsensusSigner interface {
+	SignConsensusMessage(nid int, t string, msg []byte) ([]byte, error)
+}
+
+// ContentSigner is implemented by wallets hashing the content by themselves
+// before signing it, so that they can refuse the content of consensus
+// messages. It returns the signature for SHA3-256 hash of content.
+type ContentSigner interface {
+	SignContent(content []byte) ([]byte, error)
+}
+
 type DoubleSignDataDecoder func (t string, d []byte) (DoubleSignData, error)
@@ -297,6 +297,97 @@ type TransitionManager interface {
 	WaitForTransaction(parent Transition, bi BlockInfo, cb func()) bool
 }
 
+// AccountOverride is the state of the account replaced before simulating
+// transactions.
+type AccountOverride struct {
+	Address Address
+
+	// Balance replaces the balance of the account if it's not nil.
+	Balance *big.Int
+
+	// Owner replaces the owner of the contract if it's not nil.
+	// Content is deployed to the account as the code of the contract with
+	// ContentType and Params by the owner if it's not empty. The current
+	// owner is used if Owner is nil.
+	Owner       Address
+	ContentType string
+	Content     []byte
+	Params      []byte
+
+	// Storage replaces the values in the storage of the account.
+	// The entry with nil value is deleted.
+	Storage map[string][]byte
+}
+
+// BalanceChange is the change of the balance of the account made by
+// a simulated transaction.
+type BalanceChange struct {
+	Address Address
+	Balance *big.Int
+	Delta   *big.Int
+}
+
+// SimulationResult is the result of a simulated transaction.
+type SimulationResult struct {
+	Receipt        Receipt
+	BalanceChanges []*BalanceChange
+}
+
+// PendingTxEvent is the kind of the change of a transaction in the
+// transaction pool.
+type PendingTxEvent int
+
+const (
+	// PendingTxAdded is for the transaction accepted by the pool.
+	PendingTxAdded PendingTxEvent = iota
+	// PendingTxRemoved is for the transaction included in a finalized block.
+	PendingTxRemoved
+	// PendingTxDropped is for the transaction dropped from the pool without
+	// being included in a block. Err of the notification has the reason.
+	PendingTxDropped
+)
+
+func (e PendingTxEvent) String() string {
+	switch e {
+	case PendingTxAdded:
+		return "added"
+	case PendingTxRemoved:
+		return "removed"
+	case PendingTxDropped:
+		return "dropped"
+	default:
+		return fmt.Sprintf("PendingTxEvent(%d)", int(e))
+	}
+}
+
+// PendingTransaction is the notification for the change of a transaction
+// in the transaction pool.
+type PendingTransaction struct {
+	Event PendingTxEvent
+	Tx    Transaction
+	Err   error
+}
+
+// TxPoolStatus is the status of the transaction pool for a group.
+type TxPoolStatus struct {
+	Group TransactionGroup
+
+	// Size is the capacity of the pool and Used is the number of
+	// transactions in the pool.
+	Size int
+	Used int
+
+	// OldestTimestamp is the timestamp of the oldest transaction in the pool.
+	// It's zero if the pool is empty.
+	OldestTimestamp int64
+
+	// Dropped is the number of transactions dropped from the pool without
+	// being included in a block, and Expired is the number of expired ones
+	// among them. They are counted from the start of the chain.
+	Dropped int64
+	Expired int64
+}
+
 type ServiceManager interface {
 	TransitionManager
 
@@ -359,6 +450,10 @@ type ServiceManager interface {
 	// GetRoundLimit returns round limit
 	GetRoundLimit(result []byte, vl int) int64
 
+	// GetConsensusTimeouts returns timeouts of round steps. It returns nil
+	// if none of them is configured.
+	GetConsensusTimeouts(result []byte) *ConsensusTimeouts
+
 	// GetStepPrice returns the step price of the state
 	GetStepPrice(result []byte) (*big.Int, error)
 
@@ -395,6 +490,25 @@ type ServiceManager interface {
 	// WaitTransactionResult return channel for result.
 	WaitTransactionResult(id []byte) (<-chan interface{}, error)
 
+	// WatchPendingTransactions registers the channel to receive changes of
+	// transactions in the pool. Notifications are never blocked by the
+	// channel, so it's closed if it's full. It returns the function
+	// cancelling the registration.
+	WatchPendingTransactions(ch chan<- *PendingTransaction) (cancel func())
+
+	// GetPendingTransactions returns transactions of the group in the pool.
+	// If from is not nil, it returns only transactions sent by from.
+	// It skips first skip transactions and returns at most limit
+	// transactions along with the number of all matching transactions.
+	GetPendingTransactions(group TransactionGroup, from Address, skip, limit int) ([]Transaction, int, error)
+
+	// GetTxPoolStatus returns status of the transaction pools.
+	GetTxPoolStatus() ([]*TxPoolStatus, error)
+
+	// EvictTransaction drops the transaction from the pool. Waiters for the
+	// result of the transaction get an error.
+	EvictTransaction(id []byte) error
+
 	// ExportResult exports all related entries related with the result
 	// should be exported to the database
 	ExportResult(result []byte, vh []byte, dst db.Database) error
@@ -408,6 +522,12 @@ type ServiceManager interface {
 	// It ignores supplied step limit.
 	ExecuteTransaction(result []byte, vh []byte, js []byte, bi BlockInfo) (Receipt, error)
 
+	// SimulateTransactions executes the transactions in order on the
+	// specified state after applying the overrides. Then it returns the
+	// results of the transactions. The state is discarded after the
+	// execution. It ignores supplied step limits.
+	SimulateTransactions(result []byte, vh []byte, txs [][]byte, bi BlockInfo, overrides []*AccountOverride) ([]*SimulationResult, error)
+
 	// AddSyncRequest add sync request for specified data.
 	AddSyncRequest(id db.BucketID, key []byte) error
 
@@ -41,6 +41,7 @@ const (
 	TraceModeNone TraceMode = iota
 	TraceModeInvoke
 	TraceModeBalanceChange
+	TraceModeCallTree
 )
 
 type OpType int
@@ -81,3 +82,22 @@ type TraceCallback interface {
 	OnFrameExit(success bool) error
 	OnBalanceChange(opType OpType, from, to Address, amount *big.Int) error
 }
+
+// TraceCall is the information of a call frame, which is given to
+// CallTraceCallback on entering the frame.
+type TraceCall struct {
+	Type   string
+	From   Address
+	To     Address
+	Value  *big.Int
+	Method string
+	Params interface{}
+}
+
+// CallTraceCallback is implemented by TraceCallback handling
+// TraceModeCallTree to get the details of each call frame.
+type CallTraceCallback interface {
+	OnCallEnter(call *TraceCall) error
+	OnCallExit(status error, stepUsed *big.Int, result interface{}, addr Address) error
+	OnCallEvent(addr Address, indexed, data [][]byte) error
+}
@@ -97,6 +97,10 @@ func (a *Authenticator) onPacket(pkt *Packet, p *Peer) {
 func (a *Authenticator) Signature(content []byte) []byte {
 	defer a.mtx.Unlock()
 	a.mtx.Lock()
+	if cs, ok := a.wallet.(module.ContentSigner); ok {
+		sb, _ := cs.SignContent(content)
+		return sb
+	}
 	h := crypto.SHA3Sum256(content)
 	sb, _ := a.wallet.Sign(h)
 	return sb
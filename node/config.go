@@ -15,6 +15,8 @@ import (
 const (
 	ChainConfigFileName     = "config.json"
 	ChainGenesisZipFileName = "genesis.zip"
+
+	SlashingProtectionFileName = "slashing_protection.json"
 )
 
 type StaticConfig struct {
@@ -28,6 +30,8 @@ type StaticConfig struct {
 	Engines       string `json:"engines"`
 	BackupDir     string `json:"backup_dir"`
 
+	SlashingProtection string `json:"slashing_protection"`
+
 	AuthSkipIfEmptyUsers bool `json:"auth_skip_if_empty_users,omitempty"`
 	NIDForP2P            bool `json:"nid_for_p2p,omitempty"`
 
@@ -79,6 +83,9 @@ func (c *StaticConfig) SetFilePath(path string) string {
 	if c.BackupDir != "" {
 		c.BackupDir = c.ResolveRelative(ResolveAbsolute(o, c.BackupDir))
 	}
+	if c.SlashingProtection != "" {
+		c.SlashingProtection = c.ResolveRelative(ResolveAbsolute(o, c.SlashingProtection))
+	}
 	return o
 }
 
@@ -89,6 +96,9 @@ func (c *StaticConfig) FillEmpty(addr module.Address) {
 	if c.BackupDir == "" {
 		c.BackupDir = path.Join(c.BaseDir, "backup")
 	}
+	if c.SlashingProtection == "" {
+		c.SlashingProtection = path.Join(c.BaseDir, SlashingProtectionFileName)
+	}
 	if c.CliSocket == "" {
 		c.CliSocket = path.Join(c.BaseDir, "cli.sock")
 	}
@@ -110,10 +120,14 @@ type RuntimeConfig struct {
 	RPCDefaultChannel string `json:"rpcDefaultChannel"`
 	RPCIncludeDebug   bool   `json:"rpcIncludeDebug"`
 	RPCRosetta        bool   `json:"rpcRosetta"`
+	RPCEth            bool   `json:"rpcEth"`
+	RPCGraphQL        bool   `json:"rpcGraphQL"`
 	DisableRPC        bool   `json:"disableRPC"`
 	RPCBatchLimit     int    `json:"rpcBatchLimit"`
 	WSMaxSession      int    `json:"wsMaxSession"`
 
+	RPCRateLimit *server.RateLimitConfig `json:"rpcRateLimit,omitempty"`
+
 	FilePath string `json:"-"` // absolute path
 }
 
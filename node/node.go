@@ -16,6 +16,7 @@ import (
 	"github.com/icon-project/goloop/chain/gs"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -34,6 +35,7 @@ type Node struct {
 	srv  *server.Manager
 	pm   eeproxy.Manager
 	rsm  RestoreManager
+	sp   *consensus.SlashingProtection
 	cfg  StaticConfig
 	rcfg *RuntimeConfig
 
@@ -228,6 +230,7 @@ func (n *Node) _add(cfg *chain.Config) (module.Chain, error) {
 		return nil, err
 	}
 
+	cfg.SlashingProtection = n.sp
 	c := &Chain{chain.NewChain(n.w, n.nt, n.srv, n.pm, n.logger, cfg), cfg, false}
 	if err := c.Init(); err != nil {
 		return nil, err
@@ -392,6 +395,8 @@ func (n *Node) JoinChain(
 	cfg := &chain.Config{
 		NID:              nid,
 		DBType:           p.DBType,
+		DBKeyStore:       p.DBKeyStore,
+		DBKeySecret:      p.DBKeySecret,
 		Platform:         p.Platform,
 		Channel:          channel,
 		SecureSuites:     p.SecureSuites,
@@ -413,6 +418,8 @@ func (n *Node) JoinChain(
 		ChildrenLimit:    p.ChildrenLimit,
 		NephewsLimit:     p.NephewsLimit,
 		ValidateTxOnSend: p.ValidateTxOnSend,
+		DBMetrics:        p.DBMetrics,
+		TxIndex:          p.TxIndex,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -505,6 +512,23 @@ func (n *Node) VerifyChain(cid int) error {
 	return c.Verify()
 }
 
+// EvictTransaction drops the transaction from the transaction pool of
+// the running chain.
+func (n *Node) EvictTransaction(cid int, id []byte) error {
+	defer n.mtx.RUnlock()
+	n.mtx.RLock()
+
+	c, err := n._get(cid)
+	if err != nil {
+		return err
+	}
+	sm := c.ServiceManager()
+	if sm == nil {
+		return errors.InvalidStateError.Errorf("NotRunning(cid=%#x)", cid)
+	}
+	return sm.EvictTransaction(id)
+}
+
 func (n *Node) ImportChain(cid int, s string, height int64) error {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
@@ -529,7 +553,10 @@ func (n *Node) PruneChain(cid int, dbt string, height int64) error {
 	return c.Prune(gs, dbt, height)
 }
 
-func (n *Node) BackupChain(cid int, manual bool) (string, error) {
+// BackupChain starts to back up the chain. If base is not empty, then it
+// makes an incremental backup based on the backup named base. If hot is
+// true, then it backs up the running chain without stopping it.
+func (n *Node) BackupChain(cid int, manual, hot bool, base string) (string, error) {
 	defer n.mtx.RUnlock()
 	n.mtx.RLock()
 
@@ -539,18 +566,38 @@ func (n *Node) BackupChain(cid int, manual bool) (string, error) {
 	}
 
 	if manual {
-		return "manual", c.Backup("", nil)
+		if base != "" {
+			return "", errors.IllegalArgumentError.New("BaseForManualBackup")
+		}
+		if hot {
+			return "", errors.IllegalArgumentError.New("HotForManualBackup")
+		}
+		return "manual", c.Backup("", "", nil)
 	}
 	backupDir := n.cfg.ResolveAbsolute(n.cfg.BackupDir)
 	if err := os.MkdirAll(backupDir, 0700); err != nil {
 		return "", errors.InvalidStateError.Wrapf(err,
 			"Fail to make backup directory=%s", backupDir)
 	}
+	var baseFile string
+	if base != "" {
+		if path.Base(base) != base {
+			return "", errors.IllegalArgumentError.Errorf("InvalidBaseName(base=%s)", base)
+		}
+		baseFile = path.Join(backupDir, base)
+		if _, err := os.Stat(baseFile); err != nil {
+			return "", errors.NotFoundError.Wrapf(err, "BaseNotFound(base=%s)", base)
+		}
+	}
 	now := time.Now()
 	name := fmt.Sprintf("%#x_%#x_%s_%s.zip", c.CID(), c.NID(), c.Channel(),
 		now.Format("20060102-150405"))
 	file := path.Join(backupDir, name)
-	return name, c.Backup(file, []string{ChainGenesisZipFileName, ChainConfigFileName})
+	extra := []string{ChainGenesisZipFileName, ChainConfigFileName}
+	if hot {
+		return name, c.HotBackup(file, baseFile, extra)
+	}
+	return name, c.Backup(file, baseFile, extra)
 }
 
 type BackupInfo struct {
@@ -770,6 +817,18 @@ func (n *Node) ConfigureChain(cid int, key string, value string) error {
 			} else {
 				c.cfg.ValidateTxOnSend = bc
 			}
+		case "dbMetrics":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.DBMetrics = bc
+			}
+		case "txIndex":
+			if bc, err := strconv.ParseBool(value); err != nil {
+				return errors.Wrapf(err, "InvalidValueType(exp=bool,val=%s)", value)
+			} else {
+				c.cfg.TxIndex = bc
+			}
 		default:
 			return errors.Errorf("not found key %s", key)
 		}
@@ -882,6 +941,20 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.RPCRosetta = boolVal
 		}
 		n.srv.SetRosetta(n.rcfg.RPCRosetta)
+	case "rpcEth":
+		if boolVal, err := strconv.ParseBool(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCEth = boolVal
+		}
+		n.srv.SetEth(n.rcfg.RPCEth)
+	case "rpcGraphQL":
+		if boolVal, err := strconv.ParseBool(value); err != nil {
+			return errors.Wrapf(err, "invalid value type")
+		} else {
+			n.rcfg.RPCGraphQL = boolVal
+		}
+		n.srv.SetGraphQL(n.rcfg.RPCGraphQL)
 	case "disableRPC":
 		if boolVal, err := strconv.ParseBool(value); err != nil {
 			return errors.Wrapf(err, "invalid value type")
@@ -903,6 +976,17 @@ func (n *Node) Configure(key string, value string) error {
 			n.rcfg.WSMaxSession = intVal
 		}
 		n.srv.SetWSMaxSession(n.rcfg.WSMaxSession)
+	case "rpcRateLimit":
+		var cfg *server.RateLimitConfig
+		if value != "" {
+			if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+				return errors.Wrapf(err, "invalid value type")
+			}
+		}
+		if err := n.srv.SetRateLimit(cfg); err != nil {
+			return err
+		}
+		n.rcfg.RPCRateLimit = cfg
 	default:
 		return errors.Errorf("not found key")
 	}
@@ -929,6 +1013,10 @@ func NewNode(
 	if err != nil {
 		log.Panicf("fail to load runtime config err=%+v", err)
 	}
+	sp, err := consensus.OpenSlashingProtection(cfg.ResolveAbsolute(cfg.SlashingProtection))
+	if err != nil {
+		log.Panicf("fail to open slashing protection err=%+v", err)
+	}
 
 	nt := network.NewTransport(cfg.P2PAddr, w, l)
 	if cfg.P2PListenAddr != "" {
@@ -939,10 +1027,13 @@ func NewNode(
 		JSONRPCDump:           cfg.RPCDump,
 		JSONRPCIncludeDebug:   rcfg.RPCIncludeDebug,
 		JSONRPCRosetta:        rcfg.RPCRosetta,
+		JSONRPCEth:            rcfg.RPCEth,
+		GraphQL:               rcfg.RPCGraphQL,
 		DisableRPC:            rcfg.DisableRPC,
 		JSONRPCDefaultChannel: rcfg.RPCDefaultChannel,
 		JSONRPCBatchLimit:     rcfg.RPCBatchLimit,
 		WSMaxSession:          rcfg.WSMaxSession,
+		RateLimit:             rcfg.RPCRateLimit,
 	}
 	srv := server.NewManager(config, w, l)
 
@@ -973,6 +1064,7 @@ func NewNode(
 		nt:       nt,
 		srv:      srv,
 		pm:       pm,
+		sp:       sp,
 		logger:   l,
 		cfg:      *cfg,
 		rcfg:     rcfg,
@@ -20,6 +20,7 @@ import (
 	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/network"
 	"github.com/icon-project/goloop/server"
@@ -83,8 +84,28 @@ type ChainInspectView struct {
 	Module map[string]interface{} `json:"module"`
 }
 
+type ConsensusView struct {
+	Height        int64                 `json:"height"`
+	Round         int32                 `json:"round"`
+	Step          string                `json:"step"`
+	Proposer      *common.Address       `json:"proposer,omitempty"`
+	LockedRound   int32                 `json:"lockedRound"`
+	LockedBlockID common.HexBytes       `json:"lockedBlockID,omitempty"`
+	ValidRound    int32                 `json:"validRound"`
+	ValidBlockID  common.HexBytes       `json:"validBlockID,omitempty"`
+	Votes         []*ConsensusVotesView `json:"votes"`
+}
+
+type ConsensusVotesView struct {
+	Round      int32  `json:"round"`
+	Prevotes   string `json:"prevotes"`
+	Precommits string `json:"precommits"`
+}
+
 type ChainConfig struct {
 	DBType           string `json:"dbType"`
+	DBKeyStore       string `json:"dbKeyStore,omitempty"`
+	DBKeySecret      string `json:"dbKeySecret,omitempty"`
 	Platform         string `json:"platform"`
 	SeedAddr         string `json:"seedAddress"`
 	Role             uint   `json:"role"`
@@ -103,6 +124,8 @@ type ChainConfig struct {
 	ChildrenLimit    *int   `json:"childrenLimit,omitempty"`
 	NephewsLimit     *int   `json:"nephewsLimit,omitempty"`
 	ValidateTxOnSend bool   `json:"validateTxOnSend,omitempty"`
+	DBMetrics        bool   `json:"dbMetrics,omitempty"`
+	TxIndex          bool   `json:"txIndex,omitempty"`
 }
 
 type ChainResetParam struct {
@@ -120,8 +143,19 @@ type ChainPruneParam struct {
 	Height int64  `json:"height"`
 }
 
+type ChainEvictParam struct {
+	TxHash common.HexBytes `json:"txHash"`
+}
+
 type ChainBackupParam struct {
-	Manual bool `json:"manual,omitempty"`
+	Manual bool   `json:"manual,omitempty"`
+	Hot    bool   `json:"hot,omitempty"`
+	Base   string `json:"base,omitempty"`
+}
+
+type SlashingProtectionResetParam struct {
+	Address *common.Address `json:"address,omitempty"`
+	NID     common.HexInt32 `json:"nid"`
 }
 
 type ConfigureParam struct {
@@ -164,9 +198,33 @@ func NewChainInspectView(c *Chain) *ChainInspectView {
 	return v
 }
 
+func NewConsensusView(rs *module.ConsensusRoundState) *ConsensusView {
+	v := &ConsensusView{
+		Height:        rs.Height,
+		Round:         rs.Round,
+		Step:          rs.Step,
+		Proposer:      common.AddressToPtr(rs.Proposer),
+		LockedRound:   rs.LockedRound,
+		LockedBlockID: rs.LockedBlockID,
+		ValidRound:    rs.ValidRound,
+		ValidBlockID:  rs.ValidBlockID,
+		Votes:         make([]*ConsensusVotesView, 0, len(rs.Votes)),
+	}
+	for _, rv := range rs.Votes {
+		v.Votes = append(v.Votes, &ConsensusVotesView{
+			Round:      rv.Round,
+			Prevotes:   rv.Prevotes,
+			Precommits: rv.Precommits,
+		})
+	}
+	return v
+}
+
 func NewChainConfig(cfg *chain.Config) *ChainConfig {
 	v := &ChainConfig{
 		DBType:           cfg.DBType,
+		DBKeyStore:       cfg.DBKeyStore,
+		DBKeySecret:      cfg.DBKeySecret,
 		Platform:         cfg.Platform,
 		SeedAddr:         cfg.SeedAddr,
 		Role:             cfg.Role,
@@ -185,10 +243,26 @@ func NewChainConfig(cfg *chain.Config) *ChainConfig {
 		ChildrenLimit:    cfg.ChildrenLimit,
 		NephewsLimit:     cfg.NephewsLimit,
 		ValidateTxOnSend: cfg.ValidateTxOnSend,
+		DBMetrics:        cfg.DBMetrics,
+		TxIndex:          cfg.TxIndex,
 	}
 	return v
 }
 
+func inspectBackup(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		c = nc.Chain
+	}
+	return chain.InspectBackup(c, informal)
+}
+
+func inspectTxIndex(c module.Chain, informal bool) map[string]interface{} {
+	if nc, ok := c.(*Chain); ok {
+		c = nc.Chain
+	}
+	return chain.InspectTxIndex(c, informal)
+}
+
 func RegisterInspectFunc(name string, f InspectFunc) error {
 	if _, ok := inspectFuncs[name]; ok {
 		return fmt.Errorf("already exist function name:%s", name)
@@ -216,6 +290,8 @@ func RegisterRest(n *Node) {
 	_ = RegisterInspectFunc("metrics", metric.Inspect)
 	_ = RegisterInspectFunc("network", network.Inspect)
 	_ = RegisterInspectFunc("service", service.Inspect)
+	_ = RegisterInspectFunc("backup", inspectBackup)
+	_ = RegisterInspectFunc("txindex", inspectTxIndex)
 
 	// json rpc
 	n.srv.RegisterAPIHandler(n.cliSrv.e.Group("/api"))
@@ -237,11 +313,13 @@ func (r *Rest) RegisterChainHandlers(g *echo.Group) {
 	g.POST(UrlChainRes+"/import", r.ImportChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/prune", r.PruneChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/backup", r.BackupChain, r.ChainInjector)
+	g.POST(UrlChainRes+"/evict", r.EvictTransaction, r.ChainInjector)
 	route := g.GET(UrlChainRes+"/genesis", r.GetChainGenesis, r.ChainInjector)
 	if r.a != nil {
 		r.a.SetSkip(route, false)
 	}
 	g.GET(UrlChainRes+"/configure", r.GetChainConfig, r.ChainInjector)
+	g.GET(UrlChainRes+"/consensus", r.GetChainConsensus, r.ChainInjector)
 	g.POST(UrlChainRes+"/configure", r.ConfigureChain, r.ChainInjector)
 	g.POST(UrlChainRes+"/:"+TaskID, r.RunChainTask, r.ChainInjector)
 }
@@ -421,13 +499,31 @@ func (r *Rest) BackupChain(ctx echo.Context) error {
 	if err := ctx.Bind(param); err != nil {
 		return echo.ErrBadRequest
 	}
-	if name, err := r.n.BackupChain(c.CID(), param.Manual); err != nil {
+	if name, err := r.n.BackupChain(c.CID(), param.Manual, param.Hot, param.Base); err != nil {
 		return err
 	} else {
 		return ctx.String(http.StatusOK, name)
 	}
 }
 
+func (r *Rest) EvictTransaction(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	param := &ChainEvictParam{}
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	if len(param.TxHash) == 0 {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.EvictTransaction(c.CID(), param.TxHash); err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return ctx.String(http.StatusNotFound, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) GetChainGenesis(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	gsFile := path.Join(c.cfg.AbsBaseDir(), ChainGenesisZipFileName)
@@ -439,6 +535,22 @@ func (r *Rest) GetChainConfig(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, NewChainConfig(c.cfg))
 }
 
+func (r *Rest) GetChainConsensus(ctx echo.Context) error {
+	c := ctx.Get("chain").(*Chain)
+	cm, ok := c.Consensus().(module.ConsensusMonitor)
+	if !ok {
+		return ctx.String(http.StatusConflict, "consensus is not running")
+	}
+	rs, err := cm.GetConsensusRoundState()
+	if err != nil {
+		if errors.InvalidStateError.Equals(err) {
+			return ctx.String(http.StatusConflict, err.Error())
+		}
+		return err
+	}
+	return ctx.JSON(http.StatusOK, NewConsensusView(rs))
+}
+
 func (r *Rest) ConfigureChain(ctx echo.Context) error {
 	c := ctx.Get("chain").(*Chain)
 	p := &ConfigureParam{}
@@ -474,6 +586,9 @@ func (r *Rest) RegisterSystemHandlers(g *echo.Group) {
 	g.POST("/configure", r.ConfigureSystem)
 	r.RegistryBackupHandlers(g.Group("/backup"))
 	r.RegistryRestoreHandlers(g.Group("/restore"))
+	g.GET("/slashing_protection", r.ExportSlashingProtection)
+	g.POST("/slashing_protection", r.ImportSlashingProtection)
+	g.POST("/slashing_protection/reset", r.ResetSlashingProtection)
 }
 
 func (r *Rest) GetSystem(ctx echo.Context) error {
@@ -511,6 +626,39 @@ func (r *Rest) ConfigureSystem(ctx echo.Context) error {
 	return ctx.String(http.StatusOK, "OK")
 }
 
+func (r *Rest) ExportSlashingProtection(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, r.n.sp.Export())
+}
+
+func (r *Rest) ImportSlashingProtection(ctx echo.Context) error {
+	data := new(consensus.SlashingProtectionData)
+	if err := ctx.Bind(data); err != nil {
+		return echo.ErrBadRequest
+	}
+	if err := r.n.sp.Import(data); err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return ctx.String(http.StatusBadRequest, err.Error())
+		}
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
+func (r *Rest) ResetSlashingProtection(ctx echo.Context) error {
+	param := new(SlashingProtectionResetParam)
+	if err := ctx.Bind(param); err != nil {
+		return echo.ErrBadRequest
+	}
+	var addr module.Address = r.n.w.Address()
+	if param.Address != nil {
+		addr = param.Address
+	}
+	if err := r.n.sp.Reset(addr, int(param.NID.Value)); err != nil {
+		return err
+	}
+	return ctx.String(http.StatusOK, "OK")
+}
+
 func (r *Rest) RegistryBackupHandlers(g *echo.Group) {
 	g.GET("", r.GetBackups)
 }
@@ -20,8 +20,10 @@ import (
 	"archive/zip"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"sync"
 
 	"github.com/icon-project/goloop/chain"
@@ -79,6 +81,63 @@ type RestoreManager struct {
 	lastErr error
 }
 
+// openBackups opens the backup and its base backups in the same directory,
+// and returns them in order from the full backup.
+func openBackups(file string) (ret []*zip.ReadCloser, err error) {
+	defer func() {
+		if err != nil {
+			for _, zr := range ret {
+				zr.Close()
+			}
+			ret = nil
+		}
+	}()
+
+	var last *chain.BackupInfo
+	visited := make(map[string]bool)
+	for {
+		if visited[file] {
+			return ret, errors.IllegalArgumentError.Errorf(
+				"CircularBackupBase(backup=%s)", path.Base(file))
+		}
+		visited[file] = true
+
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return ret, errors.IllegalArgumentError.Wrapf(err,
+				"ZipOpenFailure(backup=%s)", file)
+		}
+		ret = append([]*zip.ReadCloser{zr}, ret...)
+
+		info, err := chain.ReadBackupInfo(&zr.Reader)
+		if err != nil {
+			return ret, errors.IllegalArgumentError.Wrap(err,
+				"InvalidBackupInfo")
+		}
+		if last != nil {
+			if info.CID != last.CID || info.NID != last.NID ||
+				info.Channel != last.Channel || info.Codec != last.Codec ||
+				info.Height > last.Height {
+				return ret, errors.IllegalArgumentError.Errorf(
+					"IncompatibleBaseBackup(backup=%s)", path.Base(file))
+			}
+		} else if info.Codec != codec.BC.Name() {
+			return ret, errors.IllegalArgumentError.Errorf(
+				"IncompatibleCodec(backup=%s,system=%s)",
+				info.Codec, codec.BC.Name())
+		}
+		if info.Base == "" {
+			return ret, nil
+		}
+		if path.Base(info.Base) != info.Base {
+			return ret, errors.IllegalArgumentError.Errorf(
+				"InvalidBaseName(base=%s)", info.Base)
+		}
+		last = info
+		file = path.Join(path.Dir(file), info.Base)
+	}
+}
+
 func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrite bool) (ret error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -102,35 +161,30 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 		}
 	}()
 
-	zr, err := zip.OpenReader(file)
+	zrs, err := openBackups(file)
 	if err != nil {
-		return errors.IllegalArgumentError.Wrapf(err,
-			"ZipOpenFailure(backup=%s)", file)
+		return err
 	}
 	defer func() {
 		if ret != nil {
-			zr.Close()
+			for _, zr := range zrs {
+				zr.Close()
+			}
 		}
 	}()
 
-	info, err := chain.ReadBackupInfo(&zr.Reader)
+	info, err := chain.ReadBackupInfo(&zrs[len(zrs)-1].Reader)
 	if err != nil {
 		return errors.IllegalArgumentError.Wrap(err,
 			"InvalidBackupInfo")
 	}
 
-	if info.Codec != codec.BC.Name() {
-		return errors.IllegalArgumentError.Errorf(
-			"IncompatibleCodec(backup=%s,system=%s)",
-			info.Codec, codec.BC.Name())
-	}
-
 	if err := node.CanAdd(int(info.CID.Value), int(info.NID.Value), info.Channel, overwrite); err != nil {
 		return err
 	}
 
 	go func() {
-		if err := m._restore(node, zr, tmpDir, overwrite); err != nil {
+		if err := m._restore(node, zrs, tmpDir, overwrite); err != nil {
 			node.logger.Debugf("Restore failed err=%+v", err)
 			if errors.InterruptedError.Equals(err) {
 				m._setState(RestoreNone, nil)
@@ -146,18 +200,21 @@ func (m *RestoreManager) Start(node *Node, file string, baseDir string, overwrit
 	m.overwrite = overwrite
 	m.state = RestoreStarted
 	m.current = 0
-	m.total = len(zr.File)
+	m.total = 0
+	for _, zr := range zrs {
+		m.total += len(zr.File)
+	}
 	return nil
 }
 
-func (m *RestoreManager) _onRestored(idx int) error {
+func (m *RestoreManager) _onRestored() error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
 	if m.state != RestoreStarted {
 		return errors.ErrInterrupted
 	}
-	m.current = idx + 1
+	m.current += 1
 	return nil
 }
 
@@ -221,6 +278,10 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 	if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
 		return err
 	}
+	// the file may be extracted from the base backup.
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
 
 	fd, err := os.OpenFile(target,
 		os.O_CREATE|os.O_EXCL|os.O_RDWR|os.O_TRUNC, mode.Perm())
@@ -234,24 +295,92 @@ func zipExtract(file *zip.File, tmpDir string) (ret error) {
 	return err
 }
 
-func (m *RestoreManager) _restore(node *Node, zr *zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
+// applyManifest removes files not listed in the manifest, and checks all
+// listed files are extracted.
+func applyManifest(manifest *chain.BackupManifest, tmpDir string) error {
+	files := make(map[string]int64, len(manifest.Files))
+	for _, f := range manifest.Files {
+		files[f.Name] = f.Size
+	}
+	err := filepath.WalkDir(tmpDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(tmpDir, p)
+		if err != nil {
+			return err
+		}
+		name = filepath.ToSlash(name)
+		size, ok := files[name]
+		if !ok {
+			return os.Remove(p)
+		}
+		delete(files, name)
+		if st, err := d.Info(); err != nil {
+			return err
+		} else if st.Size() != size {
+			return errors.CriticalFormatError.Errorf(
+				"InvalidFileSize(name=%s,exp=%d,real=%d)", name, size, st.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for name := range files {
+		return errors.NotFoundError.Errorf("MissingFile(name=%s)", name)
+	}
+	return nil
+}
+
+func (m *RestoreManager) _restore(node *Node, zrs []*zip.ReadCloser, tmpDir string, overwrite bool) (ret error) {
 	defer func() {
 		if ret != nil {
 			os.RemoveAll(tmpDir)
 		}
 	}()
-	defer zr.Close()
+	defer func() {
+		for _, zr := range zrs {
+			zr.Close()
+		}
+	}()
+
+	if err := extractBackups(zrs, tmpDir, m._onRestored); err != nil {
+		return err
+	}
+	return node.restoreChain(tmpDir, overwrite)
+}
+
+// extractBackups extracts files of the backups in order from the full
+// backup, then applies the manifest of the last one. It calls on for
+// each entry of the backups.
+func extractBackups(zrs []*zip.ReadCloser, tmpDir string, on func() error) error {
+	for _, zr := range zrs {
+		for _, file := range zr.File {
+			if file.Name != chain.BackupManifestFile {
+				if err := zipExtract(file, tmpDir); err != nil {
+					return err
+				}
+			}
+			if err := on(); err != nil {
+				return err
+			}
+		}
+	}
 
-	for idx, file := range zr.File {
-		if err := zipExtract(file, tmpDir); err != nil {
+	if len(zrs) > 1 {
+		manifest, err := chain.ReadBackupManifest(&zrs[len(zrs)-1].Reader)
+		if err != nil {
 			return err
 		}
-		if err := m._onRestored(idx); err != nil {
+		if err := applyManifest(manifest, tmpDir); err != nil {
 			return err
 		}
 	}
-
-	return node.restoreChain(tmpDir, overwrite)
+	return nil
 }
 
 func (m *RestoreManager) Stop() error {
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package node
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// writeBackup writes the backup storing files, and the manifest listing
+// the files of the chain at the backup.
+func writeBackup(t *testing.T, file, base string, height int64, files map[string]string, listed map[string]string) {
+	fd, err := os.Create(file)
+	assert.NoError(t, err)
+	defer fd.Close()
+	zw := zip.NewWriter(fd)
+
+	info, err := json.Marshal(&chain.BackupInfo{
+		NID:     common.HexInt32{Value: 1},
+		CID:     common.HexInt32{Value: 0x1234},
+		Channel: "test",
+		Height:  height,
+		Codec:   codec.BC.Name(),
+		Base:    base,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, zw.SetComment(string(info)))
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	manifest := new(chain.BackupManifest)
+	for name, content := range listed {
+		manifest.Files = append(manifest.Files, chain.BackupFile{
+			Name: name,
+			Size: int64(len(content)),
+		})
+	}
+	bs, err := json.Marshal(manifest)
+	assert.NoError(t, err)
+	w, err := zw.Create(chain.BackupManifestFile)
+	assert.NoError(t, err)
+	_, err = w.Write(bs)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+}
+
+func readFiles(t *testing.T, dir string) map[string]string {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		bs, err := os.ReadFile(p)
+		files[filepath.ToSlash(name)] = string(bs)
+		return err
+	})
+	assert.NoError(t, err)
+	return files
+}
+
+func restoreBackups(file, dir string) error {
+	zrs, err := openBackups(file)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, zr := range zrs {
+			zr.Close()
+		}
+	}()
+	return extractBackups(zrs, dir, func() error { return nil })
+}
+
+func TestRestore_IncrementalBackups(t *testing.T) {
+	dir := t.TempDir()
+	v1 := map[string]string{
+		"db/000001.ldb": "a",
+		"db/000002.ldb": "b",
+		"wal/000.wal":   "w",
+	}
+	writeBackup(t, path.Join(dir, "full.zip"), "", 10, v1, v1)
+
+	// changed db/000002.ldb, added db/000003.ldb and removed wal/000.wal
+	v2 := map[string]string{
+		"db/000001.ldb": "a",
+		"db/000002.ldb": "bb",
+		"db/000003.ldb": "c",
+	}
+	writeBackup(t, path.Join(dir, "incr1.zip"), "full.zip", 20, map[string]string{
+		"db/000002.ldb": "bb",
+		"db/000003.ldb": "c",
+	}, v2)
+
+	// changed db/000001.ldb and removed db/000003.ldb
+	v3 := map[string]string{
+		"db/000001.ldb": "aaa",
+		"db/000002.ldb": "bb",
+	}
+	writeBackup(t, path.Join(dir, "incr2.zip"), "incr1.zip", 30, map[string]string{
+		"db/000001.ldb": "aaa",
+	}, v3)
+
+	for name, exp := range map[string]map[string]string{
+		"full.zip":  v1,
+		"incr1.zip": v2,
+		"incr2.zip": v3,
+	} {
+		target := t.TempDir()
+		assert.NoError(t, restoreBackups(path.Join(dir, name), target), name)
+		assert.Equal(t, exp, readFiles(t, target), name)
+	}
+}
+
+func TestRestore_InvalidBase(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"db/000001.ldb": "a"}
+	writeBackup(t, path.Join(dir, "incr.zip"), "full.zip", 20, files, files)
+
+	// missing base
+	err := restoreBackups(path.Join(dir, "incr.zip"), t.TempDir())
+	assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+
+	// corrupted base
+	assert.NoError(t, os.WriteFile(path.Join(dir, "full.zip"), []byte("corrupted"), 0644))
+	err = restoreBackups(path.Join(dir, "incr.zip"), t.TempDir())
+	assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+
+	// base higher than the backup
+	writeBackup(t, path.Join(dir, "full.zip"), "", 30, files, files)
+	err = restoreBackups(path.Join(dir, "incr.zip"), t.TempDir())
+	assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+
+	// base missing a file listed in the manifest
+	writeBackup(t, path.Join(dir, "full.zip"), "", 10, nil, nil)
+	writeBackup(t, path.Join(dir, "incr.zip"), "full.zip", 20, nil, files)
+	err = restoreBackups(path.Join(dir, "incr.zip"), t.TempDir())
+	assert.True(t, errors.NotFoundError.Equals(err), "err=%+v", err)
+}
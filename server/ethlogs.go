@@ -0,0 +1,284 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	v3 "github.com/icon-project/goloop/server/v3"
+)
+
+// EthLogsParam is the filter object of eth_getLogs. Address may be a string
+// or an array of strings, and each topic may be null, a string or an array
+// of strings.
+type EthLogsParam struct {
+	FromBlock *string           `json:"fromBlock,omitempty"`
+	ToBlock   *string           `json:"toBlock,omitempty"`
+	BlockHash *common.HexBytes  `json:"blockHash,omitempty"`
+	Address   json.RawMessage   `json:"address,omitempty"`
+	Topics    []json.RawMessage `json:"topics,omitempty"`
+}
+
+// stringsOf parses null, a string or an array of strings.
+func stringsOf(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []string{s}, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidValue(value=%s)", raw)
+	}
+	return list, nil
+}
+
+// ethTopicFilter matches topics of ethereum logs. A nil entry matches any
+// topic.
+type ethTopicFilter [][][]byte
+
+func newEthTopicFilter(topics []json.RawMessage) (ethTopicFilter, error) {
+	f := make(ethTopicFilter, len(topics))
+	for i, raw := range topics {
+		list, err := stringsOf(raw)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range list {
+			var topic common.HexBytes
+			if err := topic.UnmarshalJSON([]byte(`"` + s + `"`)); err != nil || len(topic) != 32 {
+				return nil, errors.IllegalArgumentError.Errorf("InvalidTopic(topic=%q)", s)
+			}
+			f[i] = append(f[i], topic)
+		}
+	}
+	return f, nil
+}
+
+func (f ethTopicFilter) Match(log *v3.EthLog) bool {
+	for i, candidates := range f {
+		if len(candidates) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		matched := false
+		for _, t := range candidates {
+			if bytes.Equal(t, log.Topics[i]) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// newEthLogsQuery returns the query for the logs. Ethereum blocks are the
+// blocks including the transactions, and the receipts of them are in the
+// result of the next block.
+func newEthLogsQuery(bm module.BlockManager, param *EthLogsParam, last, base int64) (*logsQuery, error) {
+	var from, to int64
+	if param.BlockHash != nil {
+		if param.FromBlock != nil || param.ToBlock != nil {
+			return nil, errors.IllegalArgumentError.New("BlockHashWithRange")
+		}
+		blk, err := bm.GetBlock(*param.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		from, to = blk.Height(), blk.Height()
+	} else {
+		var tag string
+		var err error
+		if param.FromBlock != nil {
+			tag = *param.FromBlock
+		}
+		if from, err = v3.EthHeightOf(tag, last, base); err != nil {
+			return nil, err
+		}
+		tag = ""
+		if param.ToBlock != nil {
+			tag = *param.ToBlock
+		}
+		if to, err = v3.EthHeightOf(tag, last, base); err != nil {
+			return nil, err
+		}
+	}
+	q := &logsQuery{
+		from:  from + 1,
+		to:    to + 1,
+		limit: ConfigMaxLogsLimit,
+	}
+	if q.to > last {
+		q.to = last
+	}
+	if from < base || from > to || to > last {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d,base=%d,last=%d)", from, to, base, last)
+	}
+	if to-from >= ConfigMaxLogsBlockRange {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", from, to, ConfigMaxLogsBlockRange)
+	}
+
+	ids, err := stringsOf(param.Address)
+	if err != nil {
+		return nil, err
+	}
+	var addresses []module.Address
+	for _, s := range ids {
+		id, err := v3.ParseEthAddress(s)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, common.NewContractAddress(id))
+	}
+	if q.matcher, err = newLogMatcher(nil, addresses); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// ethLogIndexer returns the position of logs in ethereum blocks.
+type ethLogIndexer struct {
+	bm     module.BlockManager
+	sm     module.ServiceManager
+	height int64
+	hash   []byte
+	bases  map[int]int
+	rl     module.ReceiptList
+}
+
+// Fill sets position of the log for the entry. Entries shall be passed in
+// ascending order of the height.
+func (x *ethLogIndexer) Fill(log *v3.EthLog, e *LogEntry) error {
+	if x.hash == nil || x.height != e.Height.Value {
+		blk, err := x.bm.GetBlockByHeight(e.Height.Value - 1)
+		if err != nil {
+			return err
+		}
+		nblk, err := x.bm.GetBlockByHeight(e.Height.Value)
+		if err != nil {
+			return err
+		}
+		rl, err := x.sm.ReceiptListFromResult(nblk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return err
+		}
+		x.height, x.hash, x.rl = e.Height.Value, blk.ID(), rl
+		x.bases = make(map[int]int)
+	}
+	txIndex := int(e.TxIndex.Value)
+	base, ok := x.bases[txIndex]
+	if !ok {
+		var err error
+		if base, err = v3.EthLogIndexBase(x.rl, txIndex); err != nil {
+			return err
+		}
+		x.bases[txIndex] = base
+	}
+	log.BlockNumber = common.HexInt64{Value: e.Height.Value - 1}
+	log.BlockHash = x.hash
+	log.TransactionHash = e.TxHash
+	log.TransactionIndex = e.TxIndex
+	log.LogIndex = common.HexInt32{Value: int32(base) + e.Index.Value}
+	return nil
+}
+
+func ethGetLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	var args []EthLogsParam
+	if err := json.Unmarshal(params.RawMessage(), &args); err != nil || len(args) != 1 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("InvalidParams")
+	}
+	param := &args[0]
+	filter, err := newEthTopicFilter(param.Topics)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	q, err := newEthLogsQuery(bm, param, last.Height(), chain.GenesisStorage().Height())
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	logs := []*v3.EthLog{}
+	if q.from > q.to {
+		return logs, nil
+	}
+	res, err := q.Run(bm, sm)
+	if err == nil {
+		if res.Next != nil {
+			err = errors.IllegalArgumentError.Errorf(
+				"TooManyLogs(max=%d)", ConfigMaxLogsLimit)
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+		}
+		logs, err = ethLogsOf(bm, sm, res.Logs, filter)
+	}
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return logs, nil
+}
+
+// ethLogsOf translates the entries into the ethereum logs matching the
+// filter. Event logs not translatable are skipped.
+func ethLogsOf(bm module.BlockManager, sm module.ServiceManager, entries []*LogEntry, filter ethTopicFilter) ([]*v3.EthLog, error) {
+	logs := []*v3.EthLog{}
+	indexer := &ethLogIndexer{bm: bm, sm: sm}
+	for _, e := range entries {
+		log, err := v3.NewEthLog(e.EventLog)
+		if err != nil || !filter.Match(log) {
+			continue
+		}
+		if err = indexer.Fill(log, e); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
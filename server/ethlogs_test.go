@@ -0,0 +1,142 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common"
+)
+
+type testEthLogPosition struct {
+	blockNumber int64
+	txIndex     int32
+	logIndex    int32
+}
+
+func TestEthGetLogs_Query(t *testing.T) {
+	bm, sm := newTestLogsChain(map[int64][][]*testEventLog{
+		10: {
+			{testTransferLog(testLogsSCORE1, "0x1")},
+			{testApprovalLog(testLogsSCORE2)},
+		},
+		11: {},
+		12: {
+			{
+				testTransferLog(testLogsSCORE1, "0x2"),
+				testApprovalLog(testLogsSCORE1),
+				testTransferLog(testLogsSCORE1, "0x3"),
+			},
+			{testTransferLog(testLogsSCORE2, "0x4")},
+		},
+	})
+	d := sha3.NewLegacyKeccak256()
+	d.Write([]byte("Approval(address,int256)"))
+	approval := common.HexBytes(d.Sum(nil)).String()
+
+	tests := []struct {
+		name   string
+		param  string
+		result []testEthLogPosition
+	}{
+		{
+			"All",
+			`{"fromBlock":"earliest"}`,
+			[]testEthLogPosition{
+				{9, 0, 0}, {9, 1, 1}, {11, 0, 0}, {11, 0, 1}, {11, 0, 2}, {11, 1, 3},
+			},
+		},
+		{
+			"Range",
+			`{"fromBlock":"0xa","toBlock":"0xb"}`,
+			[]testEthLogPosition{{11, 0, 0}, {11, 0, 1}, {11, 0, 2}, {11, 1, 3}},
+		},
+		{
+			"Address",
+			`{"fromBlock":"0x9","address":"0x0000000000000000000000000000000000000002"}`,
+			[]testEthLogPosition{{9, 1, 1}, {11, 1, 3}},
+		},
+		{
+			"Topics",
+			`{"fromBlock":"0x9","topics":[["` + approval + `"]]}`,
+			[]testEthLogPosition{{9, 1, 1}, {11, 0, 1}},
+		},
+		{
+			"TopicsWithAddress",
+			`{"fromBlock":"0x9","topics":["` + approval + `"],"address":["0x0000000000000000000000000000000000000001"]}`,
+			[]testEthLogPosition{{11, 0, 1}},
+		},
+		{
+			"LatestBlock",
+			`{"fromBlock":"latest"}`,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var param EthLogsParam
+			assert.NoError(t, json.Unmarshal([]byte(tt.param), &param))
+			filter, err := newEthTopicFilter(param.Topics)
+			assert.NoError(t, err)
+			q, err := newEthLogsQuery(bm, &param, 12, 9)
+			if !assert.NoError(t, err) {
+				return
+			}
+			var ps []testEthLogPosition
+			if q.from <= q.to {
+				res, err := q.Run(bm, sm)
+				if !assert.NoError(t, err) {
+					return
+				}
+				logs, err := ethLogsOf(bm, sm, res.Logs, filter)
+				assert.NoError(t, err)
+				for _, l := range logs {
+					assert.Equal(t, common.HexBytes(testHeightToBlockID(l.BlockNumber.Value)), l.BlockHash)
+					ps = append(ps, testEthLogPosition{l.BlockNumber.Value, l.TransactionIndex.Value, l.LogIndex.Value})
+				}
+			}
+			assert.Equal(t, tt.result, ps)
+		})
+	}
+}
+
+func TestEthGetLogs_InvalidParam(t *testing.T) {
+	bm, _ := newTestLogsChain(map[int64][][]*testEventLog{10: {{}}})
+	for _, p := range []string{
+		`{"fromBlock":"0xa","toBlock":"0x9"}`,
+		`{"fromBlock":"0x1"}`,
+		`{"fromBlock":"0x9","toBlock":"0xd"}`,
+		`{"fromBlock":"pending1"}`,
+		`{"address":"hx0000000000000000000000000000000000000001"}`,
+		`{"address":[1]}`,
+	} {
+		var param EthLogsParam
+		assert.NoError(t, json.Unmarshal([]byte(p), &param))
+		_, err := newEthLogsQuery(bm, &param, 12, 9)
+		assert.Error(t, err, p)
+	}
+	for _, topics := range []string{`[1]`, `["0x01"]`, `[["0xzz"]]`} {
+		var param []json.RawMessage
+		assert.NoError(t, json.Unmarshal([]byte(topics), &param))
+		_, err := newEthTopicFilter(param)
+		assert.Error(t, err, topics)
+	}
+}
@@ -0,0 +1,337 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+var (
+	// ConfigMaxLogsBlockRange limits the number of blocks searched by
+	// a request of icx_getLogs.
+	ConfigMaxLogsBlockRange int64 = 5000
+
+	// ConfigMaxLogsLimit limits the number of logs returned by a request
+	// of icx_getLogs. It's also used if limit is not specified.
+	ConfigMaxLogsLimit = 1000
+)
+
+type LogsParam struct {
+	FromHeight   jsonrpc.HexInt    `json:"fromHeight" validate:"required,t_int"`
+	ToHeight     jsonrpc.HexInt    `json:"toHeight,omitempty" validate:"optional,t_int"`
+	Addresses    []jsonrpc.Address `json:"addresses,omitempty" validate:"omitempty,dive,t_addr_score"`
+	EventFilters EventFilters      `json:"eventFilters,omitempty"`
+	Limit        jsonrpc.HexInt    `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor       *LogCursor        `json:"cursor,omitempty"`
+}
+
+// LogCursor is the position of an event log. It's returned as the position
+// of the next log when there are more logs than the limit. It can be passed
+// with the same request for the next page.
+type LogCursor struct {
+	Height  common.HexInt64 `json:"height"`
+	TxIndex common.HexInt32 `json:"txIndex"`
+	Index   common.HexInt32 `json:"index"`
+}
+
+type LogEntry struct {
+	Height    common.HexInt64 `json:"height"`
+	BlockHash common.HexBytes `json:"blockHash"`
+	TxIndex   common.HexInt32 `json:"txIndex"`
+	TxHash    common.HexBytes `json:"txHash"`
+	Index     common.HexInt32 `json:"index"`
+	EventLog  module.EventLog `json:"eventLog"`
+}
+
+type LogsResult struct {
+	Logs []*LogEntry `json:"logs"`
+	Next *LogCursor  `json:"next,omitempty"`
+}
+
+// logMatcher matches event logs with event filters or addresses. It matches
+// all logs if both are empty.
+type logMatcher struct {
+	filters   EventFilters
+	addresses []module.Address
+	addrLBs   []module.LogsBloom
+}
+
+func newLogMatcher(filters EventFilters, addresses []module.Address) (*logMatcher, error) {
+	m := new(logMatcher)
+	if len(filters) > 0 {
+		// addresses are applied to the filters without the address.
+		for idx, f := range filters {
+			if f == nil {
+				return nil, errors.IllegalArgumentError.Errorf("InvalidFilter(idx=%d)", idx)
+			}
+			if f.Addr != nil || len(addresses) == 0 {
+				m.filters = append(m.filters, f)
+				continue
+			}
+			for _, addr := range addresses {
+				f2 := &EventFilter{
+					Addr:      common.AddressToPtr(addr),
+					Signature: f.Signature,
+					Indexed:   f.Indexed,
+					Data:      f.Data,
+				}
+				m.filters = append(m.filters, f2)
+			}
+		}
+		for _, f := range m.filters {
+			if err := f.Compile(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	for _, addr := range addresses {
+		lb := txresult.NewLogsBloom(nil)
+		lb.AddAddressOfLog(addr)
+		m.addresses = append(m.addresses, addr)
+		m.addrLBs = append(m.addrLBs, lb)
+	}
+	return m, nil
+}
+
+// FilteredByLogBloom returns the matcher for the logs in the logs bloom.
+// It returns false if there is no log to match.
+func (m *logMatcher) FilteredByLogBloom(lb module.LogsBloom) (*logMatcher, bool) {
+	if len(m.filters) > 0 {
+		filters, contained := m.filters.FilteredByLogBloom(lb)
+		return &logMatcher{filters: filters}, contained
+	}
+	if len(m.addresses) == 0 {
+		return m, true
+	}
+	m2 := new(logMatcher)
+	for idx, addrLB := range m.addrLBs {
+		if lb.Contain(addrLB) {
+			m2.addresses = append(m2.addresses, m.addresses[idx])
+			m2.addrLBs = append(m2.addrLBs, addrLB)
+		}
+	}
+	return m2, len(m2.addresses) > 0
+}
+
+func (m *logMatcher) MatchLog(el module.EventLog) bool {
+	if len(m.filters) > 0 {
+		for _, f := range m.filters {
+			if f != nil && f.MatchLog(el) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(m.addresses) == 0 {
+		return true
+	}
+	for _, addr := range m.addresses {
+		if el.Address().Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+type logsQuery struct {
+	from, to int64
+	cursor   *LogCursor
+	limit    int
+	matcher  *logMatcher
+}
+
+func (q *logsQuery) isBeforeCursor(height int64, txIndex, index int) bool {
+	if q.cursor == nil || height != q.cursor.Height.Value {
+		return false
+	}
+	if txIndex != int(q.cursor.TxIndex.Value) {
+		return txIndex < int(q.cursor.TxIndex.Value)
+	}
+	return index < int(q.cursor.Index.Value)
+}
+
+func (q *logsQuery) Run(bm module.BlockManager, sm module.ServiceManager) (*LogsResult, error) {
+	res := &LogsResult{Logs: []*LogEntry{}}
+	from := q.from
+	if q.cursor != nil {
+		from = q.cursor.Height.Value
+	}
+	for height := from; height <= q.to; height++ {
+		blk, err := bm.GetBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		bMatcher, ok := q.matcher.FilteredByLogBloom(blk.LogsBloom())
+		if !ok {
+			continue
+		}
+		rl, err := sm.ReceiptListFromResult(blk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, err
+		}
+		// receipts in the result are of the transactions in the
+		// previous block.
+		var txs module.TransactionList
+		txIndex := 0
+		for rit := rl.Iterator(); rit.Has(); _, txIndex = rit.Next(), txIndex+1 {
+			if q.isBeforeCursor(height, txIndex, 0) {
+				continue
+			}
+			r, err := rit.Get()
+			if err != nil {
+				return nil, err
+			}
+			rMatcher, ok := bMatcher.FilteredByLogBloom(r.LogsBloom())
+			if !ok {
+				continue
+			}
+			for it, idx := r.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+				if q.isBeforeCursor(height, txIndex, idx) {
+					continue
+				}
+				el, err := it.Get()
+				if err != nil {
+					return nil, err
+				}
+				if !rMatcher.MatchLog(el) {
+					continue
+				}
+				if len(res.Logs) >= q.limit {
+					res.Next = &LogCursor{
+						Height:  common.HexInt64{Value: height},
+						TxIndex: common.HexInt32{Value: int32(txIndex)},
+						Index:   common.HexInt32{Value: int32(idx)},
+					}
+					return res, nil
+				}
+				if txs == nil {
+					pblk, err := bm.GetBlockByHeight(height - 1)
+					if err != nil {
+						return nil, err
+					}
+					txs = pblk.NormalTransactions()
+				}
+				tx, err := txs.Get(txIndex)
+				if err != nil {
+					return nil, err
+				}
+				res.Logs = append(res.Logs, &LogEntry{
+					Height:    common.HexInt64{Value: height},
+					BlockHash: blk.ID(),
+					TxIndex:   common.HexInt32{Value: int32(txIndex)},
+					TxHash:    tx.ID(),
+					Index:     common.HexInt32{Value: int32(idx)},
+					EventLog:  el,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+func newLogsQuery(param *LogsParam, last, base int64) (*logsQuery, error) {
+	q := new(logsQuery)
+	var err error
+	if q.from, err = param.FromHeight.Int64(); err != nil {
+		return nil, err
+	}
+	q.to = last
+	if param.ToHeight != "" {
+		if q.to, err = param.ToHeight.Int64(); err != nil {
+			return nil, err
+		}
+	}
+	if q.from < base || q.from > q.to || q.to > last {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d,base=%d,last=%d)", q.from, q.to, base, last)
+	}
+	if q.to-q.from >= ConfigMaxLogsBlockRange {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", q.from, q.to, ConfigMaxLogsBlockRange)
+	}
+
+	q.limit = ConfigMaxLogsLimit
+	if param.Limit != "" {
+		if limit, err := param.Limit.ParseInt(32); err != nil {
+			return nil, err
+		} else if limit <= 0 || limit > int64(ConfigMaxLogsLimit) {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidLimit(limit=%d,max=%d)", limit, ConfigMaxLogsLimit)
+		} else {
+			q.limit = int(limit)
+		}
+	}
+
+	if c := param.Cursor; c != nil {
+		if c.Height.Value < q.from || c.Height.Value > q.to ||
+			c.TxIndex.Value < 0 || c.Index.Value < 0 {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"InvalidCursor(height=%d,txIndex=%d,index=%d)",
+				c.Height.Value, c.TxIndex.Value, c.Index.Value)
+		}
+		q.cursor = c
+	}
+
+	addresses := make([]module.Address, len(param.Addresses))
+	for i, addr := range param.Addresses {
+		addresses[i] = addr.Address()
+	}
+	if q.matcher, err = newLogMatcher(param.EventFilters, addresses); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func getLogs(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	debug := ctx.IncludeDebug()
+	chain, err := ctx.Chain()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, debug)
+	}
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, jsonrpc.ErrorCodeServer.New("Stopped")
+	}
+
+	var param LogsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+
+	last, err := bm.GetLastBlock()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	q, err := newLogsQuery(&param, last.Height(), chain.GenesisStorage().Height())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, debug)
+	}
+	res, err := q.Run(bm, sm)
+	if err != nil {
+		if errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, debug)
+	}
+	return res, nil
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+type testTransaction struct {
+	module.Transaction
+	id []byte
+}
+
+func (tx *testTransaction) ID() []byte {
+	return tx.id
+}
+
+type testTransactionList struct {
+	module.TransactionList
+	height int64
+	size   int
+}
+
+func (l *testTransactionList) Get(i int) (module.Transaction, error) {
+	if i < 0 || i >= l.size {
+		return nil, errors.ErrNotFound
+	}
+	return &testTransaction{id: []byte(fmt.Sprintf("tx%d-%d", l.height, i))}, nil
+}
+
+type testLogsBlock struct {
+	testBlock
+	txs *testTransactionList
+}
+
+func (b *testLogsBlock) NormalTransactions() module.TransactionList {
+	return b.txs
+}
+
+type testLogsBlockManager struct {
+	module.BlockManager
+	blocks map[int64]module.Block
+}
+
+func (bm *testLogsBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if blk, ok := bm.blocks[height]; ok {
+		return blk, nil
+	}
+	return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+}
+
+const (
+	testLogsSCORE1   = "cx0000000000000000000000000000000000000001"
+	testLogsSCORE2   = "cx0000000000000000000000000000000000000002"
+	testLogsTransfer = "Transfer(Address,Address,int)"
+	testLogsApproval = "Approval(Address,int)"
+)
+
+func newTestLogsChain(blocks map[int64][][]*testEventLog) (module.BlockManager, module.ServiceManager) {
+	bm := &testLogsBlockManager{blocks: make(map[int64]module.Block)}
+	receipts := make(blockReceipts)
+	for height, txs := range blocks {
+		result := fmt.Sprintf("result%d", height)
+		var rl testReceiptList
+		for _, events := range txs {
+			rl = append(rl, newTestReceipt(events))
+		}
+		receipts[result] = rl
+		bm.blocks[height] = &testLogsBlock{
+			testBlock: testBlock{
+				height: height,
+				result: result,
+				lb:     rl.LogsBloom(),
+			},
+		}
+	}
+	// results of the blocks are of the transactions in the previous blocks.
+	for height, txs := range blocks {
+		txl := &testTransactionList{height: height - 1, size: len(txs)}
+		if blk, ok := bm.blocks[height-1]; ok {
+			blk.(*testLogsBlock).txs = txl
+		} else {
+			bm.blocks[height-1] = &testLogsBlock{
+				testBlock: testBlock{height: height - 1},
+				txs:       txl,
+			}
+		}
+	}
+	return bm, &testServiceManager{receipts: receipts}
+}
+
+func testTransferLog(score string, value string) *testEventLog {
+	return newTestEventLog(score, testLogsTransfer, [][]string{
+		{"Address", "hx0000000000000000000000000000000000000001"},
+		{"Address", "hx0000000000000000000000000000000000000002"},
+	}, [][]string{
+		{"int", value},
+	})
+}
+
+func testApprovalLog(score string) *testEventLog {
+	return newTestEventLog(score, testLogsApproval, [][]string{
+		{"Address", "hx0000000000000000000000000000000000000001"},
+	}, [][]string{
+		{"int", "0x1"},
+	})
+}
+
+type testLogPosition struct {
+	height  int64
+	txIndex int32
+	index   int32
+}
+
+func positionsOf(res *LogsResult) []testLogPosition {
+	var ps []testLogPosition
+	for _, l := range res.Logs {
+		if string(l.TxHash) != fmt.Sprintf("tx%d-%d", l.Height.Value-1, l.TxIndex.Value) {
+			return nil
+		}
+		ps = append(ps, testLogPosition{l.Height.Value, l.TxIndex.Value, l.Index.Value})
+	}
+	return ps
+}
+
+func TestGetLogs_Query(t *testing.T) {
+	bm, sm := newTestLogsChain(map[int64][][]*testEventLog{
+		10: {
+			{testTransferLog(testLogsSCORE1, "0x1")},
+			{testApprovalLog(testLogsSCORE2)},
+		},
+		11: {
+			{},
+		},
+		12: {
+			{
+				testTransferLog(testLogsSCORE1, "0x2"),
+				testApprovalLog(testLogsSCORE1),
+				testTransferLog(testLogsSCORE1, "0x3"),
+			},
+			{testTransferLog(testLogsSCORE2, "0x4")},
+		},
+		13: {
+			{testApprovalLog(testLogsSCORE2)},
+			{testTransferLog(testLogsSCORE1, "0x5")},
+		},
+	})
+	value := "0x3"
+	tests := []struct {
+		name   string
+		param  LogsParam
+		result []testLogPosition
+	}{
+		{
+			"All",
+			LogsParam{FromHeight: "0xa"},
+			[]testLogPosition{
+				{10, 0, 0}, {10, 1, 0}, {12, 0, 0}, {12, 0, 1},
+				{12, 0, 2}, {12, 1, 0}, {13, 0, 0}, {13, 1, 0},
+			},
+		},
+		{
+			"Addresses",
+			LogsParam{
+				FromHeight: "0xa",
+				Addresses:  []jsonrpc.Address{testLogsSCORE2},
+			},
+			[]testLogPosition{{10, 1, 0}, {12, 1, 0}, {13, 0, 0}},
+		},
+		{
+			"FilterWithAddresses",
+			LogsParam{
+				FromHeight: "0xa",
+				Addresses:  []jsonrpc.Address{testLogsSCORE1},
+				EventFilters: EventFilters{
+					{Signature: testLogsTransfer},
+				},
+			},
+			[]testLogPosition{{10, 0, 0}, {12, 0, 0}, {12, 0, 2}, {13, 1, 0}},
+		},
+		{
+			"FilterWithData",
+			LogsParam{
+				FromHeight: "0xb",
+				ToHeight:   "0xc",
+				EventFilters: EventFilters{
+					{Signature: testLogsTransfer, Indexed: []*string{nil, nil}, Data: []*string{&value}},
+					{Signature: testLogsApproval},
+				},
+			},
+			[]testLogPosition{{12, 0, 1}, {12, 0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := newLogsQuery(&tt.param, 13, 10)
+			assert.NoError(t, err)
+			res, err := q.Run(bm, sm)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.result, positionsOf(res))
+			assert.Nil(t, res.Next)
+		})
+	}
+}
+
+func TestGetLogs_Pagination(t *testing.T) {
+	bm, sm := newTestLogsChain(map[int64][][]*testEventLog{
+		1: {
+			{testTransferLog(testLogsSCORE1, "0x1"), testTransferLog(testLogsSCORE1, "0x2")},
+			{testTransferLog(testLogsSCORE1, "0x3")},
+		},
+		2: {
+			{testTransferLog(testLogsSCORE1, "0x4")},
+		},
+	})
+	param := LogsParam{
+		FromHeight: "0x1",
+		ToHeight:   "0x2",
+		Limit:      "0x2",
+	}
+	var all []testLogPosition
+	for i := 0; ; i++ {
+		q, err := newLogsQuery(&param, 2, 0)
+		assert.NoError(t, err)
+		res, err := q.Run(bm, sm)
+		assert.NoError(t, err)
+		all = append(all, positionsOf(res)...)
+		if res.Next == nil {
+			assert.Equal(t, 1, i)
+			break
+		}
+		assert.Len(t, res.Logs, 2)
+		param.Cursor = res.Next
+	}
+	assert.Equal(t, []testLogPosition{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}, {2, 0, 0}}, all)
+}
+
+func TestGetLogs_InvalidParam(t *testing.T) {
+	cursor := &LogCursor{Height: common.HexInt64{Value: 3}}
+	tests := []struct {
+		name  string
+		param LogsParam
+	}{
+		{"BelowBase", LogsParam{FromHeight: "0x9"}},
+		{"AboveLast", LogsParam{FromHeight: "0xa", ToHeight: "0x100000"}},
+		{"Reversed", LogsParam{FromHeight: "0xc", ToHeight: "0xb"}},
+		{"TooLarge", LogsParam{FromHeight: "0xa", ToHeight: jsonrpc.HexInt(fmt.Sprintf("%#x", 10+ConfigMaxLogsBlockRange))}},
+		{"ZeroLimit", LogsParam{FromHeight: "0xa", Limit: "0x0"}},
+		{"TooLargeLimit", LogsParam{FromHeight: "0xa", Limit: jsonrpc.HexInt(fmt.Sprintf("%#x", ConfigMaxLogsLimit+1))}},
+		{"InvalidCursor", LogsParam{FromHeight: "0xa", Cursor: cursor}},
+		{"InvalidFilter", LogsParam{FromHeight: "0xa", EventFilters: EventFilters{{Signature: "Transfer"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newLogsQuery(&tt.param, ConfigMaxLogsBlockRange*2, 10)
+			assert.True(t, errors.IllegalArgumentError.Equals(err), "err=%+v", err)
+		})
+	}
+}
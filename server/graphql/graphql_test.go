@@ -0,0 +1,309 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+type testTransactionIterator struct{}
+
+func (testTransactionIterator) Has() bool {
+	return false
+}
+
+func (testTransactionIterator) Next() error {
+	return errors.ErrNotFound
+}
+
+func (testTransactionIterator) Get() (module.Transaction, int, error) {
+	return nil, 0, errors.ErrNotFound
+}
+
+type testTransactionList struct {
+	module.TransactionList
+}
+
+func (testTransactionList) Iterator() module.TransactionIterator {
+	return testTransactionIterator{}
+}
+
+type testBlock struct {
+	module.Block
+	height int64
+}
+
+func testBlockID(height int64) []byte {
+	id := make([]byte, 32)
+	id[31] = byte(height)
+	return id
+}
+
+func (b *testBlock) Version() int {
+	return module.BlockVersion2
+}
+
+func (b *testBlock) Height() int64 {
+	return b.height
+}
+
+func (b *testBlock) ID() []byte {
+	return testBlockID(b.height)
+}
+
+func (b *testBlock) PrevID() []byte {
+	if b.height == 0 {
+		return nil
+	}
+	return testBlockID(b.height - 1)
+}
+
+func (b *testBlock) Timestamp() int64 {
+	return b.height * 1000
+}
+
+func (b *testBlock) Proposer() module.Address {
+	return nil
+}
+
+func (b *testBlock) Result() []byte {
+	return []byte(fmt.Sprintf("result%d", b.height))
+}
+
+func (b *testBlock) NormalTransactions() module.TransactionList {
+	return testTransactionList{}
+}
+
+type testBlockManager struct {
+	module.BlockManager
+	last int64
+}
+
+func (bm *testBlockManager) GetBlockByHeight(height int64) (module.Block, error) {
+	if height < 0 || height > bm.last {
+		return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", height)
+	}
+	return &testBlock{height: height}, nil
+}
+
+func (bm *testBlockManager) GetBlock(id []byte) (module.Block, error) {
+	for h := int64(0); h <= bm.last; h++ {
+		if string(testBlockID(h)) == string(id) {
+			return &testBlock{height: h}, nil
+		}
+	}
+	return nil, errors.NotFoundError.New("NoBlock")
+}
+
+func (bm *testBlockManager) GetLastBlock() (module.Block, error) {
+	return bm.GetBlockByHeight(bm.last)
+}
+
+type testServiceManager struct {
+	module.ServiceManager
+}
+
+func (sm *testServiceManager) GetBalance(result []byte, addr module.Address) (*big.Int, error) {
+	if string(result) == "result0" {
+		return big.NewInt(0), nil
+	}
+	return big.NewInt(100), nil
+}
+
+func (sm *testServiceManager) GetSCOREStatus(result []byte, addr module.Address) (module.SCOREStatus, error) {
+	return nil, errors.NotFoundError.New("NoContract")
+}
+
+func (sm *testServiceManager) GetAPIInfo(result []byte, addr module.Address) (module.APIInfo, error) {
+	return nil, errors.NotFoundError.New("NoContract")
+}
+
+type testGenesisStorage struct {
+	module.GenesisStorage
+}
+
+func (testGenesisStorage) Height() int64 {
+	return 0
+}
+
+type testChain struct {
+	module.Chain
+	bm module.BlockManager
+	sm module.ServiceManager
+}
+
+func (c *testChain) BlockManager() module.BlockManager {
+	return c.bm
+}
+
+func (c *testChain) ServiceManager() module.ServiceManager {
+	return c.sm
+}
+
+func (c *testChain) GenesisStorage() module.GenesisStorage {
+	return testGenesisStorage{}
+}
+
+func newTestChain(last int64) module.Chain {
+	return &testChain{
+		bm: &testBlockManager{last: last},
+		sm: &testServiceManager{},
+	}
+}
+
+func execQuery(t *testing.T, chain module.Chain, query string) (map[string]interface{}, []string) {
+	h := NewHandler()
+	ctx, err := NewContext(context.Background(), chain)
+	assert.NoError(t, err)
+	res := h.schema.Exec(ctx, query, "", nil)
+	var data map[string]interface{}
+	if len(res.Data) > 0 {
+		assert.NoError(t, json.Unmarshal(res.Data, &data))
+	}
+	var errs []string
+	for _, e := range res.Errors {
+		errs = append(errs, e.Message)
+	}
+	return data, errs
+}
+
+func TestQuery_Block(t *testing.T) {
+	chain := newTestChain(5)
+
+	data, errs := execQuery(t, chain, `{
+		block(height: "0x2") {
+			height
+			hash
+			parent { height }
+			next { height next { height } }
+			transactionCount
+		}
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"block": map[string]interface{}{
+			"height": "0x2",
+			"hash":   common.HexBytes(testBlockID(2)).String(),
+			"parent": map[string]interface{}{"height": "0x1"},
+			"next": map[string]interface{}{
+				"height": "0x3",
+				"next":   map[string]interface{}{"height": "0x4"},
+			},
+			"transactionCount": float64(0),
+		},
+	}, data)
+
+	data, errs = execQuery(t, chain, fmt.Sprintf(`{
+		block(hash: "%s") { height }
+	}`, common.HexBytes(testBlockID(3)).String()))
+	assert.Empty(t, errs)
+	assert.Equal(t, "0x3", data["block"].(map[string]interface{})["height"])
+
+	data, errs = execQuery(t, chain, `{ block { height next { height } } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"block": map[string]interface{}{"height": "0x5", "next": nil},
+	}, data)
+
+	data, errs = execQuery(t, chain, `{ block(height: "0x10") { height } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"block": nil}, data)
+}
+
+func TestQuery_Blocks(t *testing.T) {
+	chain := newTestChain(5)
+
+	data, errs := execQuery(t, chain, `{ blocks(from: "0x1", to: "0x3") { height } }`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"blocks": []interface{}{
+			map[string]interface{}{"height": "0x1"},
+			map[string]interface{}{"height": "0x2"},
+			map[string]interface{}{"height": "0x3"},
+		},
+	}, data)
+
+	for _, query := range []string{
+		`{ blocks(from: "0x3", to: "0x1") { height } }`,
+		`{ blocks(from: "0x1", to: "0x6") { height } }`,
+		`{ blocks(from: "abc") { height } }`,
+	} {
+		_, errs = execQuery(t, chain, query)
+		assert.NotEmpty(t, errs, query)
+	}
+
+	old := ConfigMaxBlocks
+	ConfigMaxBlocks = 2
+	defer func() { ConfigMaxBlocks = old }()
+	_, errs = execQuery(t, chain, `{ blocks(from: "0x1", to: "0x3") { height } }`)
+	assert.NotEmpty(t, errs)
+}
+
+func TestQuery_Account(t *testing.T) {
+	chain := newTestChain(5)
+
+	data, errs := execQuery(t, chain, `{
+		block(height: "0x0") { account(address: "hx0000000000000000000000000000000000000001") { balance } }
+		account(address: "hx0000000000000000000000000000000000000001") {
+			address isContract balance scoreStatus
+		}
+	}`)
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"block": map[string]interface{}{
+			"account": map[string]interface{}{"balance": "0x0"},
+		},
+		"account": map[string]interface{}{
+			"address":     "hx0000000000000000000000000000000000000001",
+			"isContract":  false,
+			"balance":     "0x64",
+			"scoreStatus": nil,
+		},
+	}, data)
+
+	_, errs = execQuery(t, chain, `{ account(address: "invalid") { balance } }`)
+	assert.NotEmpty(t, errs)
+}
+
+func TestQuery_Limits(t *testing.T) {
+	chain := newTestChain(50)
+
+	// too deep
+	_, errs := execQuery(t, chain, `{ block(height: "0x0") {
+		next { next { next { next { next { next { next { next { next { next { height } } } } } } } } } }
+	} }`)
+	assert.NotEmpty(t, errs)
+
+	// too many nodes
+	old := ConfigMaxNodes
+	ConfigMaxNodes = 10
+	defer func() { ConfigMaxNodes = old }()
+	_, errs = execQuery(t, chain, `{ blocks(from: "0x0", to: "0x9") { height } }`)
+	assert.Empty(t, errs)
+	_, errs = execQuery(t, chain, `{ blocks(from: "0x0", to: "0x9") { height next { height } } }`)
+	assert.NotEmpty(t, errs)
+}
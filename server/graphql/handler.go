@@ -0,0 +1,92 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"net/http"
+
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+var (
+	// ConfigMaxQueryLength limits the length of the query string.
+	ConfigMaxQueryLength = 16 * 1024
+
+	// ConfigMaxParallelism limits the number of resolvers running in
+	// parallel for a query.
+	ConfigMaxParallelism = 10
+)
+
+// MethodName is the name used for charging rate limiter for a query.
+const MethodName = "graphql"
+
+// Request is a GraphQL request over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Handler handles GraphQL requests for the chain set in the context.
+type Handler struct {
+	schema *gql.Schema
+}
+
+// NewHandler returns the handler with the schema of the chain data.
+func NewHandler() *Handler {
+	schema := gql.MustParseSchema(schemaString, &queryResolver{},
+		gql.MaxDepth(ConfigMaxDepth),
+		gql.MaxParallelism(ConfigMaxParallelism),
+	)
+	return &Handler{schema: schema}
+}
+
+func errorResponse(c echo.Context, status int, msg string) error {
+	return c.JSON(status, &gql.Response{
+		Errors: []*gqlerrors.QueryError{{Message: msg}},
+	})
+}
+
+// Handle executes the query in the request on the chain in the context.
+func (h *Handler) Handle(c echo.Context) error {
+	chain, ok := c.Get("chain").(module.Chain)
+	if !ok || chain == nil {
+		return errorResponse(c, http.StatusInternalServerError, "no chain")
+	}
+	var req Request
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "invalid request")
+	}
+	if len(req.Query) == 0 || len(req.Query) > ConfigMaxQueryLength {
+		return errorResponse(c, http.StatusBadRequest, "invalid query length")
+	}
+	if limiter, ok := c.Get("rateLimiter").(jsonrpc.RateLimiter); ok {
+		if err := limiter.Charge(MethodName); err != nil {
+			return errorResponse(c, http.StatusTooManyRequests, err.Error())
+		}
+	}
+	ctx, err := NewContext(c.Request().Context(), chain)
+	if err != nil {
+		return errorResponse(c, http.StatusServiceUnavailable, err.Error())
+	}
+	return c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
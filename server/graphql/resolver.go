@@ -0,0 +1,404 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+var (
+	// ConfigMaxDepth limits the depth of queries.
+	ConfigMaxDepth = 10
+
+	// ConfigMaxNodes limits the number of objects (blocks, transactions,
+	// receipts, event logs, accounts and BTP networks) resolved by a query.
+	ConfigMaxNodes int64 = 1000
+
+	// ConfigMaxBlocks limits the number of blocks returned by blocks.
+	ConfigMaxBlocks int64 = 100
+)
+
+// env is the environment of a query.
+type env struct {
+	chain module.Chain
+	bm    module.BlockManager
+	sm    module.ServiceManager
+	nodes int64
+}
+
+// charge consumes n nodes of the query. It fails if the query resolves
+// too many nodes.
+func (e *env) charge(n int64) error {
+	if atomic.AddInt64(&e.nodes, n) > ConfigMaxNodes {
+		return errors.IllegalArgumentError.Errorf(
+			"TooComplexQuery(maxNodes=%d)", ConfigMaxNodes)
+	}
+	return nil
+}
+
+func (e *env) blockByHeight(height int64) (*blockResolver, error) {
+	if height < e.chain.GenesisStorage().Height() {
+		return nil, nil
+	}
+	blk, err := e.bm.GetBlockByHeight(height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return e.newBlock(blk)
+}
+
+func (e *env) blockByID(id []byte) (*blockResolver, error) {
+	blk, err := e.bm.GetBlock(id)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if blk.Height() < e.chain.GenesisStorage().Height() {
+		return nil, nil
+	}
+	return e.newBlock(blk)
+}
+
+// blockOf returns the block of the height. It returns the last block if
+// height is nil.
+func (e *env) blockOf(height *string) (module.Block, error) {
+	if height == nil {
+		return e.bm.GetLastBlock()
+	}
+	h, err := parseHeight(*height)
+	if err != nil {
+		return nil, err
+	}
+	return e.bm.GetBlockByHeight(h)
+}
+
+type envKey struct{}
+
+// NewContext returns the context for the query on the chain.
+func NewContext(ctx context.Context, chain module.Chain) (context.Context, error) {
+	bm := chain.BlockManager()
+	sm := chain.ServiceManager()
+	if bm == nil || sm == nil {
+		return nil, errors.InvalidStateError.New("Stopped")
+	}
+	return context.WithValue(ctx, envKey{}, &env{chain: chain, bm: bm, sm: sm}), nil
+}
+
+func envOf(ctx context.Context) (*env, error) {
+	if e, ok := ctx.Value(envKey{}).(*env); ok {
+		return e, nil
+	}
+	return nil, errors.InvalidStateError.New("NoChain")
+}
+
+func parseHeight(s string) (int64, error) {
+	h, err := intconv.ParseInt(s, 64)
+	if err != nil || h < 0 {
+		return 0, errors.IllegalArgumentError.Errorf("InvalidHeight(height=%q)", s)
+	}
+	return h, nil
+}
+
+func parseHash(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidHash(hash=%q)", s)
+	}
+	bs, err := hex.DecodeString(s[2:])
+	if err != nil || len(bs) != 32 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidHash(hash=%q)", s)
+	}
+	return bs, nil
+}
+
+func parseAddress(s string) (module.Address, error) {
+	addr, err := common.NewAddressFromString(s)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(address=%q)", s)
+	}
+	return addr, nil
+}
+
+func hexOf(bs []byte) *string {
+	if bs == nil {
+		return nil
+	}
+	s := common.HexBytes(bs).String()
+	return &s
+}
+
+func addressOf(addr module.Address) *string {
+	if addr == nil {
+		return nil
+	}
+	s := addr.String()
+	return &s
+}
+
+// JSON is the value in JSON format.
+type JSON struct {
+	Value interface{}
+}
+
+func (JSON) ImplementsGraphQLType(name string) bool {
+	return name == "JSON"
+}
+
+func (j *JSON) UnmarshalGraphQL(input interface{}) error {
+	j.Value = input
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Value)
+}
+
+// jsonFieldsOf returns fields of the object in JSON. Fields are normalized
+// into the values of JSON.
+func jsonFieldsOf(obj interface {
+	ToJSON(version module.JSONVersion) (interface{}, error)
+}) (map[string]interface{}, error) {
+	jso, err := obj.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(jso)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(bs, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func stringField(fields map[string]interface{}, key string) *string {
+	if s, ok := fields[key].(string); ok {
+		return &s
+	}
+	return nil
+}
+
+func stringFieldOrEmpty(fields map[string]interface{}, key string) string {
+	if s, ok := fields[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+func jsonField(fields map[string]interface{}, key string) *JSON {
+	if v, ok := fields[key]; ok && v != nil {
+		return &JSON{Value: v}
+	}
+	return nil
+}
+
+type queryResolver struct{}
+
+type blockArgs struct {
+	Height *string
+	Hash   *string
+}
+
+func (q *queryResolver) Block(ctx context.Context, args blockArgs) (*blockResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if args.Hash != nil {
+		if args.Height != nil {
+			return nil, errors.IllegalArgumentError.New("BothHeightAndHash")
+		}
+		id, err := parseHash(*args.Hash)
+		if err != nil {
+			return nil, err
+		}
+		return e.blockByID(id)
+	}
+	if args.Height != nil {
+		h, err := parseHeight(*args.Height)
+		if err != nil {
+			return nil, err
+		}
+		return e.blockByHeight(h)
+	}
+	blk, err := e.bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	return e.newBlock(blk)
+}
+
+type blocksArgs struct {
+	From string
+	To   *string
+}
+
+func (q *queryResolver) Blocks(ctx context.Context, args blocksArgs) ([]*blockResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	last, err := e.bm.GetLastBlock()
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseHeight(args.From)
+	if err != nil {
+		return nil, err
+	}
+	to := last.Height()
+	if args.To != nil {
+		if to, err = parseHeight(*args.To); err != nil {
+			return nil, err
+		}
+	}
+	base := e.chain.GenesisStorage().Height()
+	if from < base || from > to || to > last.Height() {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(from=%d,to=%d,base=%d,last=%d)", from, to, base, last.Height())
+	}
+	if to-from >= ConfigMaxBlocks {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)", from, to, ConfigMaxBlocks)
+	}
+	blocks := make([]*blockResolver, 0, to-from+1)
+	for h := from; h <= to; h++ {
+		b, err := e.blockByHeight(h)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			return nil, errors.NotFoundError.Errorf("NoBlock(height=%d)", h)
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, nil
+}
+
+type transactionArgs struct {
+	Hash string
+}
+
+func (q *queryResolver) Transaction(ctx context.Context, args transactionArgs) (*transactionResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseHash(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := e.bm.GetTransactionInfo(id)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if txInfo.Group() != module.TransactionGroupNormal {
+		return nil, nil
+	}
+	blk := txInfo.Block()
+	if blk.Height() < e.chain.GenesisStorage().Height() {
+		return nil, nil
+	}
+	b, err := e.newBlock(blk)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txInfo.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	return b.newTransaction(tx, txInfo.Index())
+}
+
+type accountArgs struct {
+	Address string
+	Height  *string
+}
+
+func (q *queryResolver) Account(ctx context.Context, args accountArgs) (*accountResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := parseAddress(args.Address)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := e.blockOf(args.Height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return e.newAccount(blk, addr)
+}
+
+type btpArgs struct {
+	ID     string
+	Height *string
+}
+
+func (q *queryResolver) BtpNetworkType(ctx context.Context, args btpArgs) (*btpNetworkTypeResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := intconv.ParseInt(args.ID, 64)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidID(id=%q)", args.ID)
+	}
+	blk, err := e.blockOf(args.Height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return e.newBTPNetworkType(blk, id)
+}
+
+func (q *queryResolver) BtpNetwork(ctx context.Context, args btpArgs) (*btpNetworkResolver, error) {
+	e, err := envOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := intconv.ParseInt(args.ID, 64)
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidID(id=%q)", args.ID)
+	}
+	blk, err := e.blockOf(args.Height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return e.newBTPNetwork(blk, id)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+// schemaString is the schema of the GraphQL API. Integers, hashes and bytes
+// are hex strings with "0x" prefix as JSON-RPC v3, and addresses are in
+// the form of "hx..." or "cx...".
+const schemaString = `
+schema {
+	query: Query
+}
+
+# JSON value. It's used for values having the same format as JSON-RPC v3.
+scalar JSON
+
+type Query {
+	# Block of the height or the hash. It returns the last block if both are
+	# omitted.
+	block(height: String, hash: String): Block
+	# Blocks in the range. "to" is the last block if it's omitted.
+	blocks(from: String!, to: String): [Block!]!
+	transaction(hash: String!): Transaction
+	# Account in the state of the block (last block if height is omitted).
+	account(address: String!, height: String): Account
+	btpNetworkType(id: String!, height: String): BTPNetworkType
+	btpNetwork(id: String!, height: String): BTPNetwork
+}
+
+type Block {
+	version: Int!
+	height: String!
+	hash: String!
+	parentHash: String
+	parent: Block
+	# Next block having the result of transactions in this block.
+	next: Block
+	timestamp: String!
+	proposer: String
+	transactionCount: Int!
+	transactions(skip: Int, first: Int): [Transaction!]!
+	transaction(index: Int!): Transaction
+	# Account in the state of the block.
+	account(address: String!): Account
+	btpNetworkTypes: [BTPNetworkType!]!
+}
+
+type Transaction {
+	hash: String!
+	index: Int!
+	block: Block!
+	version: String
+	from: String
+	to: String
+	value: String
+	stepLimit: String
+	timestamp: String
+	nid: String
+	nonce: String
+	dataType: String
+	data: JSON
+	# Receipt of the transaction. It's null if the result is not finalized.
+	receipt: Receipt
+}
+
+type Receipt {
+	transaction: Transaction!
+	status: String!
+	to: String
+	stepUsed: String!
+	stepPrice: String!
+	cumulativeStepUsed: String!
+	scoreAddress: String
+	failure: JSON
+	logsBloom: String!
+	eventLogs: [EventLog!]!
+}
+
+type EventLog {
+	transaction: Transaction!
+	index: Int!
+	scoreAddress: String!
+	signature: String!
+	indexed: [String]!
+	data: [String]!
+	# SCORE in the state having the event.
+	score: Account!
+}
+
+type Account {
+	address: String!
+	isContract: Boolean!
+	balance: String!
+	# Status of the SCORE. It's null for EOA.
+	scoreStatus: JSON
+	# API of the SCORE. It's null for EOA.
+	scoreApi: JSON
+}
+
+type BTPNetworkType {
+	id: String!
+	name: String!
+	nextProofContextHash: String
+	nextProofContext: String
+	openNetworks: [BTPNetwork!]!
+}
+
+type BTPNetwork {
+	id: String!
+	name: String!
+	owner: String
+	networkType: BTPNetworkType!
+	startHeight: String!
+	open: Boolean!
+	nextMessageSN: String!
+	nextProofContextChanged: Boolean!
+	prevNetworkSectionHash: String
+	lastNetworkSectionHash: String
+}
+`
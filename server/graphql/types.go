@@ -0,0 +1,602 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package graphql
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+)
+
+type blockResolver struct {
+	env *env
+	blk module.Block
+
+	mtx      sync.Mutex
+	next     module.Block
+	receipts module.ReceiptList
+}
+
+func (e *env) newBlock(blk module.Block) (*blockResolver, error) {
+	if err := e.charge(1); err != nil {
+		return nil, err
+	}
+	return &blockResolver{env: e, blk: blk}, nil
+}
+
+func (b *blockResolver) Version() int32 {
+	return int32(b.blk.Version())
+}
+
+func (b *blockResolver) Height() string {
+	return intconv.FormatInt(b.blk.Height())
+}
+
+func (b *blockResolver) Hash() string {
+	return common.HexBytes(b.blk.ID()).String()
+}
+
+func (b *blockResolver) ParentHash() *string {
+	return hexOf(b.blk.PrevID())
+}
+
+func (b *blockResolver) Parent() (*blockResolver, error) {
+	if b.blk.Height() == 0 {
+		return nil, nil
+	}
+	return b.env.blockByHeight(b.blk.Height() - 1)
+}
+
+func (b *blockResolver) Next() (*blockResolver, error) {
+	next, err := b.nextBlock()
+	if err != nil || next == nil {
+		return nil, err
+	}
+	return b.env.newBlock(next)
+}
+
+func (b *blockResolver) Timestamp() string {
+	return intconv.FormatInt(b.blk.Timestamp())
+}
+
+func (b *blockResolver) Proposer() *string {
+	return addressOf(b.blk.Proposer())
+}
+
+func (b *blockResolver) TransactionCount() (int32, error) {
+	count := int32(0)
+	for it := b.blk.NormalTransactions().Iterator(); it.Has(); _ = it.Next() {
+		count++
+	}
+	return count, nil
+}
+
+type transactionsArgs struct {
+	Skip  *int32
+	First *int32
+}
+
+func (b *blockResolver) Transactions(args transactionsArgs) ([]*transactionResolver, error) {
+	skip, first := 0, -1
+	if args.Skip != nil {
+		skip = int(*args.Skip)
+	}
+	if args.First != nil {
+		first = int(*args.First)
+	}
+	if skip < 0 || (args.First != nil && first < 0) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(skip=%d,first=%d)", skip, first)
+	}
+	txs := []*transactionResolver{}
+	idx := 0
+	for it := b.blk.NormalTransactions().Iterator(); it.Has() && first != 0; _, idx = it.Next(), idx+1 {
+		if idx < skip {
+			continue
+		}
+		tx, _, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		t, err := b.newTransaction(tx, idx)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, t)
+		first--
+	}
+	return txs, nil
+}
+
+type indexArgs struct {
+	Index int32
+}
+
+func (b *blockResolver) Transaction(args indexArgs) (*transactionResolver, error) {
+	tx, err := b.blk.NormalTransactions().Get(int(args.Index))
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return b.newTransaction(tx, int(args.Index))
+}
+
+type addressArgs struct {
+	Address string
+}
+
+func (b *blockResolver) Account(args addressArgs) (*accountResolver, error) {
+	addr, err := parseAddress(args.Address)
+	if err != nil {
+		return nil, err
+	}
+	return b.env.newAccount(b.blk, addr)
+}
+
+func (b *blockResolver) BtpNetworkTypes() ([]*btpNetworkTypeResolver, error) {
+	ids, err := b.env.sm.BTPNetworkTypeIDsFromResult(b.blk.Result())
+	if err != nil {
+		return nil, err
+	}
+	nts := []*btpNetworkTypeResolver{}
+	for _, id := range ids {
+		nt, err := b.env.newBTPNetworkType(b.blk, id)
+		if err != nil {
+			return nil, err
+		}
+		if nt != nil {
+			nts = append(nts, nt)
+		}
+	}
+	return nts, nil
+}
+
+// nextBlock returns the next block having the result of the transactions.
+// It returns nil if there is no next block yet.
+func (b *blockResolver) nextBlock() (module.Block, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.next == nil {
+		next, err := b.env.bm.GetBlockByHeight(b.blk.Height() + 1)
+		if errors.NotFoundError.Equals(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		b.next = next
+	}
+	return b.next, nil
+}
+
+// receiptOf returns the receipt of the transaction at the index. It returns
+// nil if the result is not finalized yet.
+func (b *blockResolver) receiptOf(idx int) (module.Receipt, error) {
+	next, err := b.nextBlock()
+	if err != nil || next == nil {
+		return nil, err
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.receipts == nil {
+		rl, err := b.env.sm.ReceiptListFromResult(next.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, err
+		}
+		b.receipts = rl
+	}
+	return b.receipts.Get(idx)
+}
+
+type transactionResolver struct {
+	block  *blockResolver
+	tx     module.Transaction
+	index  int
+	fields map[string]interface{}
+}
+
+func (b *blockResolver) newTransaction(tx module.Transaction, idx int) (*transactionResolver, error) {
+	if err := b.env.charge(1); err != nil {
+		return nil, err
+	}
+	fields, err := jsonFieldsOf(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionResolver{block: b, tx: tx, index: idx, fields: fields}, nil
+}
+
+func (t *transactionResolver) Hash() string {
+	return common.HexBytes(t.tx.ID()).String()
+}
+
+func (t *transactionResolver) Index() int32 {
+	return int32(t.index)
+}
+
+func (t *transactionResolver) Block() *blockResolver {
+	return t.block
+}
+
+func (t *transactionResolver) Version() *string {
+	return stringField(t.fields, "version")
+}
+
+func (t *transactionResolver) From() *string {
+	return addressOf(t.tx.From())
+}
+
+func (t *transactionResolver) To() *string {
+	return stringField(t.fields, "to")
+}
+
+func (t *transactionResolver) Value() *string {
+	return stringField(t.fields, "value")
+}
+
+func (t *transactionResolver) StepLimit() *string {
+	return stringField(t.fields, "stepLimit")
+}
+
+func (t *transactionResolver) Timestamp() *string {
+	return stringField(t.fields, "timestamp")
+}
+
+func (t *transactionResolver) Nid() *string {
+	return stringField(t.fields, "nid")
+}
+
+func (t *transactionResolver) Nonce() *string {
+	return stringField(t.fields, "nonce")
+}
+
+func (t *transactionResolver) DataType() *string {
+	return stringField(t.fields, "dataType")
+}
+
+func (t *transactionResolver) Data() *JSON {
+	return jsonField(t.fields, "data")
+}
+
+func (t *transactionResolver) Receipt() (*receiptResolver, error) {
+	r, err := t.block.receiptOf(t.index)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	if err := t.block.env.charge(1); err != nil {
+		return nil, err
+	}
+	fields, err := jsonFieldsOf(r)
+	if err != nil {
+		return nil, err
+	}
+	return &receiptResolver{tx: t, receipt: r, fields: fields}, nil
+}
+
+type receiptResolver struct {
+	tx      *transactionResolver
+	receipt module.Receipt
+	fields  map[string]interface{}
+}
+
+func formatBigInt(v *big.Int) string {
+	if v == nil {
+		return "0x0"
+	}
+	return intconv.FormatBigInt(v)
+}
+
+func (r *receiptResolver) Transaction() *transactionResolver {
+	return r.tx
+}
+
+func (r *receiptResolver) Status() string {
+	return stringFieldOrEmpty(r.fields, "status")
+}
+
+func (r *receiptResolver) To() *string {
+	return addressOf(r.receipt.To())
+}
+
+func (r *receiptResolver) StepUsed() string {
+	return formatBigInt(r.receipt.StepUsed())
+}
+
+func (r *receiptResolver) StepPrice() string {
+	return formatBigInt(r.receipt.StepPrice())
+}
+
+func (r *receiptResolver) CumulativeStepUsed() string {
+	return formatBigInt(r.receipt.CumulativeStepUsed())
+}
+
+func (r *receiptResolver) ScoreAddress() *string {
+	return stringField(r.fields, "scoreAddress")
+}
+
+func (r *receiptResolver) Failure() *JSON {
+	return jsonField(r.fields, "failure")
+}
+
+func (r *receiptResolver) LogsBloom() string {
+	return stringFieldOrEmpty(r.fields, "logsBloom")
+}
+
+func (r *receiptResolver) EventLogs() ([]*eventLogResolver, error) {
+	logs, _ := r.fields["eventLogs"].([]interface{})
+	els := []*eventLogResolver{}
+	for it, idx := r.receipt.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+		el, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		if err := r.tx.block.env.charge(1); err != nil {
+			return nil, err
+		}
+		elr := &eventLogResolver{receipt: r, el: el, index: idx}
+		if idx < len(logs) {
+			elr.fields, _ = logs[idx].(map[string]interface{})
+		}
+		els = append(els, elr)
+	}
+	return els, nil
+}
+
+type eventLogResolver struct {
+	receipt *receiptResolver
+	el      module.EventLog
+	index   int
+	fields  map[string]interface{}
+}
+
+func (l *eventLogResolver) Transaction() *transactionResolver {
+	return l.receipt.tx
+}
+
+func (l *eventLogResolver) Index() int32 {
+	return int32(l.index)
+}
+
+func (l *eventLogResolver) ScoreAddress() string {
+	return l.el.Address().String()
+}
+
+func (l *eventLogResolver) Signature() string {
+	if indexed := l.el.Indexed(); len(indexed) > 0 {
+		return string(indexed[0])
+	}
+	return ""
+}
+
+func stringsOf(values []interface{}) []*string {
+	ss := make([]*string, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			ss[i] = &s
+		}
+	}
+	return ss
+}
+
+func (l *eventLogResolver) Indexed() []*string {
+	values, _ := l.fields["indexed"].([]interface{})
+	return stringsOf(values)
+}
+
+func (l *eventLogResolver) Data() []*string {
+	values, _ := l.fields["data"].([]interface{})
+	return stringsOf(values)
+}
+
+func (l *eventLogResolver) Score() (*accountResolver, error) {
+	next, err := l.receipt.tx.block.nextBlock()
+	if err != nil {
+		return nil, err
+	}
+	return l.receipt.tx.block.env.newAccount(next, l.el.Address())
+}
+
+// accountResolver resolves the account in the state of the block.
+type accountResolver struct {
+	env  *env
+	blk  module.Block
+	addr module.Address
+}
+
+func (e *env) newAccount(blk module.Block, addr module.Address) (*accountResolver, error) {
+	if err := e.charge(1); err != nil {
+		return nil, err
+	}
+	return &accountResolver{env: e, blk: blk, addr: addr}, nil
+}
+
+func (a *accountResolver) Address() string {
+	return a.addr.String()
+}
+
+func (a *accountResolver) IsContract() bool {
+	return a.addr.IsContract()
+}
+
+func (a *accountResolver) Balance() (string, error) {
+	b, err := a.env.sm.GetBalance(a.blk.Result(), a.addr)
+	if err != nil {
+		return "", err
+	}
+	return formatBigInt(b), nil
+}
+
+func (a *accountResolver) ScoreStatus() (*JSON, error) {
+	if !a.addr.IsContract() {
+		return nil, nil
+	}
+	s, err := a.env.sm.GetSCOREStatus(a.blk.Result(), a.addr)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	jso, err := s.ToJSON(a.blk.Height(), module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	return &JSON{Value: jso}, nil
+}
+
+func (a *accountResolver) ScoreApi() (*JSON, error) {
+	if !a.addr.IsContract() {
+		return nil, nil
+	}
+	info, err := a.env.sm.GetAPIInfo(a.blk.Result(), a.addr)
+	if err != nil {
+		// no active contract
+		return nil, nil
+	}
+	jso, err := info.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	return &JSON{Value: jso}, nil
+}
+
+type btpNetworkTypeResolver struct {
+	env *env
+	blk module.Block
+	id  int64
+	nt  module.BTPNetworkType
+}
+
+func (e *env) newBTPNetworkType(blk module.Block, id int64) (*btpNetworkTypeResolver, error) {
+	nt, err := e.sm.BTPNetworkTypeFromResult(blk.Result(), id)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if nt == nil {
+		return nil, nil
+	}
+	if err := e.charge(1); err != nil {
+		return nil, err
+	}
+	return &btpNetworkTypeResolver{env: e, blk: blk, id: id, nt: nt}, nil
+}
+
+func (r *btpNetworkTypeResolver) ID() string {
+	return intconv.FormatInt(r.id)
+}
+
+func (r *btpNetworkTypeResolver) Name() string {
+	return r.nt.UID()
+}
+
+func (r *btpNetworkTypeResolver) NextProofContextHash() *string {
+	return hexOf(r.nt.NextProofContextHash())
+}
+
+func (r *btpNetworkTypeResolver) NextProofContext() *string {
+	return hexOf(r.nt.NextProofContext())
+}
+
+func (r *btpNetworkTypeResolver) OpenNetworks() ([]*btpNetworkResolver, error) {
+	nws := []*btpNetworkResolver{}
+	for _, id := range r.nt.OpenNetworkIDs() {
+		nw, err := r.env.newBTPNetwork(r.blk, id)
+		if err != nil {
+			return nil, err
+		}
+		if nw != nil {
+			nws = append(nws, nw)
+		}
+	}
+	return nws, nil
+}
+
+type btpNetworkResolver struct {
+	env *env
+	blk module.Block
+	id  int64
+	nw  module.BTPNetwork
+}
+
+func (e *env) newBTPNetwork(blk module.Block, id int64) (*btpNetworkResolver, error) {
+	nw, err := e.sm.BTPNetworkFromResult(blk.Result(), id)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if nw == nil {
+		return nil, nil
+	}
+	if err := e.charge(1); err != nil {
+		return nil, err
+	}
+	return &btpNetworkResolver{env: e, blk: blk, id: id, nw: nw}, nil
+}
+
+func (r *btpNetworkResolver) ID() string {
+	return intconv.FormatInt(r.id)
+}
+
+func (r *btpNetworkResolver) Name() string {
+	return r.nw.Name()
+}
+
+func (r *btpNetworkResolver) Owner() *string {
+	return addressOf(r.nw.Owner())
+}
+
+func (r *btpNetworkResolver) NetworkType() (*btpNetworkTypeResolver, error) {
+	nt, err := r.env.newBTPNetworkType(r.blk, r.nw.NetworkTypeID())
+	if err != nil {
+		return nil, err
+	}
+	if nt == nil {
+		return nil, errors.NotFoundError.Errorf(
+			"NoNetworkType(id=%d)", r.nw.NetworkTypeID())
+	}
+	return nt, nil
+}
+
+func (r *btpNetworkResolver) StartHeight() string {
+	return intconv.FormatInt(r.nw.StartHeight())
+}
+
+func (r *btpNetworkResolver) Open() bool {
+	return r.nw.Open()
+}
+
+func (r *btpNetworkResolver) NextMessageSN() string {
+	return intconv.FormatInt(r.nw.NextMessageSN())
+}
+
+func (r *btpNetworkResolver) NextProofContextChanged() bool {
+	return r.nw.NextProofContextChanged()
+}
+
+func (r *btpNetworkResolver) PrevNetworkSectionHash() *string {
+	return hexOf(r.nw.PrevNetworkSectionHash())
+}
+
+func (r *btpNetworkResolver) LastNetworkSectionHash() *string {
+	return hexOf(r.nw.LastNetworkSectionHash())
+}
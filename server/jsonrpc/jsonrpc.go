@@ -107,6 +107,12 @@ func NewIconOptionsByHeader(h http.Header) IconOptions {
 	return nil
 }
 
+// RateLimiter charges the cost of the method to the client of the request.
+// It returns an error if the client doesn't have enough tokens.
+type RateLimiter interface {
+	Charge(method string) error
+}
+
 type Context struct {
 	echo.Context
 	opts IconOptions
@@ -142,6 +148,13 @@ func (ctx *Context) BatchLimit() int {
 	return batchLimit
 }
 
+func (ctx *Context) Charge(method string) error {
+	if limiter, ok := ctx.Get("rateLimiter").(RateLimiter); ok {
+		return limiter.Charge(method)
+	}
+	return nil
+}
+
 func (ctx *Context) GetTimeout(t time.Duration) time.Duration {
 	if v, err := ctx.opts.GetInt(IconOptionsTimeout); err != nil {
 		return t
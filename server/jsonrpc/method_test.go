@@ -208,6 +208,43 @@ func TestMethodRepository(t *testing.T) {
 	})
 }
 
+type testRateLimiter map[string]bool
+
+func (l testRateLimiter) Charge(method string) error {
+	if l[method] {
+		return ErrorCodeRateLimited.New("rate limit exceeded")
+	}
+	return nil
+}
+
+func TestMethodRepository_RateLimit(t *testing.T) {
+	mtr := metric.NewJsonrpcMetric(metric.DefaultJsonrpcDurationsExpire, metric.DefaultJsonrpcDurationsSize, true)
+	mr := NewMethodRepository(mtr)
+	mr.RegisterMethod("hello", hello)
+	mr.RegisterMethod("noArgs", noArgs)
+	limiter := testRateLimiter{"noArgs": true}
+
+	invoke := func(req, resp string, status int) {
+		c, rec, err := prepare(req)
+		assert.NoError(t, err)
+		c.Set("rateLimiter", RateLimiter(limiter))
+		assert.NoError(t, mr.Handle(c))
+		assert.Equal(t, status, rec.Code)
+		assert.Equal(t, resp, rec.Body.String())
+	}
+
+	invoke(`{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"},"id":"1001"}`,
+		`{"jsonrpc":"2.0","result":"hello, icon","id":"1001"}`+"\n",
+		http.StatusOK)
+	invoke(`{"jsonrpc":"2.0","method":"noArgs","id":"1001"}`,
+		`{"jsonrpc":"2.0","error":{"code":-31008,"message":"RateLimited: rate limit exceeded"},"id":"1001"}`+"\n",
+		http.StatusTooManyRequests)
+	invoke(`{"jsonrpc":"2.0","method":"noArgs"}`, "", http.StatusOK)
+	invoke(`[{"jsonrpc":"2.0","method":"hello","params":{"name":"icon"},"id":1},{"jsonrpc":"2.0","method":"noArgs","id":2}]`,
+		`[{"jsonrpc":"2.0","result":"hello, icon","id":1},{"jsonrpc":"2.0","error":{"code":-31008,"message":"RateLimited: rate limit exceeded"},"id":2}]`+"\n",
+		http.StatusOK)
+}
+
 type HelloParam struct {
 	Name string `json:"name" validate:"required"`
 }
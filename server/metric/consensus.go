@@ -14,6 +14,11 @@ var (
 	msRound      = stats.Int64("consensus_round", "round", stats.UnitDimensionless)
 	msHeightD    = stats.Int64("consensus_height_duration", "block_duration", stats.UnitMilliseconds)
 	msRoundD     = stats.Int64("consensus_round_duration", "block_duration", stats.UnitMilliseconds)
+	msStep       = stats.Int64("consensus_step", "step", stats.UnitDimensionless)
+	msLocked     = stats.Int64("consensus_locked_round", "locked round (-1 for none)", stats.UnitDimensionless)
+	msProposer   = stats.Int64("consensus_proposer", "1 if the node is proposer of the round", stats.UnitDimensionless)
+	msPrevotes   = stats.Int64("consensus_prevotes", "prevotes of the round", stats.UnitDimensionless)
+	msPrecommits = stats.Int64("consensus_precommits", "precommits of the round", stats.UnitDimensionless)
 	consensusMks = []tag.Key{}
 )
 
@@ -22,6 +27,11 @@ func RegisterConsensus() {
 	RegisterMetricView(msRound, view.LastValue(), consensusMks)
 	RegisterMetricView(msHeightD, view.LastValue(), consensusMks)
 	RegisterMetricView(msRoundD, view.LastValue(), consensusMks)
+	RegisterMetricView(msStep, view.LastValue(), consensusMks)
+	RegisterMetricView(msLocked, view.LastValue(), consensusMks)
+	RegisterMetricView(msProposer, view.LastValue(), consensusMks)
+	RegisterMetricView(msPrevotes, view.LastValue(), consensusMks)
+	RegisterMetricView(msPrecommits, view.LastValue(), consensusMks)
 }
 
 type ConsensusMetric struct {
@@ -44,6 +54,18 @@ func (m *ConsensusMetric) OnRound(round int32) {
 	stats.Record(m.ctx, msRound.M(int64(round)), msRoundD.M(int64(d/time.Millisecond)))
 }
 
+func (m *ConsensusMetric) OnStep(step int, lockedRound int32, proposer bool) {
+	var p int64
+	if proposer {
+		p = 1
+	}
+	stats.Record(m.ctx, msStep.M(int64(step)), msLocked.M(int64(lockedRound)), msProposer.M(p))
+}
+
+func (m *ConsensusMetric) OnVotes(prevotes, precommits int) {
+	stats.Record(m.ctx, msPrevotes.M(int64(prevotes)), msPrecommits.M(int64(precommits)))
+}
+
 func NewConsensusMetric(ctx context.Context) *ConsensusMetric {
 	return &ConsensusMetric{
 		ctx : ctx,
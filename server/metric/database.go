@@ -0,0 +1,135 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metric
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/icon-project/goloop/common/db"
+)
+
+var (
+	mkBucket      = NewMetricKey("bucket")
+	mkOperation   = NewMetricKey("operation")
+	msDBLatency   = stats.Float64("db_latency", "Database operation latency", stats.UnitMilliseconds)
+	msDBRead      = stats.Int64("db_read", "Bytes read from database", stats.UnitBytes)
+	msDBWrite     = stats.Int64("db_write", "Bytes written to database", stats.UnitBytes)
+	msDBMiss      = stats.Int64("db_miss", "Database reads for absent entries", stats.UnitDimensionless)
+	msDBMissRatio = stats.Float64("db_miss_ratio", "Ratio of database reads for absent entries", stats.UnitDimensionless)
+	msDBFailure   = stats.Int64("db_failure", "Failed database operations", stats.UnitDimensionless)
+	dbMks         = []tag.Key{mkBucket, mkOperation}
+	dbBucketMks   = []tag.Key{mkBucket}
+)
+
+func RegisterDatabase() {
+	RegisterMetricView(msDBLatency, view.Count(), dbMks)
+	RegisterMetricView(msDBLatency, view.Distribution(
+		0.01, 0.05, 0.1, 0.5, 1, 5, 10, 50, 100, 500, 1000,
+	), dbMks)
+	RegisterMetricView(msDBRead, view.Sum(), dbBucketMks)
+	RegisterMetricView(msDBWrite, view.Sum(), dbBucketMks)
+	RegisterMetricView(msDBMiss, view.Count(), dbBucketMks)
+	RegisterMetricView(msDBMissRatio, view.LastValue(), dbBucketMks)
+	RegisterMetricView(msDBFailure, view.Count(), dbMks)
+}
+
+// bucketNameOf returns printable name of the bucket for the tag value.
+func bucketNameOf(id db.BucketID) string {
+	if id == db.MerkleTrie {
+		return "merkle"
+	}
+	for _, c := range []byte(id) {
+		if c < 0x20 || c > 0x7e {
+			return hex.EncodeToString([]byte(id))
+		}
+	}
+	return string(id)
+}
+
+type bucketRecord struct {
+	ctx   context.Context
+	ops   map[db.Operation]context.Context
+	reads int64
+	miss  int64
+}
+
+type DatabaseMetric struct {
+	lock    sync.Mutex
+	ctx     context.Context
+	buckets map[db.BucketID]*bucketRecord
+}
+
+func (m *DatabaseMetric) recordOf(id db.BucketID) *bucketRecord {
+	if r, ok := m.buckets[id]; ok {
+		return r
+	}
+	ctx := GetMetricContext(m.ctx, &mkBucket, bucketNameOf(id))
+	r := &bucketRecord{
+		ctx: ctx,
+		ops: make(map[db.Operation]context.Context),
+	}
+	for _, op := range []db.Operation{db.OpGet, db.OpHas, db.OpSet, db.OpDelete} {
+		r.ops[op] = GetMetricContext(ctx, &mkOperation, op.String())
+	}
+	m.buckets[id] = r
+	return r
+}
+
+func (m *DatabaseMetric) OnOperation(id db.BucketID, op db.Operation, d time.Duration, size int, hit bool, err error) {
+	m.lock.Lock()
+	r := m.recordOf(id)
+	var ratio float64
+	if op == db.OpGet && err == nil {
+		r.reads += 1
+		if !hit {
+			r.miss += 1
+		}
+		ratio = float64(r.miss) / float64(r.reads)
+	}
+	m.lock.Unlock()
+
+	opCtx := r.ops[op]
+	if err != nil {
+		stats.Record(opCtx, msDBFailure.M(1))
+		return
+	}
+	stats.Record(opCtx, msDBLatency.M(float64(d)/float64(time.Millisecond)))
+	switch op {
+	case db.OpGet:
+		if hit {
+			stats.Record(r.ctx, msDBRead.M(int64(size)), msDBMissRatio.M(ratio))
+		} else {
+			stats.Record(r.ctx, msDBMiss.M(1), msDBMissRatio.M(ratio))
+		}
+	case db.OpSet:
+		stats.Record(r.ctx, msDBWrite.M(int64(size)))
+	}
+}
+
+func NewDatabaseMetric(ctx context.Context) *DatabaseMetric {
+	return &DatabaseMetric{
+		ctx:     ctx,
+		buckets: make(map[db.BucketID]*bucketRecord),
+	}
+}
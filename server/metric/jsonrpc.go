@@ -65,21 +65,96 @@ var (
 		"btp_getHeader":              msRetrieve,
 		"btp_getProof":               msRetrieve,
 		"btp_getSourceInformation":   msRetrieve,
+		"icx_getTransactionsByAddress": {
+			stats.Int64("jsonrpc_get_transactions_by_address", "jsonrpc icx_getTransactionsByAddress method", "ns"),
+			stats.Int64("jsonrpc_get_transactions_by_address_avg", "moving average of jsonrpc icx_getTransactionsByAddress method", "ns"),
+			emptyMks,
+		},
+		"icx_getLogs": {
+			stats.Int64("jsonrpc_get_logs", "jsonrpc icx_getLogs method", "ns"),
+			stats.Int64("jsonrpc_get_logs_avg", "moving average of jsonrpc icx_getLogs method", "ns"),
+			emptyMks,
+		},
 		"debug_getTrace": {
 			stats.Int64("jsonrpc_get_trace", "jsonrpc debug_getTrace method", "ns"),
 			stats.Int64("jsonrpc_get_trace_avg", "moving average of jsonrpc debug_getTrace method", "ns"),
 			emptyMks,
 		},
+		"debug_traceTransaction": {
+			stats.Int64("jsonrpc_trace_transaction", "jsonrpc debug_traceTransaction method", "ns"),
+			stats.Int64("jsonrpc_trace_transaction_avg", "moving average of jsonrpc debug_traceTransaction method", "ns"),
+			emptyMks,
+		},
+		"debug_traceBlock": {
+			stats.Int64("jsonrpc_trace_block", "jsonrpc debug_traceBlock method", "ns"),
+			stats.Int64("jsonrpc_trace_block_avg", "moving average of jsonrpc debug_traceBlock method", "ns"),
+			emptyMks,
+		},
+		"debug_simulateTransactions": {
+			stats.Int64("jsonrpc_simulate_transactions", "jsonrpc debug_simulateTransactions method", "ns"),
+			stats.Int64("jsonrpc_simulate_transactions_avg", "moving average of jsonrpc debug_simulateTransactions method", "ns"),
+			emptyMks,
+		},
+		"debug_getPendingTransactions": {
+			stats.Int64("jsonrpc_get_pending_transactions", "jsonrpc debug_getPendingTransactions method", "ns"),
+			stats.Int64("jsonrpc_get_pending_transactions_avg", "moving average of jsonrpc debug_getPendingTransactions method", "ns"),
+			emptyMks,
+		},
+		"debug_getPoolStatus": {
+			stats.Int64("jsonrpc_get_pool_status", "jsonrpc debug_getPoolStatus method", "ns"),
+			stats.Int64("jsonrpc_get_pool_status_avg", "moving average of jsonrpc debug_getPoolStatus method", "ns"),
+			emptyMks,
+		},
 		"debug_estimateStep": {
 			stats.Int64("jsonrpc_estimate_step", "jsonrpc debug_estimateStep method", "ns"),
 			stats.Int64("jsonrpc_estimate_step_avg", "moving average of jsonrpc debug_estimateStep method", "ns"),
 			emptyMks,
 		},
+		"debug_getStateDiff": {
+			stats.Int64("jsonrpc_get_state_diff", "jsonrpc debug_getStateDiff method", "ns"),
+			stats.Int64("jsonrpc_get_state_diff_avg", "moving average of jsonrpc debug_getStateDiff method", "ns"),
+			emptyMks,
+		},
 		"rosetta_getTrace": {
 			stats.Int64("jsonrpc_rosetta_trace_", "jsonrpc rosetta_getTrace method", "ns"),
 			stats.Int64("jsonrpc_rosetta_trace_avg", "moving average of jsonrpc rosetta_getTTrace method", "ns"),
 			emptyMks,
 		},
+		"eth_chainId": {
+			stats.Int64("jsonrpc_eth_chain_id", "jsonrpc eth_chainId method", "ns"),
+			stats.Int64("jsonrpc_eth_chain_id_avg", "moving average of jsonrpc eth_chainId method", "ns"),
+			emptyMks,
+		},
+		"eth_blockNumber": {
+			stats.Int64("jsonrpc_eth_block_number", "jsonrpc eth_blockNumber method", "ns"),
+			stats.Int64("jsonrpc_eth_block_number_avg", "moving average of jsonrpc eth_blockNumber method", "ns"),
+			emptyMks,
+		},
+		"eth_getBalance": {
+			stats.Int64("jsonrpc_eth_get_balance", "jsonrpc eth_getBalance method", "ns"),
+			stats.Int64("jsonrpc_eth_get_balance_avg", "moving average of jsonrpc eth_getBalance method", "ns"),
+			emptyMks,
+		},
+		"eth_getBlockByNumber": {
+			stats.Int64("jsonrpc_eth_get_block_by_number", "jsonrpc eth_getBlockByNumber method", "ns"),
+			stats.Int64("jsonrpc_eth_get_block_by_number_avg", "moving average of jsonrpc eth_getBlockByNumber method", "ns"),
+			emptyMks,
+		},
+		"eth_getTransactionReceipt": {
+			stats.Int64("jsonrpc_eth_get_transaction_receipt", "jsonrpc eth_getTransactionReceipt method", "ns"),
+			stats.Int64("jsonrpc_eth_get_transaction_receipt_avg", "moving average of jsonrpc eth_getTransactionReceipt method", "ns"),
+			emptyMks,
+		},
+		"eth_getLogs": {
+			stats.Int64("jsonrpc_eth_get_logs", "jsonrpc eth_getLogs method", "ns"),
+			stats.Int64("jsonrpc_eth_get_logs_avg", "moving average of jsonrpc eth_getLogs method", "ns"),
+			emptyMks,
+		},
+		"eth_call": {
+			stats.Int64("jsonrpc_eth_call", "jsonrpc eth_call method", "ns"),
+			stats.Int64("jsonrpc_eth_call_avg", "moving average of jsonrpc eth_call method", "ns"),
+			emptyMks,
+		},
 	}
 	jms    = make([]*JsonrpcMetric, 0)
 	jmsMtx sync.RWMutex
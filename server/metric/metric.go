@@ -189,6 +189,8 @@ func PrometheusExporter() *prometheus.Exporter {
 	RegisterNetwork()
 	RegisterTransaction()
 	RegisterJsonrpc()
+	RegisterDatabase()
+	RegisterRateLimit()
 	return pe
 }
 
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metric
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+const (
+	RateLimitByAddress = "address"
+	RateLimitByAPIKey  = "apikey"
+)
+
+var (
+	mkLimitType        = NewMetricKey("limit_type")
+	msRateLimitAllow   = stats.Int64("ratelimit_allow", "Cost of requests allowed by the rate limiter", stats.UnitDimensionless)
+	msRateLimitReject  = stats.Int64("ratelimit_reject", "Requests rejected by the rate limiter", stats.UnitDimensionless)
+	msRateLimitWS      = stats.Int64("ratelimit_ws_reject", "Websocket sessions rejected by the session quota", stats.UnitDimensionless)
+	msRateLimitClients = stats.Int64("ratelimit_clients", "Clients tracked by the rate limiter", stats.UnitDimensionless)
+	rateLimitMks       = []tag.Key{mkLimitType, mkMethod}
+	rateLimitTypeMks   = []tag.Key{mkLimitType}
+)
+
+func RegisterRateLimit() {
+	RegisterMetricView(msRateLimitAllow, view.Sum(), rateLimitTypeMks)
+	RegisterMetricView(msRateLimitReject, view.Count(), rateLimitMks)
+	RegisterMetricView(msRateLimitWS, view.Count(), rateLimitTypeMks)
+	RegisterMetricView(msRateLimitClients, view.LastValue(), emptyMks)
+}
+
+type RateLimitMetric struct {
+	ctx context.Context
+}
+
+func (m *RateLimitMetric) typeContext(limitType string) context.Context {
+	return GetMetricContext(m.ctx, &mkLimitType, limitType)
+}
+
+func (m *RateLimitMetric) OnAllow(limitType string, cost int) {
+	stats.Record(m.typeContext(limitType), msRateLimitAllow.M(int64(cost)))
+}
+
+func (m *RateLimitMetric) OnReject(limitType string, method string) {
+	ctx := GetMetricContext(m.typeContext(limitType), &mkMethod, method)
+	stats.Record(ctx, msRateLimitReject.M(1))
+}
+
+func (m *RateLimitMetric) OnRejectSession(limitType string) {
+	stats.Record(m.typeContext(limitType), msRateLimitWS.M(1))
+}
+
+func (m *RateLimitMetric) OnClients(n int) {
+	stats.Record(m.ctx, msRateLimitClients.M(int64(n)))
+}
+
+func NewRateLimitMetric(ctx context.Context) *RateLimitMetric {
+	return &RateLimitMetric{ctx: ctx}
+}
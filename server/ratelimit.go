@@ -0,0 +1,358 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+const (
+	HeaderKeyAPIKey        = "Icon-Api-Key"
+	rateLimitSweepInterval = time.Minute
+)
+
+// RateLimit is the quota of a client. Rate is the number of tokens refilled
+// per second and zero means unlimited. Burst is the size of the bucket, and
+// Rate is used if it's zero. WSSessions limits the number of websocket
+// sessions of the client, and zero means no limit except wsMaxSession.
+type RateLimit struct {
+	Rate       int `json:"rate"`
+	Burst      int `json:"burst,omitempty"`
+	WSSessions int `json:"wsSessions,omitempty"`
+}
+
+func (l *RateLimit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Rate)
+}
+
+func (l *RateLimit) validate() error {
+	if l.Rate < 0 || l.Burst < 0 || l.WSSessions < 0 {
+		return errors.IllegalArgumentError.Errorf(
+			"InvalidRateLimit(rate=%d,burst=%d,wsSessions=%d)",
+			l.Rate, l.Burst, l.WSSessions)
+	}
+	return nil
+}
+
+// RateLimitConfig is the configuration of the rate limiter.
+// Limit is applied to each client address. Clients sending one of APIKeys
+// with HeaderKeyAPIKey are limited by the limit of the key instead.
+// Costs are the numbers of tokens for the methods, and the name ending with
+// "*" matches all methods having the prefix. DefaultRateLimitCosts is used
+// if it's not specified, and unmatched methods cost 1.
+// If TrustProxy is set, the address is taken from the headers of the proxy
+// (X-Forwarded-For or X-Real-IP).
+type RateLimitConfig struct {
+	Limit      RateLimit            `json:"limit"`
+	APIKeys    map[string]RateLimit `json:"apiKeys,omitempty"`
+	Costs      map[string]int       `json:"costs,omitempty"`
+	TrustProxy bool                 `json:"trustProxy,omitempty"`
+}
+
+var DefaultRateLimitCosts = map[string]int{
+	"icx_call":                     10,
+	"icx_getLogs":                  10,
+	"icx_getTransactionsByAddress": 5,
+	"icx_sendTransactionAndWait":   5,
+	"icx_waitTransactionResult":    5,
+	"eth_call":                     10,
+	"eth_getLogs":                  10,
+	"graphql":                      10,
+	"debug_*":                      20,
+	"rosetta_*":                    10,
+}
+
+func (c *RateLimitConfig) Validate() error {
+	if err := c.Limit.validate(); err != nil {
+		return err
+	}
+	for key, l := range c.APIKeys {
+		if len(key) == 0 {
+			return errors.IllegalArgumentError.New("EmptyAPIKey")
+		}
+		if err := l.validate(); err != nil {
+			return err
+		}
+	}
+	for method, cost := range c.Costs {
+		if cost < 0 {
+			return errors.IllegalArgumentError.Errorf(
+				"InvalidCost(method=%s,cost=%d)", method, cost)
+		}
+	}
+	return nil
+}
+
+type costPrefix struct {
+	prefix string
+	cost   int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	lock      sync.Mutex
+	config    RateLimitConfig
+	costs     map[string]int
+	prefixes  []costPrefix
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+	mtr       *metric.RateLimitMetric
+}
+
+func newRateLimiter(cfg *RateLimitConfig, mtr *metric.RateLimitMetric) (*rateLimiter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	costs := cfg.Costs
+	if costs == nil {
+		costs = DefaultRateLimitCosts
+	}
+	rl := &rateLimiter{
+		config:  *cfg,
+		costs:   make(map[string]int),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+		mtr:     mtr,
+	}
+	for name, cost := range costs {
+		if strings.HasSuffix(name, "*") {
+			rl.prefixes = append(rl.prefixes, costPrefix{
+				prefix: strings.TrimSuffix(name, "*"),
+				cost:   cost,
+			})
+		} else {
+			rl.costs[name] = cost
+		}
+	}
+	// longer prefix takes precedence
+	sort.Slice(rl.prefixes, func(i, j int) bool {
+		return len(rl.prefixes[i].prefix) > len(rl.prefixes[j].prefix)
+	})
+	rl.lastSweep = rl.now()
+	return rl, nil
+}
+
+func (rl *rateLimiter) costOf(method string) int {
+	if cost, ok := rl.costs[method]; ok {
+		return cost
+	}
+	for _, p := range rl.prefixes {
+		if strings.HasPrefix(method, p.prefix) {
+			return p.cost
+		}
+	}
+	return 1
+}
+
+func addressOf(ctx echo.Context, trustProxy bool) string {
+	if trustProxy {
+		return ctx.RealIP()
+	}
+	addr := ctx.Request().RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// ClientOf returns the client of the request. It fails if the request has
+// an API key which is not registered.
+func (rl *rateLimiter) ClientOf(ctx echo.Context) (*rateLimitClient, error) {
+	if key := ctx.Request().Header.Get(HeaderKeyAPIKey); len(key) > 0 {
+		limit, ok := rl.config.APIKeys[key]
+		if !ok {
+			return nil, errors.IllegalArgumentError.New("UnknownAPIKey")
+		}
+		return &rateLimitClient{
+			rl:        rl,
+			ctx:       ctx,
+			id:        metric.RateLimitByAPIKey + ":" + key,
+			limitType: metric.RateLimitByAPIKey,
+			limit:     limit,
+		}, nil
+	}
+	return &rateLimitClient{
+		rl:        rl,
+		ctx:       ctx,
+		id:        metric.RateLimitByAddress + ":" + addressOf(ctx, rl.config.TrustProxy),
+		limitType: metric.RateLimitByAddress,
+		limit:     rl.config.Limit,
+	}, nil
+}
+
+func (rl *rateLimiter) sweepInLock(now time.Time) {
+	if now.Sub(rl.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	rl.lastSweep = now
+	for id, b := range rl.buckets {
+		// a bucket filled up again is same as a new one.
+		limit := rl.limitOf(id)
+		if limit == nil || b.refill(now, limit) >= limit.capacity() {
+			delete(rl.buckets, id)
+		}
+	}
+	rl.mtr.OnClients(len(rl.buckets))
+}
+
+func (rl *rateLimiter) limitOf(id string) *RateLimit {
+	if strings.HasPrefix(id, metric.RateLimitByAPIKey+":") {
+		key := strings.TrimPrefix(id, metric.RateLimitByAPIKey+":")
+		if l, ok := rl.config.APIKeys[key]; ok {
+			return &l
+		}
+		return nil
+	}
+	return &rl.config.Limit
+}
+
+func (b *tokenBucket) refill(now time.Time, limit *RateLimit) float64 {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(limit.capacity(),
+			b.tokens+elapsed.Seconds()*float64(limit.Rate))
+		b.last = now
+	}
+	return b.tokens
+}
+
+// take consumes tokens from the bucket of the client. It returns zero on
+// success, otherwise it returns the time to wait for enough tokens.
+func (rl *rateLimiter) take(id string, limit *RateLimit, cost int) time.Duration {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	now := rl.now()
+	rl.sweepInLock(now)
+
+	capacity := limit.capacity()
+	b, ok := rl.buckets[id]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		rl.buckets[id] = b
+		rl.mtr.OnClients(len(rl.buckets))
+	}
+	// a method costing more than the bucket requires the full bucket.
+	need := math.Min(float64(cost), capacity)
+	if tokens := b.refill(now, limit); tokens < need {
+		wait := (need - tokens) / float64(limit.Rate)
+		return time.Duration(math.Ceil(wait * float64(time.Second)))
+	}
+	b.tokens -= need
+	return 0
+}
+
+type rateLimitClient struct {
+	rl        *rateLimiter
+	ctx       echo.Context
+	id        string
+	limitType string
+	limit     RateLimit
+}
+
+func (c *rateLimitClient) Charge(method string) error {
+	cost := c.rl.costOf(method)
+	if c.limit.Rate == 0 || cost == 0 {
+		c.rl.mtr.OnAllow(c.limitType, cost)
+		return nil
+	}
+	if wait := c.rl.take(c.id, &c.limit, cost); wait > 0 {
+		c.rl.mtr.OnReject(c.limitType, method)
+		secs := int64(math.Ceil(wait.Seconds()))
+		c.ctx.Response().Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+		return jsonrpc.ErrorCodeRateLimited.New("rate limit exceeded",
+			map[string]interface{}{
+				"method":     method,
+				"cost":       cost,
+				"retryAfter": wait.Milliseconds(),
+			})
+	}
+	c.rl.mtr.OnAllow(c.limitType, cost)
+	return nil
+}
+
+// SessionQuota returns the identifier and the websocket session quota of
+// the client.
+func (c *rateLimitClient) SessionQuota() (string, int) {
+	return c.id, c.limit.WSSessions
+}
+
+func (c *rateLimitClient) OnRejectSession() {
+	c.rl.mtr.OnRejectSession(c.limitType)
+}
+
+func (srv *Manager) SetRateLimit(cfg *RateLimitConfig) error {
+	var rl *rateLimiter
+	if cfg != nil {
+		var err error
+		if rl, err = newRateLimiter(cfg, srv.rlMtr); err != nil {
+			return err
+		}
+	}
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	srv.rateLimiter = rl
+	return nil
+}
+
+func (srv *Manager) getRateLimiter() *rateLimiter {
+	srv.mtx.RLock()
+	defer srv.mtx.RUnlock()
+	return srv.rateLimiter
+}
+
+// RateLimit binds the client of the request to the context, so that
+// the methods and the websocket sessions are limited for the client.
+func (srv *Manager) RateLimit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			rl := srv.getRateLimiter()
+			if rl == nil {
+				return next(ctx)
+			}
+			client, err := rl.ClientOf(ctx)
+			if err != nil {
+				return ctx.JSON(http.StatusUnauthorized, &jsonrpc.Response{
+					Version: jsonrpc.Version,
+					Error:   jsonrpc.ErrorCodeInvalidRequest.New("unknown API key"),
+				})
+			}
+			ctx.Set("rateLimiter", client)
+			return next(ctx)
+		}
+	}
+}
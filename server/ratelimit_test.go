@@ -0,0 +1,259 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestRateLimiter(t *testing.T, cfg *RateLimitConfig) (*rateLimiter, *testClock) {
+	rl, err := newRateLimiter(cfg, metric.NewRateLimitMetric(metric.DefaultMetricContext()))
+	assert.NoError(t, err)
+	clock := &testClock{now: time.Unix(1000, 0)}
+	rl.now = clock.Now
+	rl.lastSweep = clock.Now()
+	return rl, clock
+}
+
+func newRateLimitTestContext(addr string, key string) echo.Context {
+	req := httptest.NewRequest(http.MethodPost, "/api/v3", nil)
+	req.RemoteAddr = addr
+	if key != "" {
+		req.Header.Set(HeaderKeyAPIKey, key)
+	}
+	return echo.New().NewContext(req, httptest.NewRecorder())
+}
+
+func assertRateLimited(t *testing.T, err error) {
+	je, ok := err.(*jsonrpc.Error)
+	if assert.True(t, ok) {
+		assert.Equal(t, jsonrpc.ErrorCodeRateLimited, je.Code)
+	}
+}
+
+func TestRateLimiter_CostOf(t *testing.T) {
+	rl, _ := newTestRateLimiter(t, &RateLimitConfig{})
+	assert.Equal(t, 1, rl.costOf("icx_getLastBlock"))
+	assert.Equal(t, 10, rl.costOf("icx_call"))
+	assert.Equal(t, 20, rl.costOf("debug_getTrace"))
+
+	rl, _ = newTestRateLimiter(t, &RateLimitConfig{
+		Costs: map[string]int{
+			"icx_*":          2,
+			"icx_get*":       3,
+			"icx_getBalance": 0,
+		},
+	})
+	assert.Equal(t, 3, rl.costOf("icx_getLastBlock"))
+	assert.Equal(t, 0, rl.costOf("icx_getBalance"))
+	assert.Equal(t, 2, rl.costOf("icx_call"))
+	assert.Equal(t, 1, rl.costOf("debug_getTrace"))
+}
+
+func TestRateLimiter_Charge(t *testing.T) {
+	rl, clock := newTestRateLimiter(t, &RateLimitConfig{
+		Limit: RateLimit{Rate: 10, Burst: 20},
+	})
+
+	ctx := newRateLimitTestContext("10.0.0.1:1234", "")
+	c, err := rl.ClientOf(ctx)
+	assert.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, c.Charge("icx_getLastBlock"))
+	}
+	assertRateLimited(t, c.Charge("icx_getLastBlock"))
+	assert.Equal(t, "1", ctx.Response().Header().Get("Retry-After"))
+
+	// other clients have their own buckets
+	c2, err := rl.ClientOf(newRateLimitTestContext("10.0.0.2:1234", ""))
+	assert.NoError(t, err)
+	assert.NoError(t, c2.Charge("icx_call"))
+
+	// same address with another port shares the bucket
+	c3, err := rl.ClientOf(newRateLimitTestContext("10.0.0.1:5678", ""))
+	assert.NoError(t, err)
+	assertRateLimited(t, c3.Charge("icx_getLastBlock"))
+
+	clock.Advance(time.Second)
+	assert.NoError(t, c.Charge("icx_call"))
+	assertRateLimited(t, c.Charge("icx_getLastBlock"))
+
+	// cost over the burst requires the full bucket
+	clock.Advance(time.Second)
+	assertRateLimited(t, c.Charge("debug_getTrace"))
+	clock.Advance(time.Second)
+	assert.NoError(t, c.Charge("debug_getTrace"))
+	assertRateLimited(t, c.Charge("icx_getLastBlock"))
+}
+
+func TestRateLimiter_APIKey(t *testing.T) {
+	rl, _ := newTestRateLimiter(t, &RateLimitConfig{
+		Limit: RateLimit{Rate: 1},
+		APIKeys: map[string]RateLimit{
+			"limited":   {Rate: 2},
+			"unlimited": {},
+		},
+	})
+
+	_, err := rl.ClientOf(newRateLimitTestContext("10.0.0.1:1234", "unknown"))
+	assert.Error(t, err)
+
+	c, err := rl.ClientOf(newRateLimitTestContext("10.0.0.1:1234", ""))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Charge("icx_getLastBlock"))
+	assertRateLimited(t, c.Charge("icx_getLastBlock"))
+
+	// the key is limited separately from the address
+	c, err = rl.ClientOf(newRateLimitTestContext("10.0.0.1:1234", "limited"))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Charge("icx_getLastBlock"))
+	c, err = rl.ClientOf(newRateLimitTestContext("10.0.0.2:1234", "limited"))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Charge("icx_getLastBlock"))
+	assertRateLimited(t, c.Charge("icx_getLastBlock"))
+
+	c, err = rl.ClientOf(newRateLimitTestContext("10.0.0.1:1234", "unlimited"))
+	assert.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, c.Charge("debug_getTrace"))
+	}
+}
+
+func TestManager_RateLimitUnknownAPIKey(t *testing.T) {
+	rl, _ := newTestRateLimiter(t, &RateLimitConfig{
+		Limit:   RateLimit{Rate: 1},
+		APIKeys: map[string]RateLimit{"known": {Rate: 2}},
+	})
+	srv := &Manager{rateLimiter: rl}
+	called := false
+	handler := srv.RateLimit()(func(ctx echo.Context) error {
+		called = true
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v3", nil)
+	req.Header.Set(HeaderKeyAPIKey, "unknown")
+	rec := httptest.NewRecorder()
+	assert.NoError(t, handler(echo.New().NewContext(req, rec)))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var resp jsonrpc.Response
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, jsonrpc.Version, resp.Version)
+	assert.NotNil(t, resp.Error)
+	assert.Equal(t, jsonrpc.ErrorCodeInvalidRequest, resp.Error.Code)
+	assert.Nil(t, resp.ID)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v3", nil)
+	req.Header.Set(HeaderKeyAPIKey, "known")
+	assert.NoError(t, handler(echo.New().NewContext(req, httptest.NewRecorder())))
+	assert.True(t, called)
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	rl, clock := newTestRateLimiter(t, &RateLimitConfig{
+		Limit: RateLimit{Rate: 1, Burst: 100},
+	})
+	c1, _ := rl.ClientOf(newRateLimitTestContext("10.0.0.1:1234", ""))
+	c2, _ := rl.ClientOf(newRateLimitTestContext("10.0.0.2:1234", ""))
+	assert.NoError(t, c1.Charge("icx_getLastBlock"))
+	assert.NoError(t, c2.Charge("icx_call"))
+	assert.Len(t, rl.buckets, 2)
+
+	// c1 refilled its bucket, but c2 didn't.
+	clock.Advance(rateLimitSweepInterval)
+	assert.NoError(t, c2.Charge("icx_getLastBlock"))
+	assert.Len(t, rl.buckets, 1)
+	assert.Contains(t, rl.buckets, c2.id)
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name  string
+		cfg   RateLimitConfig
+		valid bool
+	}{
+		{"Empty", RateLimitConfig{}, true},
+		{"NegativeRate", RateLimitConfig{Limit: RateLimit{Rate: -1}}, false},
+		{"NegativeSessions", RateLimitConfig{Limit: RateLimit{WSSessions: -1}}, false},
+		{"EmptyKey", RateLimitConfig{APIKeys: map[string]RateLimit{"": {}}}, false},
+		{"NegativeKeyBurst", RateLimitConfig{APIKeys: map[string]RateLimit{"k": {Burst: -1}}}, false},
+		{"NegativeCost", RateLimitConfig{Costs: map[string]int{"icx_call": -1}}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWSSessionManager_ClientQuota(t *testing.T) {
+	logger := log.New()
+	wm := newWSSessionManagerWithUpgrader(logger, 3, nil)
+	newConn := func() WebSocketConn {
+		return &testWebSocketConn{
+			in:  make(chan interface{}, 1),
+			out: make(chan interface{}, 1),
+		}
+	}
+
+	wss1, _ := wm.NewSessionForClient(newConn(), nil, "a", 2)
+	assert.NotNil(t, wss1)
+	wss2, _ := wm.NewSessionForClient(newConn(), nil, "a", 2)
+	assert.NotNil(t, wss2)
+	wss, reason := wm.NewSessionForClient(newConn(), nil, "a", 2)
+	assert.Nil(t, wss)
+	assert.Equal(t, wsReasonClientQuota, reason)
+
+	wss3, _ := wm.NewSessionForClient(newConn(), nil, "b", 2)
+	assert.NotNil(t, wss3)
+	wss, reason = wm.NewSessionForClient(newConn(), nil, "c", 2)
+	assert.Nil(t, wss)
+	assert.Equal(t, wsReasonTooManySessions, reason)
+
+	wm.StopSession(wss1)
+	wss, _ = wm.NewSessionForClient(newConn(), nil, "a", 2)
+	assert.NotNil(t, wss)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/icon-project/goloop/common/log"
 	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/graphql"
 	"github.com/icon-project/goloop/server/metric"
 	"github.com/icon-project/goloop/server/v3"
 )
@@ -27,10 +28,13 @@ type Config struct {
 	JSONRPCDump           bool
 	JSONRPCIncludeDebug   bool
 	JSONRPCRosetta        bool
+	JSONRPCEth            bool
+	GraphQL               bool
 	DisableRPC            bool
 	JSONRPCDefaultChannel string
 	JSONRPCBatchLimit     int
 	WSMaxSession          int
+	RateLimit             *RateLimitConfig
 }
 
 type Manager struct {
@@ -43,12 +47,16 @@ type Manager struct {
 	jsonrpcDefaultChannel string
 	jsonrpcMessageDump    int32
 	jsonrpcRosetta        int32
+	jsonrpcEth            int32
+	graphQL               int32
 	jsonrpcIncludeDebug   int32
 	jsonrpcBatchLimit     int32
 	disableJSONRPC        int32
 	logger                log.Logger
 	metricsHandler        echo.HandlerFunc
 	mtr                   *metric.JsonrpcMetric
+	rateLimiter           *rateLimiter
+	rlMtr                 *metric.RateLimitMetric
 }
 
 func NewManager(
@@ -77,11 +85,17 @@ func NewManager(
 		logger:                logger,
 		metricsHandler:        echo.WrapHandler(metric.PrometheusExporter()),
 		mtr:                   mtr,
+		rlMtr:                 metric.NewRateLimitMetric(metric.DefaultMetricContext()),
 	}
 	m.SetMessageDump(config.JSONRPCDump)
 	m.SetIncludeDebug(config.JSONRPCIncludeDebug)
 	m.SetRosetta(config.JSONRPCRosetta)
+	m.SetEth(config.JSONRPCEth)
+	m.SetGraphQL(config.GraphQL)
 	m.SetDisableRPC(config.DisableRPC)
+	if err := m.SetRateLimit(config.RateLimit); err != nil {
+		logger.Warnf("ignore invalid rate limit config err=%+v", err)
+	}
 	return m
 }
 
@@ -170,6 +184,22 @@ func (srv *Manager) Rosetta() bool {
 	return atomicLoad(&srv.jsonrpcRosetta)
 }
 
+func (srv *Manager) SetEth(enable bool) {
+	atomicStore(&srv.jsonrpcEth, enable)
+}
+
+func (srv *Manager) Eth() bool {
+	return atomicLoad(&srv.jsonrpcEth)
+}
+
+func (srv *Manager) SetGraphQL(enable bool) {
+	atomicStore(&srv.graphQL, enable)
+}
+
+func (srv *Manager) GraphQL() bool {
+	return atomicLoad(&srv.graphQL)
+}
+
 func (srv *Manager) SetBatchLimit(limitOfBatch int) {
 	atomic.StoreInt32(&srv.jsonrpcBatchLimit, int32(limitOfBatch))
 }
@@ -225,9 +255,12 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 			return next(ctx)
 		}
 	})
+	rpc.Use(srv.RateLimit())
 
 	// v3 APIs
 	mr := v3.MethodRepository(srv.mtr)
+	// icx_getLogs uses EventFilter of this package.
+	mr.RegisterMethod("icx_getLogs", getLogs)
 	v3api := rpc.Group("/v3")
 	v3api.Use(srv.CheckRPC(), JsonRpc(), Chunk())
 	v3api.POST("", mr.Handle, ChainInjector(srv))
@@ -249,12 +282,33 @@ func (srv *Manager) RegisterAPIHandler(g *echo.Group) {
 	rosetta.POST("/", rmr.Handle, ChainInjector(srv))
 	rosetta.POST("/:channel", rmr.Handle, ChainInjector(srv))
 
+	// Ethereum compatible APIs
+	emr := v3.EthMethodRepository(srv.mtr)
+	// eth_getLogs shares the query of icx_getLogs.
+	emr.RegisterMethod("eth_getLogs", ethGetLogs)
+	eth := rpc.Group("/eth")
+	eth.Use(srv.CheckEth(), JsonRpc(), Chunk())
+	eth.POST("", emr.Handle, ChainInjector(srv))
+	eth.POST("/", emr.Handle, ChainInjector(srv))
+	eth.POST("/:channel", emr.Handle, ChainInjector(srv))
+
+	// GraphQL APIs
+	gh := graphql.NewHandler()
+	gqlapi := rpc.Group("/graphql")
+	gqlapi.Use(srv.CheckGraphQL(), Chunk())
+	gqlapi.POST("", gh.Handle, ChainInjector(srv))
+	gqlapi.POST("/", gh.Handle, ChainInjector(srv))
+	gqlapi.POST("/:channel", gh.Handle, ChainInjector(srv))
+
 	// group for websocket
 	ws := g.Group("")
-	ws.Use(srv.CheckRPC())
+	ws.Use(srv.CheckRPC(), srv.RateLimit())
 	ws.GET("/v3/:channel/block", srv.wssm.RunBlockSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/event", srv.wssm.RunEventSession, ChainInjector(srv))
 	ws.GET("/v3/:channel/btp", srv.wssm.RunBtpSession, ChainInjector(srv))
+	ws.GET("/v3/:channel/pending", srv.wssm.RunPendingSession, ChainInjector(srv))
+	// consensus internals are available only for debug.
+	ws.GET("/v3/:channel/consensus", srv.wssm.RunConsensusSession, srv.CheckDebug(), ChainInjector(srv))
 }
 
 func (srv *Manager) RegisterMetricsHandler(g *echo.Group) {
@@ -288,6 +342,28 @@ func (srv *Manager) CheckRosetta() echo.MiddlewareFunc {
 	}
 }
 
+func (srv *Manager) CheckEth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if srv.DisableRPC() || !srv.Eth() {
+				return ctx.String(http.StatusNotFound, "eth API is disabled")
+			}
+			return next(ctx)
+		}
+	}
+}
+
+func (srv *Manager) CheckGraphQL() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if srv.DisableRPC() || !srv.GraphQL() {
+				return ctx.String(http.StatusNotFound, "graphql API is disabled")
+			}
+			return next(ctx)
+		}
+	}
+}
+
 func (srv *Manager) CheckRPC() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
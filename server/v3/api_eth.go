@@ -0,0 +1,786 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/icon-project/goloop/block"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+	"github.com/icon-project/goloop/server/metric"
+	"github.com/icon-project/goloop/service"
+	"github.com/icon-project/goloop/service/scoreapi"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+// Ethereum compatible JSON-RPC APIs for read-only access.
+//
+// Blocks of ethereum are mapped to the blocks of the chain, and transactions
+// belong to the blocks including them. Fields without corresponding values
+// (e.g. difficulty) are filled with zero. Bloom filters are filled with ones,
+// so that clients don't skip any block or receipt by them.
+//
+// Addresses are represented with their 20 bytes of ID. An address in the
+// request is regarded as a contract if there is a contract for the ID.
+//
+// States for a block (used by eth_getBalance and eth_call) are the states
+// after executing the transactions in the block, which are in the result of
+// the next block. So the latest block is the one before the last block.
+
+var (
+	ethZeroHash     = make(common.HexBytes, 32)
+	ethZeroAddress  = make(common.HexBytes, common.AddressIDBytes)
+	ethEmptyNonce   = make(common.HexBytes, 8)
+	ethFullBloom    = common.HexBytes(bytes.Repeat([]byte{0xff}, 256))
+	ethEmptyUncles  = common.HexBytes(mustDecodeHex("1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"))
+	ethEmptyData    = common.HexBytes{}
+	ethStatusFailed = common.HexInt64{Value: 0}
+	ethStatusOK     = common.HexInt64{Value: 1}
+)
+
+func mustDecodeHex(s string) []byte {
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return bs
+}
+
+func hashOrZero(h []byte) common.HexBytes {
+	if len(h) == 0 {
+		return ethZeroHash
+	}
+	return h
+}
+
+func addressIDOf(addr module.Address) common.HexBytes {
+	if addr == nil {
+		return nil
+	}
+	return addr.ID()
+}
+
+// ParseEthAddress returns the ID of the address in ethereum format
+// ("0x" + 40 hex digits).
+func ParseEthAddress(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") || len(s) != 2+common.AddressIDBytes*2 {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidAddress(addr=%q)", s)
+	}
+	id, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidAddress(addr=%q)", s)
+	}
+	return id, nil
+}
+
+// EthHeightOf returns the height for the block tag (or number).
+// "pending", "safe" and "finalized" are same as "latest" as blocks are
+// finalized on commit. "earliest" is the first block available.
+func EthHeightOf(tag string, last, base int64) (int64, error) {
+	switch tag {
+	case "", "latest", "pending", "safe", "finalized":
+		return last, nil
+	case "earliest":
+		return base, nil
+	}
+	var height common.HexInt64
+	if err := height.UnmarshalJSON([]byte(`"` + tag + `"`)); err != nil || !strings.HasPrefix(tag, "0x") {
+		return 0, errors.IllegalArgumentError.Errorf("InvalidBlockTag(tag=%q)", tag)
+	}
+	return height.Value, nil
+}
+
+// ethParams returns positional parameters of the request. It fails if the
+// number of parameters is not in the range.
+func ethParams(params *jsonrpc.Params, min, max int) ([]json.RawMessage, error) {
+	var args []json.RawMessage
+	if !params.IsEmpty() {
+		if err := json.Unmarshal(params.RawMessage(), &args); err != nil {
+			return nil, errors.IllegalArgumentError.Wrap(err, "InvalidParams")
+		}
+	}
+	if len(args) < min || len(args) > max {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidParams(count=%d,min=%d,max=%d)", len(args), min, max)
+	}
+	return args, nil
+}
+
+func ethStringParam(args []json.RawMessage, idx int) (string, error) {
+	if idx >= len(args) {
+		return "", nil
+	}
+	var s string
+	if err := json.Unmarshal(args[idx], &s); err != nil {
+		return "", errors.IllegalArgumentError.Wrapf(err, "InvalidParam(idx=%d)", idx)
+	}
+	return s, nil
+}
+
+type contextForEth struct {
+	contextWithSM
+}
+
+func (c *contextForEth) InvalidParams(err error) error {
+	return jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+}
+
+// LastBlock returns the latest block having the result of its transactions,
+// which is the block before the last block. It returns nil if there is no
+// such block.
+func (c *contextForEth) LastBlock() (module.Block, error) {
+	last, err := c.bm.GetLastBlock()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	if last.Height() < 1 {
+		return nil, nil
+	}
+	blk, err := c.bm.GetBlockByHeight(last.Height() - 1)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	}
+	return blk, c.AsRPCError(err)
+}
+
+// BlockOf returns the block for the block tag or the block object
+// ({"blockNumber": .. } or {"blockHash": .. }). It returns nil if there is
+// no such block. Blocks after LastBlock are not available.
+func (c *contextForEth) BlockOf(arg json.RawMessage) (module.Block, error) {
+	var ref struct {
+		BlockNumber *string          `json:"blockNumber"`
+		BlockHash   *common.HexBytes `json:"blockHash"`
+	}
+	var tag string
+	if len(arg) > 0 && arg[0] == '{' {
+		if err := json.Unmarshal(arg, &ref); err != nil {
+			return nil, c.InvalidParams(err)
+		}
+		if ref.BlockHash != nil {
+			blk, err := c.bm.GetBlock(*ref.BlockHash)
+			if errors.NotFoundError.Equals(err) {
+				return nil, nil
+			} else if err != nil {
+				return nil, c.AsRPCError(err)
+			}
+			last, err := c.LastBlock()
+			if err != nil {
+				return nil, err
+			}
+			if last == nil || blk.Height() > last.Height() {
+				return nil, nil
+			}
+			return blk, c.CheckBaseHeight(blk.Height())
+		}
+		if ref.BlockNumber != nil {
+			tag = *ref.BlockNumber
+		}
+	} else if len(arg) > 0 {
+		if err := json.Unmarshal(arg, &tag); err != nil {
+			return nil, c.InvalidParams(err)
+		}
+	}
+	last, err := c.LastBlock()
+	if err != nil || last == nil {
+		return nil, err
+	}
+	height, err := EthHeightOf(tag, last.Height(), c.chain.GenesisStorage().Height())
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	if height > last.Height() {
+		return nil, nil
+	}
+	if height == last.Height() {
+		return last, nil
+	}
+	if err = c.CheckBaseHeight(height); err != nil {
+		return nil, err
+	}
+	blk, err := c.bm.GetBlockByHeight(height)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	}
+	return blk, c.AsRPCError(err)
+}
+
+// StateOf returns the block having the state after the transactions of the
+// block, which is the next block. The block should be one returned by
+// BlockOf.
+func (c *contextForEth) StateOf(blk module.Block) (module.Block, error) {
+	next, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	return next, nil
+}
+
+// AddressOf returns the address for the ID. It returns the contract address
+// if there is a contract in the state of the result.
+func (c *contextForEth) AddressOf(result []byte, id []byte) module.Address {
+	cx := common.NewContractAddress(id)
+	if _, err := c.sm.GetAPIInfo(result, cx); err == nil {
+		return cx
+	}
+	return common.NewAccountAddress(id)
+}
+
+func ethChainID(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithChain
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := ethParams(params, 0, 0); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	return common.HexInt64{Value: int64(c.chain.NID())}, nil
+}
+
+func ethBlockNumber(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextForEth
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := ethParams(params, 0, 0); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	blk, err := c.LastBlock()
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, jsonrpc.ErrorCodeNotFound.New("NoBlock")
+	}
+	return common.HexInt64{Value: blk.Height()}, nil
+}
+
+func ethGetBalance(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextForEth
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	args, err := ethParams(params, 1, 2)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	s, err := ethStringParam(args, 0)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	id, err := ParseEthAddress(s)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	var tag json.RawMessage
+	if len(args) > 1 {
+		tag = args[1]
+	}
+	blk, err := c.BlockOf(tag)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, jsonrpc.ErrorCodeNotFound.New("NoBlock")
+	}
+	state, err := c.StateOf(blk)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.sm.GetBalance(state.Result(), c.AddressOf(state.Result(), id))
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	return &common.HexInt{Int: *b}, nil
+}
+
+type EthBlock struct {
+	Number           common.HexInt64   `json:"number"`
+	Hash             common.HexBytes   `json:"hash"`
+	ParentHash       common.HexBytes   `json:"parentHash"`
+	Nonce            common.HexBytes   `json:"nonce"`
+	MixHash          common.HexBytes   `json:"mixHash"`
+	Sha3Uncles       common.HexBytes   `json:"sha3Uncles"`
+	LogsBloom        common.HexBytes   `json:"logsBloom"`
+	TransactionsRoot common.HexBytes   `json:"transactionsRoot"`
+	StateRoot        common.HexBytes   `json:"stateRoot"`
+	ReceiptsRoot     common.HexBytes   `json:"receiptsRoot"`
+	Miner            common.HexBytes   `json:"miner"`
+	Difficulty       common.HexInt64   `json:"difficulty"`
+	TotalDifficulty  common.HexInt64   `json:"totalDifficulty"`
+	ExtraData        common.HexBytes   `json:"extraData"`
+	Size             common.HexInt64   `json:"size"`
+	GasLimit         common.HexInt64   `json:"gasLimit"`
+	GasUsed          common.HexInt64   `json:"gasUsed"`
+	Timestamp        common.HexInt64   `json:"timestamp"`
+	Transactions     []interface{}     `json:"transactions"`
+	Uncles           []common.HexBytes `json:"uncles"`
+}
+
+type EthTransaction struct {
+	Hash             common.HexBytes `json:"hash"`
+	Nonce            common.HexInt   `json:"nonce"`
+	BlockHash        common.HexBytes `json:"blockHash"`
+	BlockNumber      common.HexInt64 `json:"blockNumber"`
+	TransactionIndex common.HexInt32 `json:"transactionIndex"`
+	From             common.HexBytes `json:"from"`
+	To               common.HexBytes `json:"to"`
+	Value            common.HexInt   `json:"value"`
+	Gas              common.HexInt   `json:"gas"`
+	GasPrice         common.HexInt   `json:"gasPrice"`
+	Input            common.HexBytes `json:"input"`
+	Type             common.HexInt64 `json:"type"`
+	V                common.HexInt64 `json:"v"`
+	R                common.HexInt64 `json:"r"`
+	S                common.HexInt64 `json:"s"`
+}
+
+// ethFields has the fields of transactions and receipts in JSON used for
+// the ethereum format.
+type ethFields struct {
+	To           *common.Address `json:"to"`
+	Value        *common.HexInt  `json:"value"`
+	StepLimit    *common.HexInt  `json:"stepLimit"`
+	Nonce        *common.HexInt  `json:"nonce"`
+	SCOREAddress *common.Address `json:"scoreAddress"`
+}
+
+type jsonObject interface {
+	ToJSON(version module.JSONVersion) (interface{}, error)
+}
+
+// ethFieldsOf returns the fields in the JSON object. Values of the object may
+// have any type encoded to JSON.
+func ethFieldsOf(obj jsonObject) (*ethFields, error) {
+	jso, err := obj.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(jso)
+	if err != nil {
+		return nil, err
+	}
+	fields := new(ethFields)
+	if err = json.Unmarshal(bs, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func setHexInt(v *common.HexInt, src *common.HexInt) {
+	if src != nil {
+		v.Set(&src.Int)
+	}
+}
+
+func newEthTransaction(tx module.Transaction, blk module.Block, idx int) (*EthTransaction, error) {
+	etx := &EthTransaction{
+		Hash:             tx.ID(),
+		BlockHash:        blk.ID(),
+		BlockNumber:      common.HexInt64{Value: blk.Height()},
+		TransactionIndex: common.HexInt32{Value: int32(idx)},
+		From:             addressIDOf(tx.From()),
+		Input:            ethEmptyData,
+	}
+	if etx.From == nil {
+		etx.From = ethZeroAddress
+	}
+	fields, err := ethFieldsOf(tx)
+	if err != nil {
+		return nil, err
+	}
+	if fields.To != nil {
+		etx.To = fields.To.ID()
+	}
+	setHexInt(&etx.Nonce, fields.Nonce)
+	setHexInt(&etx.Value, fields.Value)
+	setHexInt(&etx.Gas, fields.StepLimit)
+	return etx, nil
+}
+
+func newEthBlock(blk module.Block, full bool) (*EthBlock, error) {
+	eb := &EthBlock{
+		Number:           common.HexInt64{Value: blk.Height()},
+		Hash:             blk.ID(),
+		ParentHash:       hashOrZero(blk.PrevID()),
+		Nonce:            ethEmptyNonce,
+		MixHash:          ethZeroHash,
+		Sha3Uncles:       ethEmptyUncles,
+		LogsBloom:        ethFullBloom,
+		TransactionsRoot: hashOrZero(blk.NormalTransactions().Hash()),
+		StateRoot:        ethZeroHash,
+		ReceiptsRoot:     ethZeroHash,
+		Miner:            addressIDOf(blk.Proposer()),
+		ExtraData:        ethEmptyData,
+		Timestamp:        common.HexInt64{Value: blk.Timestamp() / 1_000_000},
+		Transactions:     []interface{}{},
+		Uncles:           []common.HexBytes{},
+	}
+	if eb.Miner == nil {
+		eb.Miner = ethZeroAddress
+	}
+	idx := 0
+	for it := blk.NormalTransactions().Iterator(); it.Has(); _, idx = it.Next(), idx+1 {
+		tx, _, err := it.Get()
+		if err != nil {
+			return nil, err
+		}
+		if !full {
+			eb.Transactions = append(eb.Transactions, common.HexBytes(tx.ID()))
+			continue
+		}
+		etx, err := newEthTransaction(tx, blk, idx)
+		if err != nil {
+			return nil, err
+		}
+		eb.Transactions = append(eb.Transactions, etx)
+	}
+	return eb, nil
+}
+
+func ethGetBlockByNumber(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextForEth
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	args, err := ethParams(params, 1, 2)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	var full bool
+	if len(args) > 1 {
+		if err := json.Unmarshal(args[1], &full); err != nil {
+			return nil, c.InvalidParams(err)
+		}
+	}
+	blk, err := c.BlockOf(args[0])
+	if err != nil || blk == nil {
+		return nil, err
+	}
+	eb, err := newEthBlock(blk, full)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	return eb, nil
+}
+
+type EthReceipt struct {
+	TransactionHash   common.HexBytes `json:"transactionHash"`
+	TransactionIndex  common.HexInt32 `json:"transactionIndex"`
+	BlockHash         common.HexBytes `json:"blockHash"`
+	BlockNumber       common.HexInt64 `json:"blockNumber"`
+	From              common.HexBytes `json:"from"`
+	To                common.HexBytes `json:"to"`
+	CumulativeGasUsed common.HexInt   `json:"cumulativeGasUsed"`
+	GasUsed           common.HexInt   `json:"gasUsed"`
+	EffectiveGasPrice common.HexInt   `json:"effectiveGasPrice"`
+	ContractAddress   common.HexBytes `json:"contractAddress"`
+	Logs              []*EthLog       `json:"logs"`
+	LogsBloom         common.HexBytes `json:"logsBloom"`
+	Status            common.HexInt64 `json:"status"`
+	Type              common.HexInt64 `json:"type"`
+}
+
+// EthLogIndexBase returns the index of the first log of the transaction in
+// the block. rl is the receipt list of the transactions in the block.
+func EthLogIndexBase(rl module.ReceiptList, txIndex int) (int, error) {
+	base := 0
+	idx := 0
+	for it := rl.Iterator(); it.Has() && idx < txIndex; _, idx = it.Next(), idx+1 {
+		r, err := it.Get()
+		if err != nil {
+			return 0, err
+		}
+		for eit := r.EventLogIterator(); eit.Has(); _ = eit.Next() {
+			base++
+		}
+	}
+	return base, nil
+}
+
+func setBigInt(v *common.HexInt, i *big.Int) {
+	if i != nil {
+		v.Set(i)
+	}
+}
+
+func ethGetTransactionReceipt(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextForEth
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	args, err := ethParams(params, 1, 1)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	var hash common.HexBytes
+	if err := json.Unmarshal(args[0], &hash); err != nil || len(hash) != 32 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf("InvalidHash(hash=%s)", args[0])
+	}
+
+	txInfo, err := c.bm.GetTransactionInfo(hash)
+	if errors.NotFoundError.Equals(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	if txInfo.Group() != module.TransactionGroupNormal {
+		return nil, nil
+	}
+	blk := txInfo.Block()
+	if err = c.CheckBaseHeight(blk.Height()); err != nil {
+		return nil, err
+	}
+	receipt, err := txInfo.GetReceipt()
+	if block.ResultNotFinalizedError.Equals(err) || errors.NotFoundError.Equals(err) {
+		// not executed yet
+		return nil, nil
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tx, err := txInfo.Transaction()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+
+	er := &EthReceipt{
+		TransactionHash:  hash,
+		TransactionIndex: common.HexInt32{Value: int32(txInfo.Index())},
+		BlockHash:        blk.ID(),
+		BlockNumber:      common.HexInt64{Value: blk.Height()},
+		From:             addressIDOf(tx.From()),
+		To:               addressIDOf(receipt.To()),
+		Logs:             []*EthLog{},
+		LogsBloom:        ethFullBloom,
+		Status:           ethStatusFailed,
+	}
+	setBigInt(&er.CumulativeGasUsed, receipt.CumulativeStepUsed())
+	setBigInt(&er.GasUsed, receipt.StepUsed())
+	setBigInt(&er.EffectiveGasPrice, receipt.StepPrice())
+	if receipt.Status() == module.StatusSuccess {
+		er.Status = ethStatusOK
+	}
+	if fields, err := ethFieldsOf(receipt); err != nil {
+		return nil, c.AsRPCError(err)
+	} else if fields.SCOREAddress != nil {
+		er.ContractAddress = fields.SCOREAddress.ID()
+	}
+
+	base := 0
+	if it := receipt.EventLogIterator(); it.Has() {
+		nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		rl, err := c.sm.ReceiptListFromResult(nblk.Result(), module.TransactionGroupNormal)
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		if base, err = EthLogIndexBase(rl, txInfo.Index()); err != nil {
+			return nil, c.AsRPCError(err)
+		}
+	}
+	for it, idx := receipt.EventLogIterator(), 0; it.Has(); _, idx = it.Next(), idx+1 {
+		el, err := it.Get()
+		if err != nil {
+			return nil, c.AsRPCError(err)
+		}
+		log, err := NewEthLog(el)
+		if err != nil {
+			// the log can't be translated
+			continue
+		}
+		log.BlockNumber = er.BlockNumber
+		log.BlockHash = er.BlockHash
+		log.TransactionHash = er.TransactionHash
+		log.TransactionIndex = er.TransactionIndex
+		log.LogIndex = common.HexInt32{Value: int32(base + idx)}
+		er.Logs = append(er.Logs, log)
+	}
+	return er, nil
+}
+
+type EthCallParam struct {
+	From  *string          `json:"from,omitempty"`
+	To    string           `json:"to"`
+	Gas   *string          `json:"gas,omitempty"`
+	Value *string          `json:"value,omitempty"`
+	Data  *common.HexBytes `json:"data,omitempty"`
+	Input *common.HexBytes `json:"input,omitempty"`
+}
+
+func ethCall(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextForEth
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	args, err := ethParams(params, 1, 2)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	var param EthCallParam
+	if err := json.Unmarshal(args[0], &param); err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	to, err := ParseEthAddress(param.To)
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	var data []byte
+	if param.Input != nil {
+		data = *param.Input
+	} else if param.Data != nil {
+		data = *param.Data
+	}
+	if len(data) < 4 {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("NoSelector")
+	}
+	var tag json.RawMessage
+	if len(args) > 1 {
+		tag = args[1]
+	}
+	blk, err := c.BlockOf(tag)
+	if err != nil {
+		return nil, err
+	}
+	if blk == nil {
+		return nil, jsonrpc.ErrorCodeNotFound.New("NoBlock")
+	}
+	state, err := c.StateOf(blk)
+	if err != nil {
+		return nil, err
+	}
+
+	score := common.NewContractAddress(to)
+	info, err := c.sm.GetAPIInfo(state.Result(), score)
+	if err != nil {
+		if service.NoActiveContractError.Equals(err) || errors.NotFoundError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, c.AsRPCError(err)
+	}
+	method, err := findEthMethod(info, data[:4])
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	if method == nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"MethodNotFound(selector=%#x)", data[:4])
+	}
+	values, err := ethDecode(method.types, data[4:])
+	if err != nil {
+		return nil, c.InvalidParams(err)
+	}
+	callParams := make(map[string]interface{})
+	for i, name := range method.inputs {
+		v, err := ethParamOf(method.types[i], values[i], func(id []byte) module.Address {
+			return c.AddressOf(state.Result(), id)
+		})
+		if err != nil {
+			return nil, c.InvalidParams(err)
+		}
+		callParams[name] = v
+	}
+	callData := map[string]interface{}{"method": method.name}
+	if len(callParams) > 0 {
+		callData["params"] = callParams
+	}
+	query := map[string]interface{}{
+		"to":       score.String(),
+		"dataType": "call",
+		"data":     callData,
+	}
+	if param.From != nil {
+		if id, err := ParseEthAddress(*param.From); err == nil {
+			query["from"] = common.NewAccountAddress(id).String()
+		}
+	}
+	qbs, err := json.Marshal(query)
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+
+	bi := common.NewBlockInfo(blk.Height(), blk.Timestamp())
+	result, err := c.sm.Call(state.Result(), state.NextValidators(), qbs, bi)
+	if err != nil {
+		if service.InvalidQueryError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		} else if scoreresult.IsValid(err) {
+			return nil, jsonrpc.ErrScore(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	if len(method.outputs) == 0 {
+		return ethEmptyData, nil
+	}
+	out, err := ethResultOf(method.outputs, result)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return out, nil
+}
+
+// ethResultOf returns ABI encoded result of the call. The result is
+// normalized into its JSON form for translation.
+func ethResultOf(types []scoreapi.DataType, result interface{}) (common.HexBytes, error) {
+	bs, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var jso interface{}
+	if err = json.Unmarshal(bs, &jso); err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	if len(types) == 1 {
+		values = []interface{}{jso}
+	} else if list, ok := jso.([]interface{}); ok && len(list) == len(types) {
+		values = list
+	} else {
+		return nil, errors.UnsupportedError.Errorf("UnsupportedResult(result=%s)", bs)
+	}
+	for i, t := range types {
+		v, err := ethValueOfJSON(t, values[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return ethEncode(types, values)
+}
+
+// EthMethodRepository returns the repository for ethereum compatible APIs.
+// eth_getLogs is registered by the server, as it shares the implementation
+// of icx_getLogs.
+func EthMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
+	mr := jsonrpc.NewMethodRepository(mtr)
+
+	mr.RegisterMethod("eth_chainId", ethChainID)
+	mr.RegisterMethod("eth_blockNumber", ethBlockNumber)
+	mr.RegisterMethod("eth_getBalance", ethGetBalance)
+	mr.RegisterMethod("eth_getBlockByNumber", ethGetBlockByNumber)
+	mr.RegisterMethod("eth_getTransactionReceipt", ethGetTransactionReceipt)
+	mr.RegisterMethod("eth_call", ethCall)
+
+	return mr
+}
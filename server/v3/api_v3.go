@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/icon-project/goloop/common/db"
 	"github.com/icon-project/goloop/common/errors"
 	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/txindex"
 	"github.com/icon-project/goloop/module"
 	"github.com/icon-project/goloop/server/jsonrpc"
 	"github.com/icon-project/goloop/server/metric"
@@ -27,6 +29,26 @@ import (
 
 const (
 	ConfigShowPatchTransaction = false
+
+	// ConfigMaxTransactionsByAddress limits the number of transactions
+	// returned by a request of icx_getTransactionsByAddress.
+	ConfigMaxTransactionsByAddress = 100
+
+	// ConfigMaxSimulatedTransactions limits the number of transactions
+	// in a request of debug_simulateTransactions.
+	ConfigMaxSimulatedTransactions = 20
+
+	// ConfigMaxPendingTransactions limits the number of transactions
+	// returned by a request of debug_getPendingTransactions.
+	ConfigMaxPendingTransactions = 100
+
+	// ConfigMaxStateDiffHeightRange limits the distance between the heights
+	// of blocks compared by a request of debug_getStateDiff.
+	ConfigMaxStateDiffHeightRange = 1000
+
+	// ConfigMaxStateDiffChanges limits the number of changed accounts and
+	// storage entries returned by a request of debug_getStateDiff.
+	ConfigMaxStateDiffChanges = 10000
 )
 
 func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
@@ -53,6 +75,7 @@ func MethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository {
 	mr.RegisterMethod("icx_getProofForEvents", getProofForEvents)
 	mr.RegisterMethod("icx_getScoreStatus", getScoreStatus)
 	mr.RegisterMethod("icx_getNetworkInfo", getNetworkInfo)
+	mr.RegisterMethod("icx_getTransactionsByAddress", getTransactionsByAddress)
 
 	mr.RegisterMethod("btp_getNetworkInfo", getBTPNetworkInfo)
 	mr.RegisterMethod("btp_getNetworkTypeInfo", getBTPNetworkTypeInfo)
@@ -762,6 +785,82 @@ func getNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{},
 	}, nil
 }
 
+type TransactionOfAddress struct {
+	TxHash      common.HexBytes `json:"txHash"`
+	BlockHeight jsonrpc.HexInt  `json:"blockHeight"`
+	TxIndex     jsonrpc.HexInt  `json:"txIndex"`
+}
+
+type TransactionsOfAddress struct {
+	Transactions []*TransactionOfAddress `json:"transactions"`
+	Next         *TransactionCursor      `json:"next,omitempty"`
+}
+
+func getTransactionsByAddress(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithBM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+	if !c.chain.TxIndexEnabled() {
+		return nil, jsonrpc.ErrorCodeServer.New("TxIndexNotEnabled")
+	}
+
+	var param TransactionsByAddressParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	limit := int64(ConfigMaxTransactionsByAddress)
+	if param.Limit != "" {
+		if value, err := param.Limit.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		} else if value <= 0 || value > limit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%d,max=%d)", value, limit)
+		} else {
+			limit = value
+		}
+	}
+	var cursor *txindex.Cursor
+	if param.Cursor != nil {
+		height, err := param.Cursor.BlockHeight.Int64()
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		index, err := param.Cursor.TxIndex.ParseInt(32)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		if height < 0 || index < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidCursor(height=%d,index=%d)", height, index)
+		}
+		cursor = &txindex.Cursor{Height: height, Index: int(index)}
+	}
+
+	entries, next, err := txindex.GetTransactions(c.chain.Database(),
+		param.Address.Address(), cursor, int(limit))
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	res := &TransactionsOfAddress{
+		Transactions: make([]*TransactionOfAddress, len(entries)),
+	}
+	for i, e := range entries {
+		res.Transactions[i] = &TransactionOfAddress{
+			TxHash:      e.TxHash,
+			BlockHeight: jsonrpc.HexInt(intconv.FormatInt(e.Height)),
+			TxIndex:     jsonrpc.HexInt(intconv.FormatInt(int64(e.Index))),
+		}
+	}
+	if next != nil {
+		res.Next = &TransactionCursor{
+			BlockHeight: jsonrpc.HexInt(intconv.FormatInt(next.Height)),
+			TxIndex:     jsonrpc.HexInt(intconv.FormatInt(int64(next.Index))),
+		}
+	}
+	return res, nil
+}
+
 func getBTPNetworkInfo(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1148,7 +1247,13 @@ func DebugMethodRepository(mtr *metric.JsonrpcMetric) *jsonrpc.MethodRepository
 	RegisterValidationRule(mr.Validator())
 
 	mr.RegisterMethod("debug_getTrace", getTrace)
+	mr.RegisterMethod("debug_traceTransaction", traceTransaction)
+	mr.RegisterMethod("debug_traceBlock", traceBlock)
 	mr.RegisterMethod("debug_estimateStep", estimateStep)
+	mr.RegisterMethod("debug_simulateTransactions", simulateTransactions)
+	mr.RegisterMethod("debug_getPendingTransactions", getPendingTransactions)
+	mr.RegisterMethod("debug_getPoolStatus", getPoolStatus)
+	mr.RegisterMethod("debug_getStateDiff", getStateDiff)
 
 	return mr
 }
@@ -1188,23 +1293,10 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 	} else if err != nil {
 		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
 	}
-	csi, err := c.bm.NewConsensusInfo(blk)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
-	}
-	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
-	}
-	tr1, err := c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
-	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
-	}
-	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	tr2, err := transitionForTrace(&c, blk)
 	if err != nil {
-		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		return nil, err
 	}
-	tr2 = c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk)
 
 	cb := &traceCallback{
 		logs:    make([]interface{}, 0, 100),
@@ -1235,6 +1327,261 @@ func getTrace(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error)
 	}
 }
 
+// transitionForTrace returns a transition replaying the transactions
+// in the block.
+func transitionForTrace(c *contextWithSM, blk module.Block) (module.Transition, error) {
+	csi, err := c.bm.NewConsensusInfo(blk)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	nblk, err := c.bm.GetBlockByHeight(blk.Height() + 1)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr1, err := c.sm.CreateInitialTransition(blk.Result(), blk.NextValidators())
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	tr2, err := c.sm.CreateTransition(tr1, blk.NormalTransactions(), blk, csi, true)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return c.sm.PatchTransition(tr2, nblk.PatchTransactions(), nblk), nil
+}
+
+const (
+	TracerCall = "callTracer"
+	TracerLog  = "logTracer"
+)
+
+// newTraceCallbackFor returns the callback and the trace mode for
+// the tracer.
+func newTraceCallbackFor(tracer string) (*traceCallback, module.TraceMode, error) {
+	switch tracer {
+	case "", TracerCall:
+		return &traceCallback{
+			channel: make(chan interface{}, 10),
+			ct:      trace.NewCallTracer(),
+		}, module.TraceModeCallTree, nil
+	case TracerLog:
+		return &traceCallback{
+			logs:    make([]interface{}, 0, 100),
+			channel: make(chan interface{}, 10),
+		}, module.TraceModeInvoke, nil
+	default:
+		return nil, module.TraceModeNone, errors.IllegalArgumentError.Errorf(
+			"UnknownTracer(%s)", tracer)
+	}
+}
+
+// executeForTrace executes the transition with the callback, and waits
+// for the completion until the timeout.
+func executeForTrace(
+	c *contextWithSM, tr module.Transition, ti module.TraceInfo,
+	timeout time.Duration,
+) error {
+	cb := ti.Callback.(*traceCallback)
+	canceller, err := tr.ExecuteForTrace(ti)
+	if err != nil {
+		return jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	select {
+	case <-time.After(timeout):
+		canceller()
+		return jsonrpc.ErrorCodeSystemTimeout.New("Not enough time to get trace")
+	case <-cb.channel:
+		return nil
+	}
+}
+
+func traceTransaction(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param TraceTransactionParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	cb, mode, err := newTraceCallbackFor(param.Tracer)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	txInfo, err := c.bm.GetTransactionInfo(param.Hash.Bytes())
+	if errors.NotFoundError.Equals(err) {
+		if c.sm.HasTransaction(param.Hash.Bytes()) {
+			return nil, jsonrpc.ErrorCodePending.New("Pending")
+		}
+		return nil, jsonrpc.ErrorCodeNotFound.Wrap(err, c.debug)
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	if txInfo.Group() == module.TransactionGroupPatch {
+		return nil, jsonrpc.ErrorCodeInvalidParams.New("Patch transaction can't be replayed")
+	}
+
+	blk := txInfo.Block()
+	if err = c.CheckBaseHeight(blk.Height()); err != nil {
+		return nil, err
+	}
+	_, err = txInfo.GetReceipt()
+	if block.ResultNotFinalizedError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	tr, err := transitionForTrace(&c, blk)
+	if err != nil {
+		return nil, err
+	}
+	ti := module.TraceInfo{
+		TraceMode: mode,
+		Range:     module.TraceRangeTransaction,
+		Group:     txInfo.Group(),
+		Index:     txInfo.Index(),
+		Callback:  cb,
+	}
+	if err = executeForTrace(&c, tr, ti, time.Second*5); err != nil {
+		return nil, err
+	}
+
+	if mode == module.TraceModeInvoke {
+		return cb.invokeTraceToJSON(), nil
+	}
+	txs, err := cb.callTraceToJSON()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	if len(txs) != 1 {
+		return nil, jsonrpc.ErrorCodeSystem.Errorf("InvalidTraceResult(txs=%d)", len(txs))
+	}
+	return txs[0], nil
+}
+
+func traceBlock(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param TraceBlockParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	cb, mode, err := newTraceCallbackFor(param.Tracer)
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	var blk module.Block
+	if len(param.Hash) > 0 {
+		blk, err = c.GetBlockByID(param.Hash.Bytes())
+	} else if len(param.Height) > 0 {
+		blk, err = c.GetBlockByHeight(param.Height)
+	} else {
+		// results of the transactions in the last block are not finalized yet
+		if blk, err = c.bm.GetLastBlock(); err == nil && blk.Height() > 0 {
+			blk, err = c.bm.GetBlockByHeight(blk.Height() - 1)
+		}
+		err = c.AsRPCError(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = c.CheckBaseHeight(blk.Height()); err != nil {
+		return nil, err
+	}
+	if _, err = c.bm.GetBlockByHeight(blk.Height() + 1); errors.NotFoundError.Equals(err) {
+		return nil, jsonrpc.ErrorCodeExecuting.New("Executing")
+	} else if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+
+	tr, err := transitionForTrace(&c, blk)
+	if err != nil {
+		return nil, err
+	}
+	ti := module.TraceInfo{
+		TraceMode: mode,
+		Range:     module.TraceRangeBlock,
+		Callback:  cb,
+	}
+	if err = executeForTrace(&c, tr, ti, time.Second*60); err != nil {
+		return nil, err
+	}
+
+	if mode == module.TraceModeInvoke {
+		return cb.invokeTraceToJSON(), nil
+	}
+	txs, err := cb.callTraceToJSON()
+	if err != nil {
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	return map[string]interface{}{
+		"blockHash":    "0x" + hex.EncodeToString(blk.ID()),
+		"blockHeight":  fmt.Sprintf("%#x", blk.Height()),
+		"transactions": txs,
+	}, nil
+}
+
+func getStateDiff(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithBM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param StateDiffParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	var storage bool
+	if param.Storage != "" {
+		var err error
+		if storage, err = param.Storage.Bool(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+	}
+
+	blk1, err := c.GetBlockByHeight(param.From)
+	if err != nil {
+		return nil, err
+	}
+	blk2, err := c.GetBlockByHeight(param.To)
+	if err != nil {
+		return nil, err
+	}
+	if span := blk2.Height() - blk1.Height(); span > ConfigMaxStateDiffHeightRange ||
+		span < -ConfigMaxStateDiffHeightRange {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooLargeRange(from=%d,to=%d,max=%d)",
+			blk1.Height(), blk2.Height(), ConfigMaxStateDiffHeightRange)
+	}
+	diffs, err := service.DiffWorldStatesOfResults(c.chain.Database(),
+		blk1.Result(), blk2.Result(), storage, ConfigMaxStateDiffChanges)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+	}
+	accounts := make([]interface{}, 0, len(diffs))
+	for _, d := range diffs {
+		jso, err := d.ToJSON(module.JSONVersionLast)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		accounts = append(accounts, jso)
+	}
+	return map[string]interface{}{
+		"from":     &common.HexInt64{Value: blk1.Height()},
+		"to":       &common.HexInt64{Value: blk2.Height()},
+		"accounts": accounts,
+	}, nil
+}
+
 func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
 	var c contextWithSM
 	if err := c.Init(ctx); err != nil {
@@ -1283,6 +1630,237 @@ func estimateStep(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, er
 	return steps, nil
 }
 
+func accountOverrideOf(p *AccountOverrideParam) (*module.AccountOverride, error) {
+	ov := &module.AccountOverride{
+		Address:     p.Address.Address(),
+		ContentType: p.ContentType,
+		Content:     p.Content,
+		Params:      p.Params,
+	}
+	if len(p.Balance) > 0 {
+		balance, err := p.Balance.BigInt()
+		if err != nil {
+			return nil, err
+		}
+		if balance.Sign() < 0 {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"NegativeBalance(addr=%s)", p.Address)
+		}
+		ov.Balance = balance
+	}
+	if len(p.Owner) > 0 {
+		ov.Owner = p.Owner.Address()
+	}
+	if len(p.Storage) > 0 {
+		ov.Storage = make(map[string][]byte, len(p.Storage))
+		for k, v := range p.Storage {
+			key, err := hex.DecodeString(strings.TrimPrefix(k, "0x"))
+			if err != nil || len(key) == 0 {
+				return nil, errors.IllegalArgumentError.Errorf(
+					"InvalidStorageKey(addr=%s,key=%s)", p.Address, k)
+			}
+			ov.Storage[string(key)] = v
+		}
+	}
+	return ov, nil
+}
+
+func simulateTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param SimulateTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	if len(param.Transactions) > ConfigMaxSimulatedTransactions {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"TooManyTransactions(n=%d,max=%d)",
+			len(param.Transactions), ConfigMaxSimulatedTransactions)
+	}
+	var raw struct {
+		Transactions []json.RawMessage `json:"transactions"`
+	}
+	if err := json.Unmarshal(params.RawMessage(), &raw); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	txs := make([][]byte, len(raw.Transactions))
+	for i, tx := range raw.Transactions {
+		txs[i] = tx
+	}
+	overrides := make([]*module.AccountOverride, len(param.Overrides))
+	for i := range param.Overrides {
+		ov, err := accountOverrideOf(&param.Overrides[i])
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		overrides[i] = ov
+	}
+
+	blk, err := c.GetBlockByHeight(param.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	// new block information based on the base block
+	oldTS := blk.Timestamp()
+	newTS := common.UnixMicroFromTime(time.Now())
+	if newTS <= oldTS {
+		newTS = oldTS + 1
+	}
+	bi := common.NewBlockInfo(blk.Height()+1, newTS)
+
+	results, err := c.sm.SimulateTransactions(
+		blk.Result(),
+		blk.NextValidators().Hash(),
+		txs,
+		bi,
+		overrides,
+	)
+	if err != nil {
+		if errors.IllegalArgumentError.Equals(err) ||
+			scoreresult.InvalidParameterError.Equals(err) {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		}
+		return nil, jsonrpc.ErrorCodeServer.Wrap(err, c.debug)
+	}
+
+	res := make([]interface{}, len(results))
+	for i, r := range results {
+		rct, err := r.Receipt.ToJSON(module.JSONVersionLast)
+		if err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+		changes := make([]interface{}, len(r.BalanceChanges))
+		for j, bc := range r.BalanceChanges {
+			changes[j] = map[string]interface{}{
+				"address": bc.Address,
+				"balance": new(common.HexInt).SetValue(bc.Balance),
+				"delta":   new(common.HexInt).SetValue(bc.Delta),
+			}
+		}
+		res[i] = map[string]interface{}{
+			"receipt":        rct,
+			"balanceChanges": changes,
+		}
+	}
+	return res, nil
+}
+
+var txGroupNames = map[module.TransactionGroup]string{
+	module.TransactionGroupNormal: "normal",
+	module.TransactionGroupPatch:  "patch",
+}
+
+func txGroupOf(name string) (module.TransactionGroup, bool) {
+	if name == "" {
+		return module.TransactionGroupNormal, true
+	}
+	for g, n := range txGroupNames {
+		if n == name {
+			return g, true
+		}
+	}
+	return 0, false
+}
+
+func getPendingTransactions(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param PendingTransactionsParam
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+	group, ok := txGroupOf(param.Group)
+	if !ok {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+			"InvalidGroup(group=%s)", param.Group)
+	}
+	var skip int64
+	if param.Skip != "" {
+		if value, err := param.Skip.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		} else if value < 0 {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidSkip(skip=%d)", value)
+		} else {
+			skip = value
+		}
+	}
+	limit := int64(ConfigMaxPendingTransactions)
+	if param.Limit != "" {
+		if value, err := param.Limit.Int64(); err != nil {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+		} else if value <= 0 || value > limit {
+			return nil, jsonrpc.ErrorCodeInvalidParams.Errorf(
+				"InvalidLimit(limit=%d,max=%d)", value, limit)
+		} else {
+			limit = value
+		}
+	}
+	var from module.Address
+	if param.From != "" {
+		from = param.From.Address()
+	}
+
+	txs, total, err := c.sm.GetPendingTransactions(group, from, int(skip), int(limit))
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	jsos := make([]interface{}, len(txs))
+	for i, tx := range txs {
+		if jsos[i], err = tx.ToJSON(module.JSONVersion3); err != nil {
+			return nil, jsonrpc.ErrorCodeSystem.Wrap(err, c.debug)
+		}
+	}
+	return map[string]interface{}{
+		"total":        intconv.FormatInt(int64(total)),
+		"transactions": jsos,
+	}, nil
+}
+
+func getPoolStatus(ctx *jsonrpc.Context, params *jsonrpc.Params) (interface{}, error) {
+	var c contextWithSM
+	if err := c.Init(ctx); err != nil {
+		return nil, err
+	}
+
+	var param struct{}
+	if err := params.Convert(&param); err != nil {
+		return nil, jsonrpc.ErrorCodeInvalidParams.Wrap(err, c.debug)
+	}
+
+	status, err := c.sm.GetTxPoolStatus()
+	if err != nil {
+		return nil, c.AsRPCError(err)
+	}
+	now := common.UnixMicroFromTime(time.Now())
+	res := make(map[string]interface{})
+	for _, s := range status {
+		jso := map[string]interface{}{
+			"size":    intconv.FormatInt(int64(s.Size)),
+			"used":    intconv.FormatInt(int64(s.Used)),
+			"dropped": intconv.FormatInt(s.Dropped),
+			"expired": intconv.FormatInt(s.Expired),
+		}
+		if s.Used > 0 {
+			jso["oldestTimestamp"] = intconv.FormatInt(s.OldestTimestamp)
+			age := now - s.OldestTimestamp
+			if age < 0 {
+				age = 0
+			}
+			jso["oldestAge"] = intconv.FormatInt(age)
+		}
+		res[txGroupNames[s.Group]] = jso
+	}
+	return res, nil
+}
+
 type MissingTransactionInfo interface {
 	ReplaceID(height int64, id []byte) []byte
 	GetLocationOf(id []byte) (int64, int, bool)
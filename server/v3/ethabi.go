@@ -0,0 +1,484 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+// Translation between SCORE API and Ethereum ABI.
+//
+// Scalar types of SCORE API are mapped to ABI types as below, and methods or
+// events using other types (list, dict and struct) can't be translated.
+//
+//	int     -> int256
+//	bool    -> bool
+//	Address -> address (the type of the address is not preserved)
+//	str     -> string
+//	bytes   -> bytes
+//
+// Selectors of methods and the first topics of events are made of ABI
+// signatures. For example, "Transfer(Address,Address,int,bytes)" of SCORE
+// API is mapped to keccak256("Transfer(address,address,int256,bytes)").
+
+const ethWordSize = 32
+
+var (
+	ethMaxInt256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
+	ethMinInt256 = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+	ethModulus   = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+func keccak256(data ...[]byte) []byte {
+	d := sha3.NewLegacyKeccak256()
+	for _, b := range data {
+		d.Write(b)
+	}
+	return d.Sum(nil)
+}
+
+func ethTypeOf(t scoreapi.DataType) (string, error) {
+	if t.ListDepth() == 0 {
+		switch t.Tag() {
+		case scoreapi.TInteger:
+			return "int256", nil
+		case scoreapi.TBool:
+			return "bool", nil
+		case scoreapi.TAddress:
+			return "address", nil
+		case scoreapi.TString:
+			return "string", nil
+		case scoreapi.TBytes:
+			return "bytes", nil
+		}
+	}
+	return "", errors.UnsupportedError.Errorf("UnsupportedType(type=%s)", t.String())
+}
+
+func ethIsDynamic(t scoreapi.DataType) bool {
+	return t.Tag() == scoreapi.TString || t.Tag() == scoreapi.TBytes
+}
+
+func ethSignatureOf(name string, types []scoreapi.DataType) (string, error) {
+	args := make([]string, len(types))
+	for i, t := range types {
+		et, err := ethTypeOf(t)
+		if err != nil {
+			return "", err
+		}
+		args[i] = et
+	}
+	return name + "(" + strings.Join(args, ",") + ")", nil
+}
+
+// parseEventSignature returns the name and the types of parameters of the
+// event signature in SCORE API (e.g. "Transfer(Address,Address,int)").
+func parseEventSignature(sig string) (string, []scoreapi.DataType, error) {
+	lp := strings.IndexByte(sig, '(')
+	if lp <= 0 || !strings.HasSuffix(sig, ")") {
+		return "", nil, errors.IllegalArgumentError.Errorf("InvalidSignature(sig=%q)", sig)
+	}
+	name := sig[:lp]
+	args := sig[lp+1 : len(sig)-1]
+	if len(args) == 0 {
+		return name, nil, nil
+	}
+	var types []scoreapi.DataType
+	for _, arg := range strings.Split(args, ",") {
+		t := scoreapi.DataTypeOf(arg)
+		if t == scoreapi.Unknown {
+			return "", nil, errors.IllegalArgumentError.Errorf("InvalidSignature(sig=%q)", sig)
+		}
+		types = append(types, t)
+	}
+	return name, types, nil
+}
+
+func ethEncodeInt(v *big.Int) ([]byte, error) {
+	word := make([]byte, ethWordSize)
+	if v == nil {
+		return word, nil
+	}
+	if v.Cmp(ethMaxInt256) > 0 || v.Cmp(ethMinInt256) < 0 {
+		return nil, errors.IllegalArgumentError.Errorf("IntegerOverflow(value=%s)", v)
+	}
+	if v.Sign() < 0 {
+		v = new(big.Int).Add(v, ethModulus)
+	}
+	return v.FillBytes(word), nil
+}
+
+func ethDecodeInt(word []byte) *big.Int {
+	v := new(big.Int).SetBytes(word)
+	if len(word) > 0 && word[0]&0x80 != 0 {
+		v.Sub(v, ethModulus)
+	}
+	return v
+}
+
+func ethPadRight(b []byte) []byte {
+	size := (len(b) + ethWordSize - 1) / ethWordSize * ethWordSize
+	padded := make([]byte, size)
+	copy(padded, b)
+	return padded
+}
+
+// ethEncodeStatic encodes the value of static type into a word.
+// The value is one of values returned by scoreapi.DataType.ConvertBytesToAny.
+func ethEncodeStatic(t scoreapi.DataType, v any) ([]byte, error) {
+	switch t.Tag() {
+	case scoreapi.TInteger:
+		i, _ := v.(*big.Int)
+		return ethEncodeInt(i)
+	case scoreapi.TBool:
+		word := make([]byte, ethWordSize)
+		if b, _ := v.(bool); b {
+			word[ethWordSize-1] = 1
+		}
+		return word, nil
+	case scoreapi.TAddress:
+		word := make([]byte, ethWordSize)
+		if addr, ok := v.(module.Address); ok && addr != nil {
+			copy(word[ethWordSize-common.AddressIDBytes:], addr.ID())
+		}
+		return word, nil
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnsupportedType(type=%s)", t.String())
+	}
+}
+
+func ethBytesOf(t scoreapi.DataType, v any) ([]byte, error) {
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	default:
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidValue(type=%s,value=%v)", t.String(), v)
+	}
+}
+
+// ethEncode encodes the values with the types into ABI encoded data.
+func ethEncode(types []scoreapi.DataType, values []any) ([]byte, error) {
+	head := make([]byte, 0, len(types)*ethWordSize)
+	var tail []byte
+	for i, t := range types {
+		if _, err := ethTypeOf(t); err != nil {
+			return nil, err
+		}
+		if !ethIsDynamic(t) {
+			word, err := ethEncodeStatic(t, values[i])
+			if err != nil {
+				return nil, err
+			}
+			head = append(head, word...)
+			continue
+		}
+		bs, err := ethBytesOf(t, values[i])
+		if err != nil {
+			return nil, err
+		}
+		offset, _ := ethEncodeInt(big.NewInt(int64(len(types)*ethWordSize + len(tail))))
+		head = append(head, offset...)
+		size, _ := ethEncodeInt(big.NewInt(int64(len(bs))))
+		tail = append(tail, size...)
+		tail = append(tail, ethPadRight(bs)...)
+	}
+	return append(head, tail...), nil
+}
+
+func ethWordAt(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+ethWordSize > len(data) {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"InvalidData(offset=%d,size=%d)", offset, len(data))
+	}
+	return data[offset : offset+ethWordSize], nil
+}
+
+func ethOffsetAt(data []byte, offset int) (int, error) {
+	word, err := ethWordAt(data, offset)
+	if err != nil {
+		return 0, err
+	}
+	v := new(big.Int).SetBytes(word)
+	if !v.IsInt64() || v.Int64() > int64(len(data)) {
+		return 0, errors.IllegalArgumentError.Errorf("InvalidOffset(offset=%d)", offset)
+	}
+	return int(v.Int64()), nil
+}
+
+// ethDecode decodes ABI encoded data with the types. Addresses are returned
+// as 20 bytes of their IDs.
+func ethDecode(types []scoreapi.DataType, data []byte) ([]any, error) {
+	values := make([]any, len(types))
+	for i, t := range types {
+		if _, err := ethTypeOf(t); err != nil {
+			return nil, err
+		}
+		word, err := ethWordAt(data, i*ethWordSize)
+		if err != nil {
+			return nil, err
+		}
+		switch t.Tag() {
+		case scoreapi.TInteger:
+			values[i] = ethDecodeInt(word)
+		case scoreapi.TBool:
+			values[i] = new(big.Int).SetBytes(word).Sign() != 0
+		case scoreapi.TAddress:
+			values[i] = word[ethWordSize-common.AddressIDBytes:]
+		default:
+			offset, err := ethOffsetAt(data, i*ethWordSize)
+			if err != nil {
+				return nil, err
+			}
+			size, err := ethOffsetAt(data, offset)
+			if err != nil {
+				return nil, err
+			}
+			start := offset + ethWordSize
+			if start+size > len(data) {
+				return nil, errors.IllegalArgumentError.Errorf(
+					"InvalidData(offset=%d,size=%d)", start, size)
+			}
+			bs := data[start : start+size]
+			if t.Tag() == scoreapi.TString {
+				if !utf8.Valid(bs) {
+					return nil, errors.IllegalArgumentError.New("InvalidString")
+				}
+				values[i] = string(bs)
+			} else {
+				values[i] = bs
+			}
+		}
+	}
+	return values, nil
+}
+
+type EthLog struct {
+	Address          common.HexBytes   `json:"address"`
+	Topics           []common.HexBytes `json:"topics"`
+	Data             common.HexBytes   `json:"data"`
+	BlockNumber      common.HexInt64   `json:"blockNumber"`
+	BlockHash        common.HexBytes   `json:"blockHash"`
+	TransactionHash  common.HexBytes   `json:"transactionHash"`
+	TransactionIndex common.HexInt32   `json:"transactionIndex"`
+	LogIndex         common.HexInt32   `json:"logIndex"`
+	Removed          bool              `json:"removed"`
+}
+
+// NewEthLog returns the log translated from the event log. The position of
+// the log is not filled.
+func NewEthLog(el module.EventLog) (*EthLog, error) {
+	indexed := el.Indexed()
+	data := el.Data()
+	if len(indexed) == 0 {
+		return nil, errors.InvalidStateError.New("NoEventSignature")
+	}
+	name, types, err := parseEventSignature(string(indexed[0]))
+	if err != nil {
+		return nil, err
+	}
+	if len(indexed)-1+len(data) != len(types) {
+		return nil, errors.InvalidStateError.Errorf(
+			"InvalidEventLog(sig=%q,indexed=%d,data=%d)",
+			indexed[0], len(indexed)-1, len(data))
+	}
+	sig, err := ethSignatureOf(name, types)
+	if err != nil {
+		return nil, err
+	}
+	log := &EthLog{
+		Address: el.Address().ID(),
+		Topics:  []common.HexBytes{keccak256([]byte(sig))},
+	}
+	for i, bs := range indexed[1:] {
+		t := types[i]
+		if ethIsDynamic(t) {
+			log.Topics = append(log.Topics, keccak256(bs))
+			continue
+		}
+		v, err := t.ConvertBytesToAny(bs)
+		if err != nil {
+			return nil, err
+		}
+		word, err := ethEncodeStatic(t, v)
+		if err != nil {
+			return nil, err
+		}
+		log.Topics = append(log.Topics, word)
+	}
+	dataTypes := types[len(indexed)-1:]
+	values := make([]any, len(data))
+	for i, bs := range data {
+		if values[i], err = dataTypes[i].ConvertBytesToAny(bs); err != nil {
+			return nil, err
+		}
+	}
+	if log.Data, err = ethEncode(dataTypes, values); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// ethValueOfJSON converts the value of the result of icx_call into the value
+// for ethEncode.
+func ethValueOfJSON(t scoreapi.DataType, jso any) (any, error) {
+	if jso == nil {
+		return nil, nil
+	}
+	s, ok := jso.(string)
+	if !ok {
+		return nil, errors.UnsupportedError.Errorf(
+			"UnsupportedValue(type=%s,value=%v)", t.String(), jso)
+	}
+	switch t.Tag() {
+	case scoreapi.TInteger:
+		v := new(big.Int)
+		if err := intconv.ParseBigInt(v, s); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidInteger(value=%q)", s)
+		}
+		return v, nil
+	case scoreapi.TBool:
+		return s == "0x1", nil
+	case scoreapi.TAddress:
+		return common.NewAddressFromString(s)
+	case scoreapi.TString:
+		return s, nil
+	case scoreapi.TBytes:
+		var bs common.HexBytes
+		if err := json.Unmarshal([]byte(`"`+s+`"`), &bs); err != nil {
+			return nil, errors.IllegalArgumentError.Wrapf(err, "InvalidBytes(value=%q)", s)
+		}
+		return []byte(bs), nil
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnsupportedType(type=%s)", t.String())
+	}
+}
+
+// ethParamOf converts the value decoded by ethDecode into the parameter of
+// icx_call. addressOf is used to find the address for the ID.
+func ethParamOf(t scoreapi.DataType, v any, addressOf func(id []byte) module.Address) (any, error) {
+	switch t.Tag() {
+	case scoreapi.TInteger:
+		return intconv.FormatBigInt(v.(*big.Int)), nil
+	case scoreapi.TBool:
+		if v.(bool) {
+			return "0x1", nil
+		}
+		return "0x0", nil
+	case scoreapi.TAddress:
+		return addressOf(v.([]byte)).String(), nil
+	case scoreapi.TString:
+		return v, nil
+	case scoreapi.TBytes:
+		return "0x" + hex.EncodeToString(v.([]byte)), nil
+	default:
+		return nil, errors.UnsupportedError.Errorf("UnsupportedType(type=%s)", t.String())
+	}
+}
+
+type scoreAPIParam struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type scoreAPIMethod struct {
+	Type     string          `json:"type"`
+	Name     string          `json:"name"`
+	Inputs   []scoreAPIParam `json:"inputs"`
+	Outputs  []scoreAPIParam `json:"outputs"`
+	ReadOnly string          `json:"readonly"`
+}
+
+// ethMethod is the read-only method of SCORE API which can be called with
+// ABI encoded data.
+type ethMethod struct {
+	name    string
+	inputs  []string
+	types   []scoreapi.DataType
+	outputs []scoreapi.DataType
+}
+
+func typesOfParams(params []scoreAPIParam) ([]scoreapi.DataType, bool) {
+	types := make([]scoreapi.DataType, len(params))
+	for i, p := range params {
+		types[i] = scoreapi.DataTypeOf(p.Type)
+		if _, err := ethTypeOf(types[i]); err != nil {
+			return nil, false
+		}
+	}
+	return types, true
+}
+
+// findEthMethod returns the read-only method for the selector in the API
+// of SCORE. It returns nil if there is no such method.
+func findEthMethod(info module.APIInfo, selector []byte) (*ethMethod, error) {
+	jso, err := info.ToJSON(module.JSONVersion3)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(jso)
+	if err != nil {
+		return nil, err
+	}
+	var methods []scoreAPIMethod
+	if err := json.Unmarshal(bs, &methods); err != nil {
+		return nil, err
+	}
+	for _, m := range methods {
+		if m.Type != "function" || m.ReadOnly != "0x1" {
+			continue
+		}
+		types, ok := typesOfParams(m.Inputs)
+		if !ok {
+			continue
+		}
+		outputs, ok := typesOfParams(m.Outputs)
+		if !ok {
+			continue
+		}
+		sig, _ := ethSignatureOf(m.Name, types)
+		if string(keccak256([]byte(sig))[:4]) != string(selector) {
+			continue
+		}
+		em := &ethMethod{
+			name:    m.Name,
+			types:   types,
+			outputs: outputs,
+		}
+		for _, p := range m.Inputs {
+			em.inputs = append(em.inputs, p.Name)
+		}
+		return em, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreapi"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	bs, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bs
+}
+
+// word returns the hex string of the word padded with zeros on the left.
+func word(s string) string {
+	for len(s) < 64 {
+		s = "0" + s
+	}
+	return s
+}
+
+type testAPIInfo []interface{}
+
+func (info testAPIInfo) ToJSON(module.JSONVersion) (interface{}, error) {
+	return []interface{}(info), nil
+}
+
+type testEventLog struct {
+	addr    module.Address
+	indexed [][]byte
+	data    [][]byte
+}
+
+func (l *testEventLog) Address() module.Address { return l.addr }
+func (l *testEventLog) Indexed() [][]byte       { return l.indexed }
+func (l *testEventLog) Data() [][]byte          { return l.data }
+
+func TestEthSignatureOf(t *testing.T) {
+	sig, err := ethSignatureOf("balanceOf", []scoreapi.DataType{scoreapi.Address})
+	assert.NoError(t, err)
+	assert.Equal(t, "balanceOf(address)", sig)
+	assert.Equal(t, mustHex(t, "70a08231"), keccak256([]byte(sig))[:4])
+
+	_, err = ethSignatureOf("f", []scoreapi.DataType{scoreapi.ListTypeOf(1, scoreapi.Integer)})
+	assert.Error(t, err)
+
+	name, types, err := parseEventSignature("Transfer(Address,Address,int,bytes)")
+	assert.NoError(t, err)
+	assert.Equal(t, "Transfer", name)
+	sig, err = ethSignatureOf(name, types)
+	assert.NoError(t, err)
+	assert.Equal(t, "Transfer(address,address,int256,bytes)", sig)
+
+	for _, s := range []string{"Transfer", "(int)", "Transfer(int", "Transfer(foo)"} {
+		_, _, err = parseEventSignature(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestEthEncodeInt(t *testing.T) {
+	for _, v := range []int64{0, 1, -1, 255, -256} {
+		bs, err := ethEncodeInt(big.NewInt(v))
+		assert.NoError(t, err)
+		assert.Len(t, bs, ethWordSize)
+		assert.Zero(t, big.NewInt(v).Cmp(ethDecodeInt(bs)), v)
+	}
+	bs, _ := ethEncodeInt(big.NewInt(-1))
+	assert.Equal(t, bytes.Repeat([]byte{0xff}, ethWordSize), bs)
+
+	_, err := ethEncodeInt(new(big.Int).Add(ethMaxInt256, big.NewInt(1)))
+	assert.Error(t, err)
+	_, err = ethEncodeInt(new(big.Int).Sub(ethMinInt256, big.NewInt(1)))
+	assert.Error(t, err)
+}
+
+func TestEthEncodeDecode(t *testing.T) {
+	addr := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	types := []scoreapi.DataType{
+		scoreapi.Integer, scoreapi.String, scoreapi.Bool, scoreapi.Address, scoreapi.Bytes,
+	}
+	values := []any{big.NewInt(7), "hello", true, addr, []byte{1, 2}}
+	data, err := ethEncode(types, values)
+	assert.NoError(t, err)
+	expected := word("7") +
+		word("a0") +
+		word("1") +
+		word("1") +
+		word("e0") +
+		word("5") + "68656c6c6f" + word("")[10:] +
+		word("2") + "0102" + word("")[4:]
+	assert.Equal(t, expected, hex.EncodeToString(data))
+
+	decoded, err := ethDecode(types, data)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{big.NewInt(7), "hello", true, addr.ID(), []byte{1, 2}}, decoded)
+
+	// the last bytes value is truncated
+	_, err = ethDecode(types, data[:len(data)-ethWordSize+1])
+	assert.Error(t, err)
+	_, err = ethDecode(types, data[:ethWordSize*2])
+	assert.Error(t, err)
+}
+
+func TestNewEthLog(t *testing.T) {
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+	from := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	el := &testEventLog{
+		addr: score,
+		indexed: [][]byte{
+			[]byte("Transfer(Address,Address,int,bytes)"),
+			from.Bytes(),
+			score.Bytes(),
+		},
+		data: [][]byte{{0x10}, []byte("memo")},
+	}
+	log, err := NewEthLog(el)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexBytes(score.ID()), log.Address)
+	assert.Len(t, log.Topics, 3)
+	assert.Equal(t, common.HexBytes(keccak256([]byte("Transfer(address,address,int256,bytes)"))), log.Topics[0])
+	assert.Equal(t, word(hex.EncodeToString(from.ID())), hex.EncodeToString(log.Topics[1]))
+	assert.Equal(t, word(hex.EncodeToString(score.ID())), hex.EncodeToString(log.Topics[2]))
+	assert.Equal(t,
+		word("10")+word("40")+word("4")+"6d656d6f"+word("")[8:],
+		hex.EncodeToString(log.Data))
+
+	// indexed dynamic values are hashed
+	el = &testEventLog{
+		addr:    score,
+		indexed: [][]byte{[]byte("Message(str)"), []byte("hello")},
+	}
+	log, err = NewEthLog(el)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexBytes(keccak256([]byte("hello"))), log.Topics[1])
+	assert.Equal(t, common.HexBytes{}, log.Data)
+
+	// mismatched number of values
+	el = &testEventLog{
+		addr:    score,
+		indexed: [][]byte{[]byte("Message(str,int)"), []byte("hello")},
+	}
+	_, err = NewEthLog(el)
+	assert.Error(t, err)
+
+	// unsupported types
+	el = &testEventLog{
+		addr:    score,
+		indexed: [][]byte{[]byte("Values([]int)")},
+		data:    [][]byte{nil},
+	}
+	_, err = NewEthLog(el)
+	assert.Error(t, err)
+}
+
+func TestFindEthMethod(t *testing.T) {
+	info := testAPIInfo{
+		map[string]interface{}{
+			"type":     "function",
+			"name":     "balanceOf",
+			"inputs":   []interface{}{map[string]interface{}{"name": "_owner", "type": "Address"}},
+			"outputs":  []interface{}{map[string]interface{}{"type": "int"}},
+			"readonly": "0x1",
+		},
+		map[string]interface{}{
+			"type":    "function",
+			"name":    "transfer",
+			"inputs":  []interface{}{map[string]interface{}{"name": "_to", "type": "Address"}},
+			"outputs": []interface{}{},
+		},
+		map[string]interface{}{
+			"type":     "function",
+			"name":     "name",
+			"inputs":   []interface{}{},
+			"outputs":  []interface{}{map[string]interface{}{"type": "str"}},
+			"readonly": "0x1",
+		},
+	}
+	m, err := findEthMethod(info, mustHex(t, "70a08231"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "balanceOf", m.name)
+		assert.Equal(t, []string{"_owner"}, m.inputs)
+		assert.Equal(t, []scoreapi.DataType{scoreapi.Address}, m.types)
+		assert.Equal(t, []scoreapi.DataType{scoreapi.Integer}, m.outputs)
+	}
+
+	m, err = findEthMethod(info, mustHex(t, "06fdde03"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, m) {
+		assert.Equal(t, "name", m.name)
+	}
+
+	// writable methods can't be called
+	sig, _ := ethSignatureOf("transfer", []scoreapi.DataType{scoreapi.Address})
+	m, err = findEthMethod(info, keccak256([]byte(sig))[:4])
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestEthValueAndParam(t *testing.T) {
+	v, err := ethValueOfJSON(scoreapi.Integer, "-0x10")
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(-16), v)
+	v, err = ethValueOfJSON(scoreapi.Bool, "0x1")
+	assert.NoError(t, err)
+	assert.Equal(t, true, v)
+	v, err = ethValueOfJSON(scoreapi.Bytes, "0x0102")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, v)
+	_, err = ethValueOfJSON(scoreapi.Integer, map[string]interface{}{})
+	assert.Error(t, err)
+
+	id := make([]byte, common.AddressIDBytes)
+	id[19] = 1
+	addressOf := func(id []byte) module.Address {
+		return common.NewContractAddress(id)
+	}
+	p, err := ethParamOf(scoreapi.Address, id, addressOf)
+	assert.NoError(t, err)
+	assert.Equal(t, "cx0000000000000000000000000000000000000001", p)
+	p, err = ethParamOf(scoreapi.Integer, big.NewInt(-16), addressOf)
+	assert.NoError(t, err)
+	assert.Equal(t, "-0x10", p)
+	p, err = ethParamOf(scoreapi.Bool, false, addressOf)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0", p)
+	p, err = ethParamOf(scoreapi.Bytes, []byte{1, 2}, addressOf)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x0102", p)
+}
+
+func TestEthHeightOf(t *testing.T) {
+	for _, tc := range []struct {
+		tag    string
+		height int64
+		ok     bool
+	}{
+		{"", 10, true},
+		{"latest", 10, true},
+		{"finalized", 10, true},
+		{"earliest", 2, true},
+		{"0x5", 5, true},
+		{"5", 0, false},
+		{"0xz", 0, false},
+		{"unknown", 0, false},
+	} {
+		h, err := EthHeightOf(tc.tag, 10, 2)
+		if tc.ok {
+			assert.NoError(t, err, tc.tag)
+			assert.Equal(t, tc.height, h, tc.tag)
+		} else {
+			assert.Error(t, err, tc.tag)
+		}
+	}
+}
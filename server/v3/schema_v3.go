@@ -1,6 +1,9 @@
 package v3
 
 import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
 	"github.com/icon-project/goloop/common/intconv"
 	"github.com/icon-project/goloop/server/jsonrpc"
 )
@@ -47,6 +50,17 @@ type TransactionHashParam struct {
 	Hash jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
 }
 
+type TraceTransactionParam struct {
+	Hash   jsonrpc.HexBytes `json:"txHash" validate:"required,t_hash"`
+	Tracer string           `json:"tracer,omitempty"`
+}
+
+type TraceBlockParam struct {
+	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,t_int"`
+	Hash   jsonrpc.HexBytes `json:"hash,omitempty" validate:"optional,t_hash"`
+	Tracer string           `json:"tracer,omitempty"`
+}
+
 type TransactionParamForEstimate struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -59,6 +73,29 @@ type TransactionParamForEstimate struct {
 	Data        interface{}     `json:"data,omitempty"`
 }
 
+type AccountOverrideParam struct {
+	Address     jsonrpc.Address            `json:"address" validate:"required,t_addr"`
+	Balance     jsonrpc.HexInt             `json:"balance,omitempty" validate:"optional,t_int"`
+	Owner       jsonrpc.Address            `json:"owner,omitempty" validate:"optional,t_addr_eoa"`
+	ContentType string                     `json:"contentType,omitempty"`
+	Content     common.HexBytes            `json:"content,omitempty"`
+	Params      json.RawMessage            `json:"params,omitempty"`
+	Storage     map[string]common.HexBytes `json:"storage,omitempty"`
+}
+
+type SimulateTransactionsParam struct {
+	Height       jsonrpc.HexInt                `json:"height,omitempty" validate:"optional,t_int"`
+	Transactions []TransactionParamForEstimate `json:"transactions" validate:"required,gt=0,dive"`
+	Overrides    []AccountOverrideParam        `json:"stateOverrides,omitempty" validate:"optional,dive"`
+}
+
+type PendingTransactionsParam struct {
+	Group string          `json:"group,omitempty"`
+	From  jsonrpc.Address `json:"from,omitempty" validate:"optional,t_addr_eoa"`
+	Skip  jsonrpc.HexInt  `json:"skip,omitempty" validate:"optional,t_int"`
+	Limit jsonrpc.HexInt  `json:"limit,omitempty" validate:"optional,t_int"`
+}
+
 type TransactionParam struct {
 	Version     jsonrpc.HexInt  `json:"version" validate:"required,t_int"`
 	FromAddress jsonrpc.Address `json:"from" validate:"required,t_addr_eoa"`
@@ -94,6 +131,23 @@ type RosettaTraceParam struct {
 	Height jsonrpc.HexInt   `json:"height,omitempty" validate:"optional,gte=0,t_int"`
 }
 
+type StateDiffParam struct {
+	From    jsonrpc.HexInt  `json:"from" validate:"required,t_int"`
+	To      jsonrpc.HexInt  `json:"to,omitempty" validate:"optional,t_int"`
+	Storage jsonrpc.HexBool `json:"storage,omitempty" validate:"optional,t_bool"`
+}
+
+type TransactionCursor struct {
+	BlockHeight jsonrpc.HexInt `json:"blockHeight" validate:"required,t_int"`
+	TxIndex     jsonrpc.HexInt `json:"txIndex" validate:"required,t_int"`
+}
+
+type TransactionsByAddressParam struct {
+	Address jsonrpc.Address    `json:"address" validate:"required,t_addr"`
+	Limit   jsonrpc.HexInt     `json:"limit,omitempty" validate:"optional,t_int"`
+	Cursor  *TransactionCursor `json:"cursor,omitempty"`
+}
+
 type BTPQueryParam struct {
 	Height jsonrpc.HexInt `json:"height,omitempty" validate:"optional,t_int"`
 	Id     jsonrpc.HexInt `json:"id" validate:"required,t_int"`
@@ -19,6 +19,7 @@ type traceCallback struct {
 	ts      time.Time
 	channel chan interface{}
 	bt      *trace.BalanceTracer
+	ct      *trace.CallTracer
 }
 
 type traceLog struct {
@@ -88,12 +89,27 @@ func (t *traceCallback) balanceChangeToJSON(blk module.Block) interface{} {
 	return result
 }
 
+func (t *traceCallback) callTraceToJSON() ([]interface{}, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.last != nil {
+		return nil, t.last
+	}
+	return t.ct.ToJSON(), nil
+}
+
 func (t *traceCallback) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
 	if t.bt != nil {
 		t.lock.Lock()
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionStart(txIndex, txHash, isBlockTx)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionStart(txIndex, txHash, isBlockTx)
+	}
 	return nil
 }
 
@@ -105,6 +121,9 @@ func (t *traceCallback) OnTransactionReset() error {
 	if t.bt != nil {
 		return t.bt.OnTransactionReset()
 	}
+	if t.ct != nil {
+		return t.ct.OnTransactionReset()
+	}
 	return nil
 }
 
@@ -114,6 +133,11 @@ func (t *traceCallback) OnTransactionEnd(txIndex int, txHash []byte) error {
 		defer t.lock.Unlock()
 		return t.bt.OnTransactionEnd(txIndex, txHash)
 	}
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnTransactionEnd(txIndex, txHash)
+	}
 	return nil
 }
 
@@ -143,3 +167,30 @@ func (t *traceCallback) OnBalanceChange(opType module.OpType, from, to module.Ad
 	}
 	return nil
 }
+
+func (t *traceCallback) OnCallEnter(call *module.TraceCall) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallEnter(call)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnCallExit(status error, stepUsed *big.Int, result interface{}, addr module.Address) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallExit(status, stepUsed, result, addr)
+	}
+	return nil
+}
+
+func (t *traceCallback) OnCallEvent(addr module.Address, indexed, data [][]byte) error {
+	if t.ct != nil {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return t.ct.OnCallEvent(addr, indexed, data)
+	}
+	return nil
+}
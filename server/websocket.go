@@ -29,9 +29,10 @@ type WebSocketUpgrader interface {
 }
 
 type wsSession struct {
-	lock  sync.Mutex
-	c     WebSocketConn
-	chain module.Chain
+	lock   sync.Mutex
+	c      WebSocketConn
+	chain  module.Chain
+	client string
 }
 
 type wsSessionManager struct {
@@ -63,15 +64,34 @@ func newWSSessionManagerWithUpgrader(logger log.Logger, maxSession int, upgrader
 }
 
 func (wm *wsSessionManager) NewSession(c WebSocketConn, chain module.Chain) *wsSession {
+	wss, _ := wm.NewSessionForClient(c, chain, "", 0)
+	return wss
+}
+
+// NewSessionForClient registers a new session of the client. If quota is
+// positive, it limits the number of the sessions of the client.
+// It returns nil with the reason if there are too many sessions.
+func (wm *wsSessionManager) NewSessionForClient(c WebSocketConn, chain module.Chain, client string, quota int) (*wsSession, string) {
 	wm.Lock()
 	defer wm.Unlock()
 
 	if len(wm.sessions) >= wm.maxSession {
-		return nil
+		return nil, wsReasonTooManySessions
+	}
+	if quota > 0 {
+		cnt := 0
+		for _, wss := range wm.sessions {
+			if wss.client == client {
+				cnt++
+			}
+		}
+		if cnt >= quota {
+			return nil, wsReasonClientQuota
+		}
 	}
-	wss := &wsSession{c: c, chain: chain}
+	wss := &wsSession{c: c, chain: chain, client: client}
 	wm.sessions = append(wm.sessions, wss)
-	return wss
+	return wss, ""
 }
 
 func (wm *wsSessionManager) stopSessionAt(i int) {
@@ -159,15 +179,24 @@ func (wm *wsSessionManager) initSession(ctx echo.Context, reqPtr interface{}) (*
 		return nil, err
 	}
 
-	wss := wm.NewSession(c, chain)
+	var client string
+	var quota int
+	rc, _ := ctx.Get("rateLimiter").(*rateLimitClient)
+	if rc != nil {
+		client, quota = rc.SessionQuota()
+	}
+	wss, reason := wm.NewSessionForClient(c, chain, client, quota)
 	if wss == nil {
+		if rc != nil && reason == wsReasonClientQuota {
+			rc.OnRejectSession()
+		}
 		wsResponse := WSResponse{
 			Code:    int(jsonrpc.ErrorLackOfResource),
-			Message: "too many monitor",
+			Message: reason,
 		}
 		c.WriteJSON(&wsResponse)
 		c.Close()
-		return nil, errors.New("too many monitor")
+		return nil, errors.New(reason)
 	}
 	return wss, nil
 }
@@ -228,6 +257,11 @@ const (
 	DefaultWSMaxReadLimit = 16 * 1024 // 16kB
 )
 
+const (
+	wsReasonTooManySessions = "too many monitor"
+	wsReasonClientQuota     = "too many monitor for the client"
+)
+
 type WSResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message,omitempty"`
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	DefaultWSConsensusBufferSize = 256
+)
+
+type ConsensusRequest struct {
+}
+
+type ConsensusNotification struct {
+	Height    common.HexInt64 `json:"height"`
+	Round     common.HexInt32 `json:"round"`
+	Step      string          `json:"step"`
+	Proposer  *common.Address `json:"proposer,omitempty"`
+	Timestamp common.HexInt64 `json:"timestamp"`
+}
+
+func NewConsensusNotification(ev *module.ConsensusStepEvent) *ConsensusNotification {
+	return &ConsensusNotification{
+		Height:    common.HexInt64{Value: ev.Height},
+		Round:     common.HexInt32{Value: ev.Round},
+		Step:      ev.Step,
+		Proposer:  common.AddressToPtr(ev.Proposer),
+		Timestamp: common.HexInt64{Value: ev.Timestamp.UnixMicro()},
+	}
+}
+
+func (wm *wsSessionManager) RunConsensusSession(ctx echo.Context) error {
+	var cr ConsensusRequest
+	wss, err := wm.initSession(ctx, &cr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	cm, ok := wss.chain.Consensus().(module.ConsensusMonitor)
+	if !ok {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	ch := make(chan *module.ConsensusStepEvent, DefaultWSConsensusBufferSize)
+	cancel, err := cm.WatchSteps(ch)
+	if err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), err.Error())
+		return nil
+	}
+	defer cancel()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case ev, ok := <-ch:
+			if !ok {
+				_ = wss.response(int(jsonrpc.ErrorLackOfResource), "too slow to receive notifications or consensus stopped")
+				err = errors.New("ConsensusNotificationClosed")
+				break loop
+			}
+			if err = wss.WriteJSON(NewConsensusNotification(ev)); err != nil {
+				wm.logger.Infof("fail to write json ConsensusNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
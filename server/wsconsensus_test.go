@@ -0,0 +1,78 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestNewConsensusNotification(t *testing.T) {
+	proposer := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	ev := &module.ConsensusStepEvent{
+		Height:    0x10,
+		Round:     2,
+		Step:      "prevoteWait",
+		Proposer:  proposer,
+		Timestamp: time.UnixMicro(0x5f3b8c3b2a2c0),
+	}
+	bs, err := json.Marshal(NewConsensusNotification(ev))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"height": "0x10",
+		"round": "0x2",
+		"step": "prevoteWait",
+		"proposer": "hx0000000000000000000000000000000000000001",
+		"timestamp": "0x5f3b8c3b2a2c0"
+	}`, string(bs))
+
+	ev.Proposer = nil
+	bs, err = json.Marshal(NewConsensusNotification(ev))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(bs), "proposer")
+}
+
+func TestConsensusSession_Debug(t *testing.T) {
+	srv := NewManager(&Config{}, nil, log.New())
+	srv.RegisterAPIHandler(srv.e.Group("/api"))
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v3/test/consensus", nil)
+		rec := httptest.NewRecorder()
+		srv.e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get()
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "debug API is disabled", rec.Body.String())
+
+	// it reaches the channel check with debug enabled
+	srv.SetIncludeDebug(true)
+	rec = get()
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "No channel", rec.Body.String())
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/server/jsonrpc"
+)
+
+const (
+	DefaultWSPendingBufferSize = 1024
+)
+
+type PendingRequest struct {
+	From     *common.Address `json:"from,omitempty"`
+	To       *common.Address `json:"to,omitempty"`
+	DataType *string         `json:"dataType,omitempty"`
+	Events   []string        `json:"events,omitempty"`
+	Full     common.HexBool  `json:"full,omitempty"`
+
+	events map[module.PendingTxEvent]bool
+}
+
+type PendingNotification struct {
+	Event       string          `json:"event"`
+	Hash        common.HexBytes `json:"hash"`
+	Reason      string          `json:"reason,omitempty"`
+	Transaction interface{}     `json:"transaction,omitempty"`
+}
+
+var pendingTxEvents = []module.PendingTxEvent{
+	module.PendingTxAdded,
+	module.PendingTxRemoved,
+	module.PendingTxDropped,
+}
+
+func (r *PendingRequest) Compile() error {
+	r.events = make(map[module.PendingTxEvent]bool)
+	if len(r.Events) == 0 {
+		for _, ev := range pendingTxEvents {
+			r.events[ev] = true
+		}
+		return nil
+	}
+	for _, name := range r.Events {
+		found := false
+		for _, ev := range pendingTxEvents {
+			if ev.String() == name {
+				r.events[ev] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.IllegalArgumentError.Errorf("UnknownEvent(name=%s)", name)
+		}
+	}
+	return nil
+}
+
+type txWithTo interface {
+	To() module.Address
+}
+
+// Match returns whether the transaction of the notification passes the
+// filters of the request. It also returns JSON of the transaction if it's
+// made for the matching.
+func (r *PendingRequest) Match(ptx *module.PendingTransaction) (bool, interface{}, error) {
+	if !r.events[ptx.Event] {
+		return false, nil, nil
+	}
+	tx := ptx.Tx
+	if r.From != nil && !r.From.Equal(tx.From()) {
+		return false, nil, nil
+	}
+	if r.To != nil {
+		if ttx, ok := tx.(txWithTo); !ok || !r.To.Equal(ttx.To()) {
+			return false, nil, nil
+		}
+	}
+	var jso interface{}
+	if r.DataType != nil {
+		var err error
+		if jso, err = tx.ToJSON(module.JSONVersion3); err != nil {
+			return false, nil, err
+		}
+		m, ok := jso.(map[string]interface{})
+		if !ok {
+			return false, nil, nil
+		}
+		if dt, _ := m["dataType"].(string); dt != *r.DataType {
+			return false, nil, nil
+		}
+	}
+	return true, jso, nil
+}
+
+func (r *PendingRequest) notificationOf(ptx *module.PendingTransaction, jso interface{}) (*PendingNotification, error) {
+	pn := &PendingNotification{
+		Event: ptx.Event.String(),
+		Hash:  ptx.Tx.ID(),
+	}
+	if ptx.Err != nil {
+		pn.Reason = ptx.Err.Error()
+	}
+	if r.Full.Value {
+		if jso == nil {
+			var err error
+			if jso, err = ptx.Tx.ToJSON(module.JSONVersion3); err != nil {
+				return nil, err
+			}
+		}
+		pn.Transaction = jso
+	}
+	return pn, nil
+}
+
+func (wm *wsSessionManager) RunPendingSession(ctx echo.Context) error {
+	var pr PendingRequest
+	wss, err := wm.initSession(ctx, &pr)
+	if err != nil {
+		return err
+	}
+	defer wm.StopSession(wss)
+
+	if err := pr.Compile(); err != nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeInvalidParams), err.Error())
+		return nil
+	}
+
+	sm := wss.chain.ServiceManager()
+	if sm == nil {
+		_ = wss.response(int(jsonrpc.ErrorCodeServer), "Stopped")
+		return nil
+	}
+
+	ch := make(chan *module.PendingTransaction, DefaultWSPendingBufferSize)
+	cancel := sm.WatchPendingTransactions(ch)
+	defer cancel()
+
+	_ = wss.response(0, "")
+
+	ech := make(chan error, 1)
+	wss.RunLoop(ech)
+
+loop:
+	for {
+		select {
+		case err = <-ech:
+			break loop
+		case ptx, ok := <-ch:
+			if !ok {
+				_ = wss.response(int(jsonrpc.ErrorLackOfResource), "too slow to receive notifications")
+				err = errors.New("PendingNotificationOverflow")
+				break loop
+			}
+			matched, jso, err2 := pr.Match(ptx)
+			if err2 != nil || !matched {
+				break
+			}
+			pn, err2 := pr.notificationOf(ptx, jso)
+			if err2 != nil {
+				wm.logger.Infof("fail to make PendingNotification err:%+v\n", err2)
+				break
+			}
+			if err = wss.WriteJSON(pn); err != nil {
+				wm.logger.Infof("fail to write json PendingNotification err:%+v\n", err)
+				break loop
+			}
+		}
+	}
+	wm.logger.Warnf("%+v\n", err)
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+)
+
+type testPendingTransaction struct {
+	module.Transaction
+	id       []byte
+	from, to module.Address
+	dataType string
+}
+
+func (tx *testPendingTransaction) ID() []byte {
+	return tx.id
+}
+
+func (tx *testPendingTransaction) From() module.Address {
+	return tx.from
+}
+
+func (tx *testPendingTransaction) To() module.Address {
+	return tx.to
+}
+
+func (tx *testPendingTransaction) ToJSON(version module.JSONVersion) (interface{}, error) {
+	jso := map[string]interface{}{
+		"from":   tx.from,
+		"to":     tx.to,
+		"txHash": common.HexBytes(tx.id),
+	}
+	if len(tx.dataType) > 0 {
+		jso["dataType"] = tx.dataType
+	}
+	return jso, nil
+}
+
+func TestPendingRequest_Compile(t *testing.T) {
+	var pr PendingRequest
+	assert.NoError(t, json.Unmarshal([]byte(`{}`), &pr))
+	assert.NoError(t, pr.Compile())
+	assert.Len(t, pr.events, 3)
+
+	pr = PendingRequest{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"events":["added","dropped"]}`), &pr))
+	assert.NoError(t, pr.Compile())
+	assert.True(t, pr.events[module.PendingTxAdded])
+	assert.False(t, pr.events[module.PendingTxRemoved])
+	assert.True(t, pr.events[module.PendingTxDropped])
+
+	pr = PendingRequest{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"events":["unknown"]}`), &pr))
+	assert.Error(t, pr.Compile())
+}
+
+func TestPendingRequest_Match(t *testing.T) {
+	eoa1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	eoa2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+	transfer := &testPendingTransaction{id: []byte("tx1"), from: eoa1, to: eoa2}
+	call := &testPendingTransaction{id: []byte("tx2"), from: eoa2, to: score, dataType: "call"}
+
+	tests := []struct {
+		name    string
+		request string
+		ptx     *module.PendingTransaction
+		want    bool
+	}{
+		{"NoFilter", `{}`, &module.PendingTransaction{Tx: transfer}, true},
+		{"From", `{"from":"hx0000000000000000000000000000000000000001"}`,
+			&module.PendingTransaction{Tx: transfer}, true},
+		{"FromMismatch", `{"from":"hx0000000000000000000000000000000000000001"}`,
+			&module.PendingTransaction{Tx: call}, false},
+		{"To", `{"to":"cx0000000000000000000000000000000000000003"}`,
+			&module.PendingTransaction{Tx: call}, true},
+		{"ToMismatch", `{"to":"cx0000000000000000000000000000000000000003"}`,
+			&module.PendingTransaction{Tx: transfer}, false},
+		{"DataType", `{"dataType":"call"}`,
+			&module.PendingTransaction{Tx: call}, true},
+		{"DataTypeMismatch", `{"dataType":"call"}`,
+			&module.PendingTransaction{Tx: transfer}, false},
+		{"NoDataType", `{"dataType":""}`,
+			&module.PendingTransaction{Tx: transfer}, true},
+		{"Event", `{"events":["removed"]}`,
+			&module.PendingTransaction{Event: module.PendingTxRemoved, Tx: call}, true},
+		{"EventMismatch", `{"events":["removed"]}`,
+			&module.PendingTransaction{Event: module.PendingTxAdded, Tx: call}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pr PendingRequest
+			assert.NoError(t, json.Unmarshal([]byte(tt.request), &pr))
+			assert.NoError(t, pr.Compile())
+			matched, _, err := pr.Match(tt.ptx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, matched)
+		})
+	}
+}
+
+func TestPendingRequest_notificationOf(t *testing.T) {
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	tx := &testPendingTransaction{id: []byte("tx1"), from: eoa, to: eoa}
+	ptx := &module.PendingTransaction{Event: module.PendingTxAdded, Tx: tx}
+
+	pr := &PendingRequest{}
+	pn, err := pr.notificationOf(ptx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "added", pn.Event)
+	assert.Equal(t, common.HexBytes("tx1"), pn.Hash)
+	assert.Nil(t, pn.Transaction)
+
+	pr.Full.Value = true
+	pn, err = pr.notificationOf(ptx, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, pn.Transaction)
+}
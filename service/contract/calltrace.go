@@ -0,0 +1,106 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contract
+
+import (
+	"encoding/json"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	TraceCallTypeTransfer = "transfer"
+	TraceCallTypeCall     = "call"
+	TraceCallTypeDeploy   = "deploy"
+	TraceCallTypeDeposit  = "deposit"
+	TraceCallTypeSystem   = "system"
+)
+
+// traceCallHandler is implemented by the handlers embedding CommonHandler.
+type traceCallHandler interface {
+	traceCall(t string) *module.TraceCall
+}
+
+// traceCallOf returns the information of the call handled by the handler
+// for TraceModeCallTree.
+func traceCallOf(handler ContractHandler) *module.TraceCall {
+	switch h := handler.(type) {
+	case *TransferAndCallHandler:
+		if !h.To.IsContract() {
+			return h.traceCall(TraceCallTypeTransfer)
+		}
+		return traceCallOfCallHandler(h.CallHandler)
+	case *CallHandler:
+		return traceCallOfCallHandler(h)
+	case *TransferHandler:
+		return h.traceCall(TraceCallTypeTransfer)
+	case *DeployHandler:
+		call := h.traceCall(TraceCallTypeDeploy)
+		call.Params = paramsForTrace(h.params)
+		return call
+	case *DepositHandler:
+		call := h.traceCall(TraceCallTypeDeposit)
+		if h.data != nil {
+			call.Method = h.data.Action
+		}
+		return call
+	case traceCallHandler:
+		return h.traceCall(TraceCallTypeSystem)
+	default:
+		return &module.TraceCall{Type: TraceCallTypeSystem}
+	}
+}
+
+func (h *CommonHandler) traceCall(t string) *module.TraceCall {
+	return &module.TraceCall{
+		Type:  t,
+		From:  h.From,
+		To:    h.To,
+		Value: h.Value,
+	}
+}
+
+func traceCallOfCallHandler(h *CallHandler) *module.TraceCall {
+	call := h.traceCall(TraceCallTypeCall)
+	call.Method = h.name
+	if h.paramObj != nil {
+		call.Params = resultForTrace(h.paramObj)
+	} else {
+		call.Params = paramsForTrace(h.params)
+	}
+	return call
+}
+
+func paramsForTrace(params []byte) interface{} {
+	if len(params) == 0 || !json.Valid(params) {
+		return nil
+	}
+	return json.RawMessage(params)
+}
+
+func resultForTrace(obj *codec.TypedObj) interface{} {
+	if obj == nil {
+		return nil
+	}
+	value, err := common.DecodeAnyForJSON(obj)
+	if err != nil {
+		return nil
+	}
+	return value
+}
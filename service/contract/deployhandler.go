@@ -180,6 +180,27 @@ func NewDeployHandlerForPreInstall(owner, scoreAddr module.Address, contentType
 	}
 }
 
+// NewDeployHandlerForOverride returns a handler deploying the content to
+// the address by the owner regardless of the deployer white list and the
+// audit. It's used to override the code of the account in simulation.
+func NewDeployHandlerForOverride(owner, scoreAddr module.Address, update bool,
+	contentType string, content []byte, params []byte, log log.Logger,
+) *DeployHandler {
+	var to module.Address = state.SystemAddress
+	if update {
+		to = scoreAddr
+	}
+	eeType, _ := state.EETypeFromContentType(contentType)
+	return &DeployHandler{
+		CommonHandler:  NewCommonHandler(owner, to, new(big.Int), false, log),
+		content:        &ContentBytes{Bytes: content},
+		contentType:    contentType,
+		preDefinedAddr: scoreAddr,
+		eeType:         eeType,
+		params:         params,
+	}
+}
+
 // genContractAddr generate new contract address
 // nonce, timestamp, from
 // data = from(20 bytes) + timestamp (32 bytes) + if exists, nonce (32 bytes)
@@ -19,6 +19,7 @@ package contract
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/icon-project/goloop/common/containerdb"
 	"github.com/icon-project/goloop/common/intconv"
@@ -35,8 +36,40 @@ const (
 
 	EventMaxStepLimitSet = "MaxStepLimitSet(str,int)"
 	EventTimestampThresholdSet = "TimestampThresholdSet(int)"
+	EventConsensusTimeoutSet   = "ConsensusTimeoutSet(str,int)"
 )
 
+const (
+	ConsensusTimeoutPropose        = "propose"
+	ConsensusTimeoutProposeDelta   = "proposeDelta"
+	ConsensusTimeoutPrevote        = "prevote"
+	ConsensusTimeoutPrevoteDelta   = "prevoteDelta"
+	ConsensusTimeoutPrecommit      = "precommit"
+	ConsensusTimeoutPrecommitDelta = "precommitDelta"
+	ConsensusTimeoutNewRound       = "newRound"
+	ConsensusTimeoutNewRoundDelta  = "newRoundDelta"
+)
+
+// Ranges of consensus timeouts in milliseconds. Zero is also allowed
+// for all of them to use the default.
+const (
+	ConsensusTimeoutMin      = 100
+	ConsensusTimeoutMax      = 60_000
+	ConsensusTimeoutDeltaMin = 1
+	ConsensusTimeoutDeltaMax = 10_000
+)
+
+var AllConsensusTimeouts = []string{
+	ConsensusTimeoutPropose,
+	ConsensusTimeoutProposeDelta,
+	ConsensusTimeoutPrevote,
+	ConsensusTimeoutPrevoteDelta,
+	ConsensusTimeoutPrecommit,
+	ConsensusTimeoutPrecommitDelta,
+	ConsensusTimeoutNewRound,
+	ConsensusTimeoutNewRoundDelta,
+}
+
 func GetRevision(cc CallContext) int {
 	as := cc.GetAccountState(state.SystemID)
 	return int(scoredb.NewVarDB(as, state.VarRevision).Int64())
@@ -251,3 +284,124 @@ func SetTimestampThreshold(cc CallContext, value int64) (bool, error) {
 	}
 	return true, nil
 }
+
+func IsValidConsensusTimeout(name string) bool {
+	for _, n := range AllConsensusTimeouts {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidConsensusTimeoutValue returns whether the value in milliseconds
+// is in the range of the timeout. Zero is valid for all timeouts.
+func IsValidConsensusTimeoutValue(name string, value int64) bool {
+	if value == 0 {
+		return true
+	}
+	switch name {
+	case ConsensusTimeoutPropose, ConsensusTimeoutPrevote,
+		ConsensusTimeoutPrecommit, ConsensusTimeoutNewRound:
+		return value >= ConsensusTimeoutMin && value <= ConsensusTimeoutMax
+	case ConsensusTimeoutProposeDelta, ConsensusTimeoutPrevoteDelta,
+		ConsensusTimeoutPrecommitDelta, ConsensusTimeoutNewRoundDelta:
+		return value >= ConsensusTimeoutDeltaMin && value <= ConsensusTimeoutDeltaMax
+	default:
+		return false
+	}
+}
+
+func consensusTimeoutOf(to *module.ConsensusTimeouts, name string) *time.Duration {
+	switch name {
+	case ConsensusTimeoutPropose:
+		return &to.Propose
+	case ConsensusTimeoutProposeDelta:
+		return &to.ProposeDelta
+	case ConsensusTimeoutPrevote:
+		return &to.Prevote
+	case ConsensusTimeoutPrevoteDelta:
+		return &to.PrevoteDelta
+	case ConsensusTimeoutPrecommit:
+		return &to.Precommit
+	case ConsensusTimeoutPrecommitDelta:
+		return &to.PrecommitDelta
+	case ConsensusTimeoutNewRound:
+		return &to.NewRound
+	case ConsensusTimeoutNewRoundDelta:
+		return &to.NewRoundDelta
+	default:
+		return nil
+	}
+}
+
+// ConsensusTimeoutsFromState returns consensus timeouts stored in the
+// system storage. It returns nil if none of them is set.
+func ConsensusTimeoutsFromState(as containerdb.BytesStoreState) *module.ConsensusTimeouts {
+	db := scoredb.NewDictDB(as, state.VarConsensusTimeouts, 1)
+	var to module.ConsensusTimeouts
+	found := false
+	for _, name := range AllConsensusTimeouts {
+		if v := db.Get(name); v != nil {
+			*consensusTimeoutOf(&to, name) = time.Duration(v.Int64()) * time.Millisecond
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &to
+}
+
+// GetConsensusTimeouts returns configured consensus timeouts in milliseconds.
+func GetConsensusTimeouts(cc CallContext) map[string]any {
+	timeouts := make(map[string]any)
+	as := cc.GetAccountState(state.SystemID)
+	db := scoredb.NewDictDB(as, state.VarConsensusTimeouts, 1)
+	for _, name := range AllConsensusTimeouts {
+		if v := db.Get(name); v != nil {
+			timeouts[name] = v.Int64()
+		}
+	}
+	return timeouts
+}
+
+// SetConsensusTimeout sets the timeout of a round step in milliseconds.
+// Zero value removes the setting, so the consensus uses its default.
+// The consensus reads timeouts from the result of the last block, so the
+// change is applied from the second height after the block including it.
+func SetConsensusTimeout(cc CallContext, name string, value int64) (bool, error) {
+	if !IsValidConsensusTimeout(name) {
+		return false, scoreresult.InvalidParameterError.Errorf("InvalidConsensusTimeout(name=%s)", name)
+	}
+	if !IsValidConsensusTimeoutValue(name, value) {
+		return false, scoreresult.InvalidParameterError.Errorf(
+			"InvalidConsensusTimeout(name=%s,value=%d)", name, value)
+	}
+	as := cc.GetAccountState(state.SystemID)
+	db := scoredb.NewDictDB(as, state.VarConsensusTimeouts, 1)
+	var old int64
+	if v := db.Get(name); v != nil {
+		old = v.Int64()
+	}
+	if old == value {
+		return false, nil
+	}
+	if value == 0 {
+		if err := db.Delete(name); err != nil {
+			return false, err
+		}
+	} else {
+		if err := db.Set(name, value); err != nil {
+			return false, err
+		}
+	}
+	if cc.Revision().Has(module.ReportConfigureEvents) {
+		cc.OnEvent(
+			state.SystemAddress,
+			[][]byte{[]byte(EventConsensusTimeoutSet)},
+			[][]byte{[]byte(name), intconv.Int64ToBytes(value)},
+		)
+	}
+	return true, nil
+}
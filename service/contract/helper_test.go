@@ -18,8 +18,10 @@
 package contract
 
 import (
+	"math"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -430,4 +432,93 @@ func TestTimestampThreshold(t *testing.T) {
 		nil, []any{intconv.BigIntZero},
 	))
 	cc.events = nil
-}
\ No newline at end of file
+}
+
+func TestConsensusTimeout(t *testing.T) {
+	cc := newFakeCallContext()
+	as := cc.GetAccountState(state.SystemID)
+
+	// initial is empty
+	assert.Empty(t, GetConsensusTimeouts(cc))
+	assert.Nil(t, ConsensusTimeoutsFromState(as))
+
+	// set with invalid name
+	ok, err := SetConsensusTimeout(cc, "commit", 500)
+	assert.Error(t, err)
+	assert.False(t, ok)
+
+	// set as negative (invalid)
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPropose, -100)
+	assert.Error(t, err)
+	assert.False(t, ok)
+
+	// set out of the range (invalid)
+	for _, tc := range []struct {
+		name  string
+		value int64
+	}{
+		{ConsensusTimeoutPropose, ConsensusTimeoutMin - 1},
+		{ConsensusTimeoutPropose, ConsensusTimeoutMax + 1},
+		{ConsensusTimeoutPrevoteDelta, ConsensusTimeoutDeltaMax + 1},
+		{ConsensusTimeoutNewRound, math.MaxInt64},
+	} {
+		ok, err = SetConsensusTimeout(cc, tc.name, tc.value)
+		assert.Error(t, err, "name=%s value=%d", tc.name, tc.value)
+		assert.False(t, ok)
+	}
+	assert.Empty(t, GetConsensusTimeouts(cc))
+
+	// set propose
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPropose, 500)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	cc.revision |= module.ReportConfigureEvents
+
+	// set prevoteDelta
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPrevoteDelta, 200)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, 1, len(cc.events))
+	assert.NoError(t, cc.events[0].Assert(
+		state.SystemAddress,
+		EventConsensusTimeoutSet,
+		nil, []any{ConsensusTimeoutPrevoteDelta, int64(200)},
+	))
+	cc.events = nil
+
+	assert.Equal(t, map[string]any{
+		ConsensusTimeoutPropose:      int64(500),
+		ConsensusTimeoutPrevoteDelta: int64(200),
+	}, GetConsensusTimeouts(cc))
+	assert.Equal(t, &module.ConsensusTimeouts{
+		Propose:      500 * time.Millisecond,
+		PrevoteDelta: 200 * time.Millisecond,
+	}, ConsensusTimeoutsFromState(as))
+
+	// set as same
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPropose, 500)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, len(cc.events))
+
+	// set as zero (delete)
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPropose, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, 1, len(cc.events))
+	assert.NoError(t, cc.events[0].Assert(
+		state.SystemAddress,
+		EventConsensusTimeoutSet,
+		nil, []any{ConsensusTimeoutPropose, intconv.BigIntZero},
+	))
+	cc.events = nil
+
+	ok, err = SetConsensusTimeout(cc, ConsensusTimeoutPrevoteDelta, 0)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, GetConsensusTimeouts(cc))
+	assert.Nil(t, ConsensusTimeoutsFromState(as))
+}
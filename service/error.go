@@ -15,6 +15,7 @@ const (
 	NotContractAddressError
 	InvalidPatchDataError
 	CommittedTransactionError
+	EvictedTransactionError
 )
 
 var (
@@ -24,4 +25,5 @@ var (
 	ErrTransitionInterrupted   = errors.NewBase(TransitionInterruptedError, "TransitionInterrupted")
 	ErrInvalidTransaction      = errors.NewBase(InvalidTransactionError, "InvalidTransaction")
 	ErrCommittedTransaction    = errors.NewBase(CommittedTransactionError, "CommittedTransaction")
+	ErrEvictedTransaction      = errors.NewBase(EvictedTransactionError, "EvictedTransaction")
 )
@@ -57,6 +57,7 @@ type manager struct {
 	syncer    *ssync.Manager
 	dsm       *dsrManager
 	lm        module.LocatorManager
+	ptw       *pendingTxWatchers
 
 	log log.Logger
 
@@ -97,6 +98,8 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 	pTxPool := NewTransactionPool(module.TransactionGroupPatch, chain.PatchTxPoolSize(), tim, pMetric, logger)
 	nTxPool := NewTransactionPool(module.TransactionGroupNormal, chain.NormalTxPoolSize(), tim, nMetric, logger)
 	tm := NewTransactionManager(chain.NID(), tsc, pTxPool, nTxPool, tim, logger)
+	ptw := new(pendingTxWatchers)
+	tm.SetPendingTxWatcher(ptw)
 	syncm := ssync.NewSyncManager(chain.Database(), chain.NetworkManager(), plt, logger)
 
 	mgr := &manager{
@@ -118,6 +121,7 @@ func NewManager(chain module.Chain, nm module.NetworkManager,
 		tim: tim,
 		dsm: dsm,
 		lm:  lm,
+		ptw: ptw,
 	}
 	if nm != nil {
 		mgr.txReactor = NewTransactionReactor(nm, tm)
@@ -445,6 +449,35 @@ func (m *manager) WaitTransactionResult(id []byte) (<-chan interface{}, error) {
 	return m.tm.WaitResult(id)
 }
 
+func (m *manager) WatchPendingTransactions(ch chan<- *module.PendingTransaction) func() {
+	return m.ptw.Watch(ch)
+}
+
+func (m *manager) GetPendingTransactions(
+	group module.TransactionGroup, from module.Address, skip, limit int,
+) ([]module.Transaction, int, error) {
+	if group != module.TransactionGroupNormal && group != module.TransactionGroupPatch {
+		return nil, 0, errors.IllegalArgumentError.Errorf("InvalidGroup(group=%d)", group)
+	}
+	if skip < 0 || limit < 0 {
+		return nil, 0, errors.IllegalArgumentError.Errorf(
+			"InvalidRange(skip=%d,limit=%d)", skip, limit)
+	}
+	txs, total := m.tm.PendingTransactions(group, from, skip, limit)
+	return txs, total, nil
+}
+
+func (m *manager) GetTxPoolStatus() ([]*module.TxPoolStatus, error) {
+	return []*module.TxPoolStatus{
+		m.tm.PoolStatus(module.TransactionGroupNormal),
+		m.tm.PoolStatus(module.TransactionGroupPatch),
+	}, nil
+}
+
+func (m *manager) EvictTransaction(id []byte) error {
+	return m.tm.Evict(id)
+}
+
 type worldContextWrapper struct {
 	state.WorldContext
 	height int64
@@ -712,6 +745,14 @@ func (m *manager) GetRoundLimit(result []byte, vl int) int64 {
 	return limit
 }
 
+func (m *manager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	as, err := m.getSystemByteStoreState(result)
+	if err != nil {
+		return nil
+	}
+	return contract.ConsensusTimeoutsFromState(as)
+}
+
 func (m *manager) GetMinimizeBlockGen(result []byte) bool {
 	as, err := m.getSystemByteStoreState(result)
 	if err != nil {
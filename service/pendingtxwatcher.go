@@ -0,0 +1,49 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+)
+
+type PendingTxWatcher interface {
+	OnPendingTx(ev module.PendingTxEvent, tx module.Transaction, err error)
+}
+
+type dummyPendingTxWatcher struct{}
+
+func (w dummyPendingTxWatcher) OnPendingTx(ev module.PendingTxEvent, tx module.Transaction, err error) {
+	// do nothing
+}
+
+// pendingTxWatchers delivers changes of the transaction pool to registered
+// channels.
+type pendingTxWatchers struct {
+	common.Watchers[*module.PendingTransaction]
+}
+
+func (ws *pendingTxWatchers) Watch(ch chan<- *module.PendingTransaction) func() {
+	cancel, _ := ws.Watchers.Watch(ch)
+	return cancel
+}
+
+func (ws *pendingTxWatchers) OnPendingTx(ev module.PendingTxEvent, tx module.Transaction, err error) {
+	ws.NotifyFunc(func() *module.PendingTransaction {
+		return &module.PendingTransaction{Event: ev, Tx: tx, Err: err}
+	})
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/txlocator"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestPendingTxWatchers(t *testing.T) {
+	dbase := db.NewMapDB()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tim, _ := NewTXIDManager(lm, NewTimestampChecker(), nil)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 5000, tim, &mockMonitor{}, logger)
+
+	ptw := new(pendingTxWatchers)
+	pool.SetPendingTxWatcher(ptw)
+
+	ch1 := make(chan *module.PendingTransaction, 1)
+	cancel1 := ptw.Watch(ch1)
+	ch2 := make(chan *module.PendingTransaction, 2)
+	cancel2 := ptw.Watch(ch2)
+	defer cancel2()
+
+	addr := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	tx1 := newMockTransaction([]byte("tx1"), addr, 1)
+	assert.NoError(t, pool.Add(tx1, true))
+
+	ptx := <-ch1
+	assert.Equal(t, module.PendingTxAdded, ptx.Event)
+	assert.Equal(t, tx1.ID(), ptx.Tx.ID())
+	ptx = <-ch2
+	assert.Equal(t, module.PendingTxAdded, ptx.Event)
+
+	// cancelled watcher gets no more notifications
+	cancel1()
+	tx2 := newMockTransaction([]byte("tx2"), addr, 2)
+	assert.NoError(t, pool.Add(tx2, true))
+	assert.Len(t, ch1, 0)
+	assert.Len(t, ch2, 1)
+
+	// full channel is closed
+	tx3 := newMockTransaction([]byte("tx3"), addr, 3)
+	assert.NoError(t, pool.Add(tx3, true))
+	tx4 := newMockTransaction([]byte("tx4"), addr, 4)
+	assert.NoError(t, pool.Add(tx4, true))
+	var ids [][]byte
+	for ptx := range ch2 {
+		ids = append(ids, ptx.Tx.ID())
+	}
+	assert.Equal(t, [][]byte{tx2.ID(), tx3.ID()}, ids)
+	assert.Equal(t, 0, ptw.Len())
+
+	// dropped transactions
+	ch3 := make(chan *module.PendingTransaction, 4)
+	cancel3 := ptw.Watch(ch3)
+	defer cancel3()
+	pool.DropOldTXs(2)
+	for _, id := range [][]byte{tx1.ID(), tx2.ID()} {
+		ptx := <-ch3
+		assert.Equal(t, module.PendingTxDropped, ptx.Event)
+		assert.Equal(t, id, ptx.Tx.ID())
+		assert.Error(t, ptx.Err)
+	}
+}
@@ -490,6 +490,23 @@ var chainMethods = []*chainMethod{
 			scoreapi.Bool,
 		},
 	}, Revision8, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "setConsensusTimeout",
+		scoreapi.FlagExternal, 2,
+		[]scoreapi.Parameter{
+			{"name", scoreapi.String, nil, nil},
+			{"timeout", scoreapi.Integer, nil, nil},
+		},
+		nil,
+	}, Revision9, 0},
+	{scoreapi.Method{
+		scoreapi.Function, "getConsensusTimeouts",
+		scoreapi.FlagReadOnly | scoreapi.FlagExternal, 0,
+		nil,
+		[]scoreapi.DataType{
+			scoreapi.Dict,
+		},
+	}, Revision9, 0},
 	{scoreapi.Method{
 		scoreapi.Function, "setUseSystemDeposit",
 		scoreapi.FlagExternal, 2,
@@ -602,16 +619,17 @@ type Chain struct {
 		StepLimit *json.RawMessage `json:"stepLimit"`
 		StepCosts *json.RawMessage `json:"stepCosts"`
 	} `json:"fee"`
-	ValidatorList      []*common.Address `json:"validatorList"`
-	MemberList         []*common.Address `json:"memberList"`
-	BlockInterval      *common.HexInt64  `json:"blockInterval"`
-	CommitTimeout      *common.HexInt64  `json:"commitTimeout"`
-	TimestampThreshold *common.HexInt64  `json:"timestampThreshold"`
-	RoundLimitFactor   *common.HexInt64  `json:"roundLimitFactor"`
-	MinimizeBlockGen   *common.HexInt16  `json:"minimizeBlockGen"`
-	DepositTerm        *common.HexInt64  `json:"depositTerm"`
-	DepositIssueRate   *common.HexInt64  `json:"depositIssueRate"`
-	FeeSharingEnabled  *common.HexInt16  `json:"feeSharingEnabled"`
+	ValidatorList      []*common.Address          `json:"validatorList"`
+	MemberList         []*common.Address          `json:"memberList"`
+	BlockInterval      *common.HexInt64           `json:"blockInterval"`
+	CommitTimeout      *common.HexInt64           `json:"commitTimeout"`
+	TimestampThreshold *common.HexInt64           `json:"timestampThreshold"`
+	RoundLimitFactor   *common.HexInt64           `json:"roundLimitFactor"`
+	MinimizeBlockGen   *common.HexInt16           `json:"minimizeBlockGen"`
+	DepositTerm        *common.HexInt64           `json:"depositTerm"`
+	DepositIssueRate   *common.HexInt64           `json:"depositIssueRate"`
+	FeeSharingEnabled  *common.HexInt16           `json:"feeSharingEnabled"`
+	ConsensusTimeouts  map[string]common.HexInt64 `json:"consensusTimeouts"`
 }
 
 func (s *ChainScore) Install(param []byte) error {
@@ -685,6 +703,23 @@ func (s *ChainScore) Install(param []byte) error {
 		}
 	}
 
+	if len(chain.ConsensusTimeouts) > 0 {
+		for k, v := range chain.ConsensusTimeouts {
+			if !contract.IsValidConsensusTimeout(k) || !contract.IsValidConsensusTimeoutValue(k, v.Value) {
+				return scoreresult.IllegalFormatError.Errorf(
+					"InvalidConsensusTimeout(name=%s,value=%s)", k, v)
+			}
+		}
+		db := scoredb.NewDictDB(as, state.VarConsensusTimeouts, 1)
+		for _, k := range contract.AllConsensusTimeouts {
+			if v, ok := chain.ConsensusTimeouts[k]; ok && v.Value != 0 {
+				if err := db.Set(k, v.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	if chain.MinimizeBlockGen != nil {
 		yn := chain.MinimizeBlockGen.Value != 0
 		if err := scoredb.NewVarDB(as, state.VarMinimizeBlockGen).Set(yn); err != nil {
@@ -1427,6 +1462,24 @@ func (s *ChainScore) Ex_setRoundLimitFactor(f *common.HexInt) error {
 	return factor.Set(f)
 }
 
+func (s *ChainScore) Ex_setConsensusTimeout(name string, timeout *common.HexInt) error {
+	if err := s.checkGovernance(true); err != nil {
+		return err
+	}
+	if !timeout.IsInt64() {
+		return scoreresult.InvalidParameterError.Errorf("InvalidConsensusTimeout(value=%s)", timeout)
+	}
+	_, err := contract.SetConsensusTimeout(s.cc, name, timeout.Int64())
+	return err
+}
+
+func (s *ChainScore) Ex_getConsensusTimeouts() (map[string]interface{}, error) {
+	if err := s.tryChargeCall(); err != nil {
+		return nil, err
+	}
+	return contract.GetConsensusTimeouts(s.cc), nil
+}
+
 func (s *ChainScore) Ex_getMinimizeBlockGen() (bool, error) {
 	if err := s.tryChargeCall(); err != nil {
 		return false, err
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/eeproxy"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/state"
+	"github.com/icon-project/goloop/service/transaction"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+func (m *manager) SimulateTransactions(
+	result []byte, vh []byte, txs [][]byte, bi module.BlockInfo,
+	overrides []*module.AccountOverride,
+) ([]*module.SimulationResult, error) {
+	wss, err := m.trc.GetWorldSnapshot(result, vh)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := state.WorldStateFromSnapshot(wss)
+	if err != nil {
+		return nil, err
+	}
+	wc := state.NewWorldContext(ws, bi, nil, m.plt)
+
+	for _, ov := range overrides {
+		if err := m.applyAccountOverride(wc, ov); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*module.SimulationResult, 0, len(txs))
+	for idx, js := range txs {
+		r, err := m.simulateTransaction(wc, idx, js)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (m *manager) simulateTransaction(wc state.WorldContext, idx int, js []byte) (*module.SimulationResult, error) {
+	tx, err := transaction.NewTransactionFromJSON(js)
+	if err != nil {
+		return nil, scoreresult.InvalidParameterError.Wrapf(err,
+			"InvalidTransaction(idx=%d)", idx)
+	}
+	if err := tx.Verify(); err != nil && !transaction.InvalidSignatureError.Equals(err) {
+		return nil, scoreresult.InvalidParameterError.Wrapf(err,
+			"InvalidTransaction(idx=%d)", idx)
+	}
+
+	txh, err := tx.GetHandler(m.cm)
+	if err != nil {
+		return nil, err
+	}
+	defer txh.Dispose()
+
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Index:     int32(idx),
+		Hash:      tx.ID(),
+		From:      tx.From(),
+		Timestamp: tx.Timestamp(),
+		Nonce:     tx.Nonce(),
+	})
+	ctx.UpdateSystemInfo()
+
+	wcs := wc.GetSnapshot()
+	rct, err := txh.Execute(ctx, wcs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := []module.Address{tx.From(), tx.To(), wc.Treasury()}
+	for itr := rct.EventLogIterator(); itr.Has(); log.Must(itr.Next()) {
+		ev, _ := itr.Get()
+		indexed := ev.Indexed()
+		if len(indexed) != 4 || string(indexed[0]) != txresult.EventLogICXTransfer {
+			continue
+		}
+		for _, bs := range indexed[1:3] {
+			if addr, err := common.NewAddress(bs); err == nil {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return &module.SimulationResult{
+		Receipt:        rct,
+		BalanceChanges: balanceChangesOf(wcs, wc, addrs),
+	}, nil
+}
+
+// balanceChangesOf returns the changes of the balances of the accounts
+// between the snapshot and the current state in the order of the addresses.
+func balanceChangesOf(
+	wss state.WorldSnapshot, ws state.WorldState, addrs []module.Address,
+) []*module.BalanceChange {
+	var changes []*module.BalanceChange
+	seen := make(map[string]bool)
+	for _, addr := range addrs {
+		if addr == nil || seen[string(addr.Bytes())] {
+			continue
+		}
+		seen[string(addr.Bytes())] = true
+
+		before := new(big.Int)
+		if ass := wss.GetAccountSnapshot(addr.ID()); ass != nil {
+			before = ass.GetBalance()
+		}
+		after := ws.GetAccountState(addr.ID()).GetBalance()
+		if delta := new(big.Int).Sub(after, before); delta.Sign() != 0 {
+			changes = append(changes, &module.BalanceChange{
+				Address: addr,
+				Balance: after,
+				Delta:   delta,
+			})
+		}
+	}
+	return changes
+}
+
+func (m *manager) applyAccountOverride(wc state.WorldContext, ov *module.AccountOverride) error {
+	if ov == nil || ov.Address == nil {
+		return errors.IllegalArgumentError.New("NoAddressForOverride")
+	}
+	as := wc.GetAccountState(ov.Address.ID())
+	if ov.Owner != nil && as.IsContract() {
+		// the owner is replaced directly, so the code can be overridden
+		// regardless of the current owner.
+		if err := as.SetContractOwner(ov.Owner); err != nil {
+			return err
+		}
+	}
+	if len(ov.Content) > 0 {
+		if !ov.Address.IsContract() {
+			return errors.IllegalArgumentError.Errorf(
+				"CodeForEOA(addr=%s)", ov.Address)
+		}
+		owner := ov.Owner
+		if owner == nil {
+			owner = as.ContractOwner()
+		}
+		if owner == nil {
+			return errors.IllegalArgumentError.Errorf(
+				"NoOwnerForCode(addr=%s)", ov.Address)
+		}
+		if err := m.deployForOverride(wc, owner, ov); err != nil {
+			return err
+		}
+	} else if ov.Owner != nil && !as.IsContract() {
+		return errors.IllegalArgumentError.Errorf(
+			"OwnerForNonContract(addr=%s)", ov.Address)
+	}
+	if ov.Balance != nil {
+		as.SetBalance(ov.Balance)
+	}
+	for k, v := range ov.Storage {
+		var err error
+		if v == nil {
+			_, err = as.DeleteValue([]byte(k))
+		} else {
+			_, err = as.SetValue([]byte(k), v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *manager) deployForOverride(wc state.WorldContext, owner module.Address, ov *module.AccountOverride) error {
+	update := wc.GetAccountState(ov.Address.ID()).IsContract()
+	handler := contract.NewDeployHandlerForOverride(owner, ov.Address, update,
+		ov.ContentType, ov.Content, ov.Params, m.log)
+
+	ctx := contract.NewContext(wc, m.cm, m.eem, m.chain, m.log, nil, eeproxy.ForQuery)
+	ctx.SetTransactionInfo(&state.TransactionInfo{
+		Group:     module.TransactionGroupNormal,
+		Index:     0,
+		Hash:      crypto.SHA3Sum256(append([]byte("override:"), ov.Address.Bytes()...)),
+		From:      owner,
+		Timestamp: wc.BlockTimeStamp(),
+	})
+	ctx.UpdateSystemInfo()
+
+	cc := contract.NewCallContext(ctx, ctx.GetStepLimit(state.StepLimitTypeInvoke), false)
+	defer cc.Dispose()
+	status, _, _, _ := cc.Call(handler, cc.StepAvailable())
+	if status != nil {
+		return errors.IllegalArgumentError.Wrapf(status,
+			"FailToOverrideCode(addr=%s)", ov.Address)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/chain/base"
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/intconv"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/contract"
+	"github.com/icon-project/goloop/service/scoredb"
+	"github.com/icon-project/goloop/service/state"
+)
+
+func TestBalanceChangesOf(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	addr3 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+
+	ws := state.NewWorldState(db.NewMapDB(), nil, nil, nil, nil)
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	ws.GetAccountState(addr3.ID()).SetBalance(big.NewInt(7))
+	wss := ws.GetSnapshot()
+
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(70))
+	ws.GetAccountState(addr2.ID()).SetBalance(big.NewInt(30))
+
+	changes := balanceChangesOf(wss, ws,
+		[]module.Address{addr2, nil, addr1, addr3, addr2})
+	assert.Equal(t, []*module.BalanceChange{
+		{Address: addr2, Balance: big.NewInt(30), Delta: big.NewInt(30)},
+		{Address: addr1, Balance: big.NewInt(70), Delta: big.NewInt(-30)},
+	}, changes)
+}
+
+type testSimulationPlatform struct {
+	base.Platform
+}
+
+func (p *testSimulationPlatform) ToRevision(value int) module.Revision {
+	return module.LatestRevision
+}
+
+func (p *testSimulationPlatform) NewExtensionSnapshot(dbase db.Database, raw []byte) state.ExtensionSnapshot {
+	return nil
+}
+
+type testSimulationChain struct {
+	module.Chain
+}
+
+func (c *testSimulationChain) TransactionTimeout() time.Duration {
+	return 5 * time.Second
+}
+
+func newTestSimulationManager(t *testing.T, dbase db.Database) *manager {
+	logger := log.GlobalLogger()
+	plt := &testSimulationPlatform{}
+	cm, err := contract.NewContractManager(dbase, t.TempDir(), logger)
+	assert.NoError(t, err)
+	return &manager{
+		plt:   plt,
+		db:    dbase,
+		chain: &testSimulationChain{},
+		cm:    cm,
+		trc:   newTransitionResultCache(dbase, plt, 10, 10, logger),
+		log:   logger,
+	}
+}
+
+// newTestSimulationWorldState returns a world state having the step price
+// and the step limit for executing transactions.
+func newTestSimulationWorldState(t *testing.T, dbase db.Database) state.WorldState {
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	sys := ws.GetAccountState(state.SystemID)
+	assert.NoError(t, scoredb.NewVarDB(sys, state.VarStepPrice).Set(0))
+	assert.NoError(t, scoredb.NewArrayDB(sys, state.VarStepLimitTypes).Put(state.StepLimitTypeInvoke))
+	assert.NoError(t, scoredb.NewDictDB(sys, state.VarStepLimit, 1).Set(state.StepLimitTypeInvoke, 100000))
+	return ws
+}
+
+func testTransferTx(t *testing.T, from, to module.Address, value int64) []byte {
+	js, err := json.Marshal(map[string]interface{}{
+		"version":   "0x3",
+		"from":      from.String(),
+		"to":        to.String(),
+		"value":     intconv.FormatInt(value),
+		"stepLimit": "0x186a0",
+		"timestamp": intconv.FormatInt(time.Now().UnixMicro()),
+		"nid":       "0x1",
+		"nonce":     "0x1",
+		"signature": base64.StdEncoding.EncodeToString(make([]byte, 65)),
+	})
+	assert.NoError(t, err)
+	return js
+}
+
+func TestManager_SimulateTransactions(t *testing.T) {
+	addr1 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	addr2 := common.MustNewAddressFromString("hx0000000000000000000000000000000000000002")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000003")
+
+	dbase := db.NewMapDB()
+	ws := newTestSimulationWorldState(t, dbase)
+	ws.GetAccountState(addr1.ID()).SetBalance(big.NewInt(100))
+	_, err := ws.GetAccountState(addr1.ID()).SetValue([]byte("key"), []byte("value"))
+	assert.NoError(t, err)
+	wss := ws.GetSnapshot()
+	assert.NoError(t, wss.Flush())
+	result := (&transitionResult{StateHash: wss.StateHash()}).Bytes()
+
+	m := newTestSimulationManager(t, dbase)
+	bi := common.NewBlockInfo(1, time.Now().UnixMicro())
+	txs := [][]byte{testTransferTx(t, addr1, addr2, 500)}
+
+	// addr1 doesn't have enough balance
+	results, err := m.SimulateTransactions(result, nil, txs, bi, nil)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, module.StatusOutOfBalance, results[0].Receipt.Status())
+	assert.Empty(t, results[0].BalanceChanges)
+
+	// with overridden balance and storage
+	results, err = m.SimulateTransactions(result, nil, txs, bi, []*module.AccountOverride{{
+		Address: addr1,
+		Balance: big.NewInt(1000),
+		Storage: map[string][]byte{
+			"key":  nil,
+			"key2": []byte("value2"),
+		},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, module.StatusSuccess, results[0].Receipt.Status())
+	assert.Equal(t, []*module.BalanceChange{
+		{Address: addr1, Balance: big.NewInt(500), Delta: big.NewInt(-500)},
+		{Address: addr2, Balance: big.NewInt(500), Delta: big.NewInt(500)},
+	}, results[0].BalanceChanges)
+
+	// failure of code override fails the simulation
+	_, err = m.SimulateTransactions(result, nil, txs, bi, []*module.AccountOverride{{
+		Address:     score,
+		Owner:       addr1,
+		ContentType: "application/x-unknown",
+		Content:     []byte("code"),
+	}})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Contains(t, err.Error(), "FailToOverrideCode")
+
+	// real state is not changed
+	wss2, err := m.trc.GetWorldSnapshot(result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, wss.StateHash(), wss2.StateHash())
+	ass1 := wss2.GetAccountSnapshot(addr1.ID())
+	assert.Equal(t, big.NewInt(100), ass1.GetBalance())
+	value, err := ass1.GetValue([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	value, err = ass1.GetValue([]byte("key2"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Nil(t, wss2.GetAccountSnapshot(addr2.ID()))
+	assert.Nil(t, wss2.GetAccountSnapshot(score.ID()))
+}
+
+func TestManager_applyAccountOverride(t *testing.T) {
+	eoa := common.MustNewAddressFromString("hx0000000000000000000000000000000000000001")
+	score := common.MustNewAddressFromString("cx0000000000000000000000000000000000000002")
+
+	dbase := db.NewMapDB()
+	m := newTestSimulationManager(t, dbase)
+	ws := state.NewWorldState(dbase, nil, nil, nil, nil)
+	wc := state.NewWorldContext(ws, common.NewBlockInfo(1, 0), nil, m.plt)
+
+	err := m.applyAccountOverride(wc, &module.AccountOverride{
+		Address: eoa,
+		Balance: big.NewInt(10),
+		Storage: map[string][]byte{"key": []byte("value")},
+	})
+	assert.NoError(t, err)
+	as := wc.GetAccountState(eoa.ID())
+	assert.Equal(t, big.NewInt(10), as.GetBalance())
+	value, err := as.GetValue([]byte("key"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address: eoa,
+		Storage: map[string][]byte{"key": nil},
+	})
+	assert.NoError(t, err)
+	value, err = as.GetValue([]byte("key"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	assert.Equal(t, big.NewInt(10), as.GetBalance())
+
+	err = m.applyAccountOverride(wc, &module.AccountOverride{})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+
+	// code override on EOA
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address:     eoa,
+		Owner:       eoa,
+		ContentType: "application/java",
+		Content:     []byte("code"),
+	})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Contains(t, err.Error(), "CodeForEOA")
+
+	// code override without owner on the account without contract
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address:     score,
+		ContentType: "application/java",
+		Content:     []byte("code"),
+	})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Contains(t, err.Error(), "NoOwnerForCode")
+	assert.False(t, wc.GetAccountState(score.ID()).IsContract())
+
+	// owner override without contract
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address: score,
+		Owner:   eoa,
+	})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Contains(t, err.Error(), "OwnerForNonContract")
+
+	// owner override on the contract owned by others
+	other := common.MustNewAddressFromString("hx0000000000000000000000000000000000000003")
+	sas := wc.GetAccountState(score.ID())
+	assert.True(t, sas.InitContractAccount(other))
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address: score,
+		Owner:   eoa,
+	})
+	assert.NoError(t, err)
+	assert.True(t, sas.IsContractOwner(eoa))
+
+	// code override is done by the new owner, so it fails only because
+	// the java contract can't be updated with python.
+	assert.NoError(t, sas.SetContractOwner(other))
+	_, err = sas.DeployContract([]byte("java"), state.JavaEE, state.CTAppJava, nil, []byte("tx"))
+	assert.NoError(t, err)
+	assert.NoError(t, sas.AcceptContract([]byte("tx"), nil))
+	err = m.applyAccountOverride(wc, &module.AccountOverride{
+		Address:     score,
+		Owner:       eoa,
+		ContentType: state.CTAppZip,
+		Content:     []byte("python"),
+	})
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	assert.Contains(t, errors.Unwrap(err).Error(), "ProhibitToUpdate")
+	assert.True(t, sas.IsContractOwner(eoa))
+}
@@ -0,0 +1,267 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"bytes"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/trie"
+	"github.com/icon-project/goloop/common/trie/trie_manager"
+	"github.com/icon-project/goloop/module"
+)
+
+// Changed properties of the account
+const (
+	AccountChangeVersion  = "version"
+	AccountChangeBalance  = "balance"
+	AccountChangeState    = "state"
+	AccountChangeOwner    = "owner"
+	AccountChangeStorage  = "storage"
+	AccountChangeCode     = "code"
+	AccountChangeAPI      = "api"
+	AccountChangeObjGraph = "objGraph"
+	AccountChangeDeposit  = "deposit"
+)
+
+// StorageDiff is a changed entry of the account storage. Old is nil for
+// the added entry, and New is nil for the removed entry.
+type StorageDiff struct {
+	Key []byte
+	Old []byte
+	New []byte
+}
+
+// AccountDiff is the difference of an account between two world states.
+// Key is the key of the account in the world state trie (hash of the
+// address). Old is nil for the added account, and New is nil for the
+// removed account.
+type AccountDiff struct {
+	Key     []byte
+	Old     AccountSnapshot
+	New     AccountSnapshot
+	Changes []string
+	Storage []StorageDiff
+}
+
+func storeOf(s *accountSnapshotImpl) trie.Immutable {
+	if s == nil || s.store == nil {
+		return nil
+	}
+	return s.store.(trie.Immutable)
+}
+
+func storeHashOf(s *accountSnapshotImpl) []byte {
+	if store := storeOf(s); store != nil {
+		return store.Hash()
+	}
+	return nil
+}
+
+func changesOfAccounts(s1, s2 *accountSnapshotImpl) []string {
+	var changes []string
+	if s1.version != s2.version {
+		changes = append(changes, AccountChangeVersion)
+	}
+	if s1.balance.Cmp(s2.balance) != 0 {
+		changes = append(changes, AccountChangeBalance)
+	}
+	if s1.isContract != s2.isContract || s1.state != s2.state {
+		changes = append(changes, AccountChangeState)
+	}
+	if !s1.contractOwner.Equal(s2.contractOwner) {
+		changes = append(changes, AccountChangeOwner)
+	}
+	if !bytes.Equal(storeHashOf(s1), storeHashOf(s2)) {
+		changes = append(changes, AccountChangeStorage)
+	}
+	if !s1.curContract.Equal(s2.curContract) || !s1.nextContract.Equal(s2.nextContract) {
+		changes = append(changes, AccountChangeCode)
+	}
+	if !s1.apiInfo.Equal(&s2.apiInfo) {
+		changes = append(changes, AccountChangeAPI)
+	}
+	if !s1.objGraph.Equal(s2.objGraph) {
+		changes = append(changes, AccountChangeObjGraph)
+	}
+	if !s1.deposits.Equal(s2.deposits) {
+		changes = append(changes, AccountChangeDeposit)
+	}
+	return changes
+}
+
+func diffStorage(dbase db.Database, s1, s2 *accountSnapshotImpl, limit int) ([]StorageDiff, error) {
+	st1, st2 := storeOf(s1), storeOf(s2)
+	if st1 == nil {
+		st1 = trie_manager.NewImmutable(dbase, nil)
+	}
+	if st2 == nil {
+		st2 = trie_manager.NewImmutable(dbase, nil)
+	}
+	var diffs []StorageDiff
+	err := trie_manager.CompareImmutableWithError(st1, st2, func(op int, key []byte, exp, real []byte) error {
+		if limit >= 0 && len(diffs) >= limit {
+			return errTooManyChanges
+		}
+		diffs = append(diffs, StorageDiff{Key: key, Old: exp, New: real})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diffs, nil
+}
+
+var errTooManyChanges = errors.IllegalArgumentError.New("TooManyChanges")
+
+// DiffWorldStates returns changed accounts from the world state of hash1
+// to the world state of hash2 in order of the keys. If withStorage is true,
+// then changed entries of the storage are also returned.
+//
+// If limit is positive, it stops comparing and returns IllegalArgumentError
+// if the number of changed accounts and storage entries exceeds the limit.
+func DiffWorldStates(dbase db.Database, hash1, hash2 []byte, withStorage bool, limit int) ([]*AccountDiff, error) {
+	if bytes.Equal(hash1, hash2) {
+		return []*AccountDiff{}, nil
+	}
+	t1 := trie_manager.NewImmutableForObject(dbase, hash1, AccountType)
+	t2 := trie_manager.NewImmutableForObject(dbase, hash2, AccountType)
+
+	// remains is the number of changes to be returned, negative for no limit.
+	remains := -1
+	if limit > 0 {
+		remains = limit
+	}
+	diffs := []*AccountDiff{}
+	err := trie_manager.CompareImmutableForObjectWithError(t1, t2, func(op int, key []byte, exp, real trie.Object) error {
+		s1, _ := exp.(*accountSnapshotImpl)
+		s2, _ := real.(*accountSnapshotImpl)
+		diff := &AccountDiff{Key: key}
+		if s1 != nil {
+			diff.Old = s1
+		}
+		if s2 != nil {
+			diff.New = s2
+		}
+		var storageChanged bool
+		if s1 != nil && s2 != nil {
+			diff.Changes = changesOfAccounts(s1, s2)
+			if len(diff.Changes) == 0 {
+				return nil
+			}
+			for _, c := range diff.Changes {
+				if c == AccountChangeStorage {
+					storageChanged = true
+				}
+			}
+		} else {
+			storageChanged = storeOf(s1) != nil || storeOf(s2) != nil
+		}
+		if remains == 0 {
+			return errTooManyChanges
+		} else if remains > 0 {
+			remains--
+		}
+		if withStorage && storageChanged {
+			var err error
+			if diff.Storage, err = diffStorage(dbase, s1, s2, remains); err != nil {
+				return err
+			}
+			if remains > 0 {
+				remains -= len(diff.Storage)
+			}
+		}
+		diffs = append(diffs, diff)
+		return nil
+	})
+	if err != nil {
+		if err == errTooManyChanges {
+			return nil, errors.IllegalArgumentError.Errorf("TooManyChanges(max=%d)", limit)
+		}
+		return nil, err
+	}
+	return diffs, nil
+}
+
+func accountToJSON(ass AccountSnapshot) map[string]interface{} {
+	s, ok := ass.(*accountSnapshotImpl)
+	if !ok || s == nil {
+		return nil
+	}
+	jso := map[string]interface{}{
+		"version":    common.HexInt32{Value: int32(s.version)},
+		"balance":    common.NewHexInt(0).SetValue(s.balance),
+		"isContract": s.isContract,
+		"state":      common.HexInt32{Value: int32(s.state)},
+	}
+	if h := storeHashOf(s); h != nil {
+		jso["storageRoot"] = common.HexBytes(h)
+	}
+	if s.contractOwner != nil {
+		jso["owner"] = s.contractOwner
+	}
+	if s.curContract != nil {
+		jso["codeHash"] = common.HexBytes(s.curContract.codeHash)
+	}
+	if s.nextContract != nil {
+		jso["nextCodeHash"] = common.HexBytes(s.nextContract.codeHash)
+	}
+	if h := s.apiInfo.getHash(); h != nil {
+		jso["apiHash"] = common.HexBytes(h)
+	}
+	return jso
+}
+
+func (d *AccountDiff) ToJSON(version module.JSONVersion) (interface{}, error) {
+	jso := map[string]interface{}{
+		"key": common.HexBytes(d.Key),
+	}
+	switch {
+	case d.Old == nil:
+		jso["op"] = "added"
+	case d.New == nil:
+		jso["op"] = "removed"
+	default:
+		jso["op"] = "changed"
+		jso["changes"] = d.Changes
+	}
+	if d.Old != nil {
+		jso["old"] = accountToJSON(d.Old)
+	}
+	if d.New != nil {
+		jso["new"] = accountToJSON(d.New)
+	}
+	if d.Storage != nil {
+		storage := make([]interface{}, 0, len(d.Storage))
+		for _, sd := range d.Storage {
+			e := map[string]interface{}{
+				"key": common.HexBytes(sd.Key),
+			}
+			if sd.Old != nil {
+				e["old"] = common.HexBytes(sd.Old)
+			}
+			if sd.New != nil {
+				e["new"] = common.HexBytes(sd.New)
+			}
+			storage = append(storage, e)
+		}
+		jso["storage"] = storage
+	}
+	return jso, nil
+}
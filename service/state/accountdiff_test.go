@@ -0,0 +1,101 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/db"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+func TestDiffWorldStates(t *testing.T) {
+	dbase := db.NewMapDB()
+	ws := NewWorldState(dbase, nil, nil, nil, nil)
+
+	id1, id2, id3 := []byte("account1"), []byte("account2"), []byte("account3")
+	ws.GetAccountState(id1).SetBalance(big.NewInt(100))
+	ws.GetAccountState(id2).SetBalance(big.NewInt(200))
+	_, err := ws.GetAccountState(id2).SetValue([]byte("k1"), []byte("v1"))
+	assert.NoError(t, err)
+	ss1 := ws.GetSnapshot()
+	assert.NoError(t, ss1.Flush())
+
+	ws.GetAccountState(id1).SetBalance(big.NewInt(150))
+	_, err = ws.GetAccountState(id2).SetValue([]byte("k1"), []byte("v2"))
+	assert.NoError(t, err)
+	_, err = ws.GetAccountState(id2).SetValue([]byte("k2"), []byte("v3"))
+	assert.NoError(t, err)
+	ws.GetAccountState(id3).SetBalance(big.NewInt(300))
+	ss2 := ws.GetSnapshot()
+	assert.NoError(t, ss2.Flush())
+
+	diffs, err := DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), false, 0)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 3)
+
+	byKey := make(map[string]*AccountDiff)
+	for _, d := range diffs {
+		byKey[string(d.Key)] = d
+		assert.Nil(t, d.Storage)
+	}
+	d1 := byKey[string(crypto.SHA3Sum256(id1))]
+	assert.NotNil(t, d1)
+	assert.Equal(t, []string{AccountChangeBalance}, d1.Changes)
+	assert.Equal(t, 0, d1.Old.GetBalance().Cmp(big.NewInt(100)))
+	assert.Equal(t, 0, d1.New.GetBalance().Cmp(big.NewInt(150)))
+
+	d2 := byKey[string(crypto.SHA3Sum256(id2))]
+	assert.NotNil(t, d2)
+	assert.Equal(t, []string{AccountChangeStorage}, d2.Changes)
+
+	d3 := byKey[string(crypto.SHA3Sum256(id3))]
+	assert.NotNil(t, d3)
+	assert.Nil(t, d3.Old)
+	assert.NotNil(t, d3.New)
+
+	diffs, err = DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), true, 0)
+	assert.NoError(t, err)
+	for _, d := range diffs {
+		if string(d.Key) == string(crypto.SHA3Sum256(id2)) {
+			assert.Len(t, d.Storage, 2)
+		}
+		_, err := d.ToJSON(module.JSONVersionLast)
+		assert.NoError(t, err)
+	}
+
+	diffs, err = DiffWorldStates(dbase, ss2.StateHash(), ss2.StateHash(), true, 0)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 0)
+
+	// limit counts both changed accounts and storage entries
+	diffs, err = DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), false, 3)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 3)
+	_, err = DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), false, 2)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+	diffs, err = DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), true, 5)
+	assert.NoError(t, err)
+	assert.Len(t, diffs, 3)
+	_, err = DiffWorldStates(dbase, ss1.StateHash(), ss2.StateHash(), true, 4)
+	assert.True(t, errors.IllegalArgumentError.Equals(err))
+}
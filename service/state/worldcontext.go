@@ -40,6 +40,7 @@ const (
 	VarNextBlockVersion   = "next_block_version"
 	VarEnabledEETypes     = "enabled_ee_types"
 	VarSystemDepositUsage = "system_deposit_usage"
+	VarConsensusTimeouts  = "consensus_timeouts"
 
 	VarDSRContextHistory = "dsr_context_history"
 )
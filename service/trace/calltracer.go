@@ -0,0 +1,214 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+	"github.com/icon-project/goloop/service/txresult"
+)
+
+type callTrace struct {
+	parent *callTrace
+	call   *module.TraceCall
+
+	exited   bool
+	status   error
+	stepUsed *big.Int
+	result   interface{}
+	addr     module.Address
+
+	events []interface{}
+	calls  []*callTrace
+}
+
+func (c *callTrace) toJSON() map[string]interface{} {
+	call := c.call
+	jso := map[string]interface{}{
+		"type": call.Type,
+	}
+	if call.From != nil {
+		jso["from"] = call.From
+	}
+	if call.To != nil {
+		jso["to"] = call.To
+	}
+	if call.Value != nil {
+		jso["value"] = new(common.HexInt).SetValue(call.Value)
+	}
+	if len(call.Method) > 0 {
+		jso["method"] = call.Method
+	}
+	if call.Params != nil {
+		jso["params"] = call.Params
+	}
+	if c.exited {
+		jso["stepUsed"] = new(common.HexInt).SetValue(c.stepUsed)
+		if c.status == nil {
+			jso["status"] = "0x1"
+			if c.result != nil {
+				jso["result"] = c.result
+			}
+			if c.addr != nil {
+				jso["scoreAddress"] = c.addr
+			}
+		} else {
+			jso["status"] = "0x0"
+			code, _ := scoreresult.StatusOf(c.status)
+			jso["failure"] = map[string]interface{}{
+				"code":    code,
+				"message": c.status.Error(),
+			}
+		}
+	}
+	if len(c.events) > 0 {
+		jso["eventLogs"] = c.events
+	}
+	if len(c.calls) > 0 {
+		jso["calls"] = callTracesToJSON(c.calls)
+	}
+	return jso
+}
+
+func callTracesToJSON(calls []*callTrace) []interface{} {
+	jso := make([]interface{}, len(calls))
+	for i, c := range calls {
+		jso[i] = c.toJSON()
+	}
+	return jso
+}
+
+type callTraceTx struct {
+	index     int
+	hash      []byte
+	isBlockTx bool
+	calls     []*callTrace
+}
+
+func (t *callTraceTx) toJSON() map[string]interface{} {
+	jso := map[string]interface{}{
+		"txIndex": fmt.Sprintf("%#x", t.index),
+		"calls":   callTracesToJSON(t.calls),
+	}
+	if !t.isBlockTx {
+		jso["txHash"] = "0x" + hex.EncodeToString(t.hash)
+	}
+	return jso
+}
+
+// CallTracer builds the tree of call frames for each transaction with
+// the details of the frames given in TraceModeCallTree.
+type CallTracer struct {
+	txs     []*callTraceTx
+	curTx   *callTraceTx
+	curCall *callTrace
+}
+
+func (ct *CallTracer) OnTransactionStart(txIndex int, txHash []byte, isBlockTx bool) error {
+	if ct.curTx != nil {
+		return errors.InvalidStateError.Errorf(
+			"Invalid curTx: txIndex=%d txHash=%#x curTx=%#x",
+			txIndex, txHash, ct.curTx.hash)
+	}
+	tx := &callTraceTx{index: txIndex, hash: txHash, isBlockTx: isBlockTx}
+	ct.txs = append(ct.txs, tx)
+	ct.curTx = tx
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionReset() error {
+	if ct.curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	ct.curTx.calls = nil
+	ct.curCall = nil
+	return nil
+}
+
+func (ct *CallTracer) OnTransactionEnd(txIndex int, txHash []byte) error {
+	curTx := ct.curTx
+	if curTx == nil {
+		return errors.InvalidStateError.New("No transaction")
+	}
+	if curTx.index != txIndex || !bytes.Equal(curTx.hash, txHash) {
+		return errors.InvalidStateError.Errorf(
+			"Invalid txHash: curTxHash=%#x hash=%#x", curTx.hash, txHash)
+	}
+	if curTx.isBlockTx && len(curTx.calls) == 0 {
+		ct.txs = ct.txs[:len(ct.txs)-1]
+	}
+	ct.curTx = nil
+	ct.curCall = nil
+	return nil
+}
+
+func (ct *CallTracer) OnCallEnter(call *module.TraceCall) error {
+	if ct.curTx == nil {
+		return errors.InvalidStateError.New("CallTracer Not Ready")
+	}
+	c := &callTrace{parent: ct.curCall, call: call}
+	if ct.curCall != nil {
+		ct.curCall.calls = append(ct.curCall.calls, c)
+	} else {
+		ct.curTx.calls = append(ct.curTx.calls, c)
+	}
+	ct.curCall = c
+	return nil
+}
+
+func (ct *CallTracer) OnCallExit(status error, stepUsed *big.Int, result interface{}, addr module.Address) error {
+	c := ct.curCall
+	if c == nil {
+		return errors.InvalidStateError.New("curCall Not Ready")
+	}
+	c.exited = true
+	c.status = status
+	c.stepUsed = stepUsed
+	c.result = result
+	c.addr = addr
+	ct.curCall = c.parent
+	return nil
+}
+
+func (ct *CallTracer) OnCallEvent(addr module.Address, indexed, data [][]byte) error {
+	c := ct.curCall
+	if c == nil {
+		return errors.InvalidStateError.New("curCall Not Ready")
+	}
+	c.events = append(c.events, txresult.EventLogToJSON(addr, indexed, data))
+	return nil
+}
+
+// ToJSON returns the call trees of the traced transactions.
+func (ct *CallTracer) ToJSON() []interface{} {
+	jso := make([]interface{}, len(ct.txs))
+	for i, tx := range ct.txs {
+		jso[i] = tx.toJSON()
+	}
+	return jso
+}
+
+func NewCallTracer() *CallTracer {
+	return new(CallTracer)
+}
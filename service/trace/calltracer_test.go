@@ -0,0 +1,138 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/module"
+	"github.com/icon-project/goloop/service/scoreresult"
+)
+
+func TestCallTracer(t *testing.T) {
+	ct := NewCallTracer()
+
+	eoa := common.MustNewAddressFromString("hx100")
+	score1 := common.MustNewAddressFromString("cx101")
+	score2 := common.MustNewAddressFromString("cx102")
+	txHash := newRandomHash(32)
+
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+	assert.NoError(t, ct.OnCallEnter(&module.TraceCall{
+		Type:   "call",
+		From:   eoa,
+		To:     score1,
+		Value:  big.NewInt(0),
+		Method: "transfer",
+		Params: json.RawMessage(`{"_to":"hx100"}`),
+	}))
+	assert.NoError(t, ct.OnCallEvent(score1, [][]byte{
+		[]byte("Transfer(Address,Address,int)"),
+		eoa.Bytes(),
+		score1.Bytes(),
+	}, [][]byte{{0x10}}))
+
+	// failed sub call
+	assert.NoError(t, ct.OnCallEnter(&module.TraceCall{
+		Type: "call", From: score1, To: score2, Method: "notify",
+	}))
+	assert.NoError(t, ct.OnCallExit(scoreresult.ErrMethodNotFound, big.NewInt(10), nil, nil))
+
+	// successful sub call
+	assert.NoError(t, ct.OnCallEnter(&module.TraceCall{
+		Type: "transfer", From: score1, To: eoa, Value: big.NewInt(5),
+	}))
+	assert.NoError(t, ct.OnCallExit(nil, big.NewInt(20), nil, nil))
+
+	assert.NoError(t, ct.OnCallExit(nil, big.NewInt(100), "0x1", nil))
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	// block transaction without calls is ignored
+	assert.NoError(t, ct.OnTransactionStart(1, nil, true))
+	assert.NoError(t, ct.OnTransactionEnd(1, nil))
+
+	bs, err := json.Marshal(ct.ToJSON())
+	assert.NoError(t, err)
+	var jso []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(bs, &jso))
+	assert.Len(t, jso, 1)
+
+	tx := jso[0]
+	assert.Equal(t, "0x0", tx["txIndex"])
+	assert.Equal(t, "0x"+hex.EncodeToString(txHash), tx["txHash"])
+	calls := tx["calls"].([]interface{})
+	assert.Len(t, calls, 1)
+
+	root := calls[0].(map[string]interface{})
+	assert.Equal(t, "call", root["type"])
+	assert.Equal(t, "hx0000000000000000000000000000000000000100", root["from"])
+	assert.Equal(t, "transfer", root["method"])
+	assert.Equal(t, map[string]interface{}{"_to": "hx100"}, root["params"])
+	assert.Equal(t, "0x64", root["stepUsed"])
+	assert.Equal(t, "0x1", root["status"])
+	assert.Equal(t, "0x1", root["result"])
+
+	events := root["eventLogs"].([]interface{})
+	assert.Len(t, events, 1)
+	event := events[0].(map[string]interface{})
+	assert.Equal(t, "cx0000000000000000000000000000000000000101", event["scoreAddress"])
+	assert.Equal(t, []interface{}{
+		"Transfer(Address,Address,int)",
+		"hx0000000000000000000000000000000000000100",
+		"cx0000000000000000000000000000000000000101",
+	}, event["indexed"])
+
+	subCalls := root["calls"].([]interface{})
+	assert.Len(t, subCalls, 2)
+	failed := subCalls[0].(map[string]interface{})
+	assert.Equal(t, "0x0", failed["status"])
+	assert.Equal(t, "0xa", failed["stepUsed"])
+	assert.NotNil(t, failed["failure"])
+	transfer := subCalls[1].(map[string]interface{})
+	assert.Equal(t, "transfer", transfer["type"])
+	assert.Equal(t, "0x5", transfer["value"])
+	assert.Equal(t, "0x1", transfer["status"])
+}
+
+func TestCallTracer_ErrorCase(t *testing.T) {
+	ct := NewCallTracer()
+	txHash := newRandomHash(32)
+
+	assert.Error(t, ct.OnCallEnter(&module.TraceCall{Type: "call"}))
+	assert.Error(t, ct.OnTransactionEnd(0, txHash))
+
+	assert.NoError(t, ct.OnTransactionStart(0, txHash, false))
+	assert.Error(t, ct.OnTransactionStart(1, txHash, false))
+	assert.Error(t, ct.OnCallExit(nil, big.NewInt(0), nil, nil))
+	assert.Error(t, ct.OnCallEvent(nil, nil, nil))
+
+	// reset drops the calls of the transaction
+	assert.NoError(t, ct.OnCallEnter(&module.TraceCall{Type: "call"}))
+	assert.NoError(t, ct.OnTransactionReset())
+	assert.Error(t, ct.OnTransactionEnd(1, txHash))
+	assert.NoError(t, ct.OnTransactionEnd(0, txHash))
+
+	jso := ct.ToJSON()
+	assert.Len(t, jso, 1)
+	assert.Len(t, jso[0].(map[string]interface{})["calls"], 0)
+}
@@ -206,6 +206,38 @@ func (l *Logger) OnFrameExit(success bool, stepUsed *big.Int) {
 	}
 }
 
+func (l *Logger) callTraceCallback() module.CallTraceCallback {
+	if l.traceMode != module.TraceModeCallTree {
+		return nil
+	}
+	cb, _ := l.cb.(module.CallTraceCallback)
+	return cb
+}
+
+func (l *Logger) OnCallEnter(call *module.TraceCall) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallEnter(call); err != nil {
+			l.Warnf("OnCallEnter() error: call=%+v err=%#v", call, err)
+		}
+	}
+}
+
+func (l *Logger) OnCallExit(status error, stepUsed *big.Int, result interface{}, addr module.Address) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallExit(status, stepUsed, result, addr); err != nil {
+			l.Warnf("OnCallExit() error: status=%v err=%#v", status, err)
+		}
+	}
+}
+
+func (l *Logger) OnCallEvent(addr module.Address, indexed, data [][]byte) {
+	if cb := l.callTraceCallback(); cb != nil {
+		if err := cb.OnCallEvent(addr, indexed, data); err != nil {
+			l.Warnf("OnCallEvent() error: score=%s err=%#v", addr, err)
+		}
+	}
+}
+
 func (l *Logger) OnBalanceChange(opType module.OpType, from, to module.Address, amount *big.Int) {
 	if l.TraceMode() == module.TraceModeNone {
 		return
@@ -44,6 +44,10 @@ func (t *txElement) Prev() *txElement {
 	return t.listPrev
 }
 
+func (t *txElement) SrcPrev() *txElement {
+	return t.srcPrev
+}
+
 func (t *txElement) Remove() bool {
 	if t.list != nil {
 		return t.list.Remove(t)
@@ -249,6 +253,18 @@ func (l *transactionList) HasTx(id []byte) bool {
 	return ok
 }
 
+func (l *transactionList) Get(id []byte) *txElement {
+	tidBk, tidSlot := indexAndBucketKeyFromKey(string(id))
+	return l.idMap[tidBk][tidSlot]
+}
+
+// LastOf returns the element of the last transaction sent by the address.
+// Previous transactions of the address can be followed by SrcPrev.
+func (l *transactionList) LastOf(from module.Address) *txElement {
+	uidBk, uidSlot := indexAndBucketKeyFromKey(string(from.ID()))
+	return l.srcMapToLast[uidBk][uidSlot]
+}
+
 func (l *transactionList) GetBloom() *TxBloom {
 	if l.listFront == nil {
 		return &TxBloom{}
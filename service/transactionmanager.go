@@ -248,6 +248,30 @@ func (m *TransactionManager) SetPoolCapacityMonitor(pcm PoolCapacityMonitor) {
 	m.normalTxPool.SetPoolCapacityMonitor(pcm)
 }
 
+func (m *TransactionManager) PendingTransactions(
+	g module.TransactionGroup, from module.Address, skip, limit int,
+) ([]module.Transaction, int) {
+	return m.getTxPool(g).Transactions(from, skip, limit)
+}
+
+func (m *TransactionManager) PoolStatus(g module.TransactionGroup) *module.TxPoolStatus {
+	return m.getTxPool(g).Status()
+}
+
+func (m *TransactionManager) Evict(id []byte) error {
+	for _, pool := range []*TransactionPool{m.normalTxPool, m.patchTxPool} {
+		if pool.Evict(id, ErrEvictedTransaction) {
+			return nil
+		}
+	}
+	return errors.NotFoundError.Errorf("TransactionNotInPool(id=%#x)", id)
+}
+
+func (m *TransactionManager) SetPendingTxWatcher(ptw PendingTxWatcher) {
+	m.patchTxPool.SetPendingTxWatcher(ptw)
+	m.normalTxPool.SetPendingTxWatcher(ptw)
+}
+
 func NewTransactionManager(nid int, tsc *TxTimestampChecker, ptp *TransactionPool, ntp *TransactionPool, tim TXIDManager, logger log.Logger) *TransactionManager {
 	txm := &TransactionManager{
 		nid:          nid,
@@ -74,7 +74,11 @@ type TransactionPool struct {
 	txm     TxWaiterManager
 	monitor Monitor
 	pcm     PoolCapacityMonitor
+	ptw     PendingTxWatcher
 	log     log.Logger
+
+	dropped int64
+	expired int64
 }
 
 func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager, m Monitor, log log.Logger) *TransactionPool {
@@ -86,6 +90,7 @@ func NewTransactionPool(group module.TransactionGroup, size int, tim TXIDManager
 		txm:     dummyTxWaiterManager{},
 		monitor: m,
 		pcm:     dummyPoolCapacityMonitor{},
+		ptw:     dummyPendingTxWatcher{},
 		log:     log,
 	}
 	return pool
@@ -111,7 +116,9 @@ func (tp *TransactionPool) DropOldTXs(bts int64) {
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), iter.err)
 			drops = append(drops, TxDrop{tx.ID(), iter.err})
+			tp.countDropInLock(iter.err)
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
+			tp.ptw.OnPendingTx(module.PendingTxDropped, tx, iter.err)
 		}
 		iter = next
 	}
@@ -233,6 +240,7 @@ func (tp *TransactionPool) Add(tx transaction.Transaction, direct bool) error {
 	if err == nil {
 		tp.monitor.OnAddTx(len(tx.Bytes()), direct)
 		tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+		tp.ptw.OnPendingTx(module.PendingTxAdded, tx, nil)
 	}
 	return err
 }
@@ -263,6 +271,7 @@ func (tp *TransactionPool) RemoveList(txs module.TransactionList) {
 				count += 1
 			}
 			tp.monitor.OnRemoveTx(len(t.Bytes()), ts != 0)
+			tp.ptw.OnPendingTx(module.PendingTxRemoved, t, nil)
 		}
 	}
 
@@ -307,6 +316,13 @@ func (tp *TransactionPool) SetPoolCapacityMonitor(pcm PoolCapacityMonitor) {
 	go tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
 }
 
+func (tp *TransactionPool) SetPendingTxWatcher(ptw PendingTxWatcher) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	tp.ptw = ptw
+}
+
 func (tp *TransactionPool) GetBloom() *TxBloom {
 	tp.mutex.Lock()
 	defer tp.mutex.Unlock()
@@ -314,10 +330,21 @@ func (tp *TransactionPool) GetBloom() *TxBloom {
 	return tp.list.GetBloom()
 }
 
+func (tp *TransactionPool) countDropInLock(reason error) {
+	tp.dropped += 1
+	if ExpiredTransactionError.Equals(reason) {
+		tp.expired += 1
+	}
+}
+
 func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 	lock := common.LockForAutoCall(&tp.mutex)
 	defer lock.Unlock()
 
+	tp.dropTransactionsInLock(lock, txs)
+}
+
+func (tp *TransactionPool) dropTransactionsInLock(lock *common.AutoCallLocker, txs []*txElement) {
 	var drops []TxDrop
 	for _, e := range txs {
 		if tp.list.Remove(e) {
@@ -328,7 +355,9 @@ func (tp *TransactionPool) dropTransactions(txs []*txElement) {
 			}
 			tp.log.Debugf("DROP TX: id=0x%x reason=%v", tx.ID(), e.err)
 			drops = append(drops, TxDrop{tx.ID(), e.err})
+			tp.countDropInLock(e.err)
 			tp.monitor.OnDropTx(len(tx.Bytes()), direct)
+			tp.ptw.OnPendingTx(module.PendingTxDropped, tx, e.err)
 		}
 	}
 	lock.CallAfterUnlock(func() {
@@ -366,3 +395,73 @@ func (tp *TransactionPool) FilterTransactions(bloom *TxBloom, max int) []module.
 	}
 	return txs
 }
+
+// Transactions returns transactions in the pool. If from is not nil, it
+// returns only transactions sent by from in the order of their timestamps.
+// It skips first skip transactions and returns at most limit transactions
+// along with the number of all matching transactions.
+func (tp *TransactionPool) Transactions(from module.Address, skip, limit int) ([]module.Transaction, int) {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	txs := make([]module.Transaction, 0)
+	if from == nil {
+		e := tp.list.Front()
+		for i := 0; e != nil && i < skip; i++ {
+			e = e.Next()
+		}
+		for ; e != nil && len(txs) < limit; e = e.Next() {
+			txs = append(txs, e.Value())
+		}
+		return txs, tp.list.Len()
+	}
+
+	var elements []*txElement
+	for e := tp.list.LastOf(from); e != nil; e = e.SrcPrev() {
+		elements = append(elements, e)
+	}
+	total := len(elements)
+	for i := total - 1 - skip; i >= 0 && len(txs) < limit; i-- {
+		txs = append(txs, elements[i].Value())
+	}
+	return txs, total
+}
+
+func (tp *TransactionPool) Status() *module.TxPoolStatus {
+	tp.mutex.Lock()
+	defer tp.mutex.Unlock()
+
+	status := &module.TxPoolStatus{
+		Group:   tp.group,
+		Size:    tp.size,
+		Used:    tp.list.Len(),
+		Dropped: tp.dropped,
+		Expired: tp.expired,
+	}
+	for e := tp.list.Front(); e != nil; e = e.Next() {
+		ts := e.Value().Timestamp()
+		if status.OldestTimestamp == 0 || ts < status.OldestTimestamp {
+			status.OldestTimestamp = ts
+		}
+	}
+	return status
+}
+
+// Evict drops the transaction from the pool with the reason. The
+// transaction is regarded as dropped, so it's not accepted again for
+// a while. It returns false if the pool doesn't have the transaction.
+func (tp *TransactionPool) Evict(id []byte, reason error) bool {
+	lock := common.LockForAutoCall(&tp.mutex)
+	defer lock.Unlock()
+
+	e := tp.list.Get(id)
+	if e == nil {
+		return false
+	}
+	tx := e.Value()
+	e.err = reason
+	tp.tim.AddDroppedTX(tx.ID(), tx.Timestamp())
+	tp.dropTransactionsInLock(lock, []*txElement{e})
+	tp.pcm.OnPoolCapacityUpdated(tp.group, tp.size, tp.list.Len())
+	return true
+}
@@ -49,3 +49,84 @@ func TestTransactionPool_Add(t *testing.T) {
 		t.Error("Fail to add transaction with valid network ID")
 	}
 }
+
+type dropRecorder struct {
+	drops []TxDrop
+}
+
+func (r *dropRecorder) OnTxDrops(drops []TxDrop) {
+	r.drops = append(r.drops, drops...)
+}
+
+func TestTransactionPool_Inspect(t *testing.T) {
+	dbase := db.NewMapDB()
+	tsc := NewTimestampChecker()
+	logger := log.New()
+	lm, err := txlocator.NewManager(dbase, logger)
+	assert.NoError(t, err)
+	tic := NewTxIDCache(ConfigDroppedTxSlotDuration, 100, logger)
+	tim, _ := NewTXIDManager(lm, tsc, tic)
+	pool := NewTransactionPool(module.TransactionGroupNormal, 10, tim, &mockMonitor{}, logger)
+	recorder := new(dropRecorder)
+	pool.SetTxManager(recorder)
+
+	addr1 := common.MustNewAddressFromString("hx1111111111111111111111111111111111111111")
+	addr2 := common.MustNewAddressFromString("hx2222222222222222222222222222222222222222")
+	tx1 := newMockTransaction([]byte("tx1"), addr1, 30)
+	tx2 := newMockTransaction([]byte("tx2"), addr2, 20)
+	tx3 := newMockTransaction([]byte("tx3"), addr1, 10)
+	tx4 := newMockTransaction([]byte("tx4"), addr1, 40)
+	for _, tx := range []*mockTransaction{tx1, tx2, tx3, tx4} {
+		assert.NoError(t, pool.Add(tx, true))
+	}
+
+	ids := func(txs []module.Transaction) []string {
+		var res []string
+		for _, tx := range txs {
+			res = append(res, string(tx.ID()))
+		}
+		return res
+	}
+
+	txs, total := pool.Transactions(nil, 0, 10)
+	assert.Equal(t, 4, total)
+	assert.Len(t, txs, 4)
+	txs, total = pool.Transactions(nil, 3, 10)
+	assert.Equal(t, 4, total)
+	assert.Len(t, txs, 1)
+
+	txs, total = pool.Transactions(addr1, 0, 10)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{"tx3", "tx1", "tx4"}, ids(txs))
+	txs, total = pool.Transactions(addr1, 1, 1)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{"tx1"}, ids(txs))
+	txs, total = pool.Transactions(addr2, 1, 1)
+	assert.Equal(t, 1, total)
+	assert.Len(t, txs, 0)
+
+	status := pool.Status()
+	assert.Equal(t, module.TransactionGroupNormal, status.Group)
+	assert.Equal(t, 10, status.Size)
+	assert.Equal(t, 4, status.Used)
+	assert.EqualValues(t, 10, status.OldestTimestamp)
+	assert.EqualValues(t, 0, status.Dropped)
+
+	assert.False(t, pool.Evict([]byte("tx5"), ErrEvictedTransaction))
+	assert.True(t, pool.Evict([]byte("tx3"), ErrEvictedTransaction))
+	assert.False(t, pool.HasTx([]byte("tx3")))
+	assert.Len(t, recorder.drops, 1)
+	assert.Equal(t, []byte("tx3"), recorder.drops[0].ID)
+	assert.True(t, EvictedTransactionError.Equals(recorder.drops[0].Err))
+
+	// evicted one can't be added again
+	err = tim.CheckTXForAdd(tx3)
+	assert.Error(t, err)
+
+	pool.DropOldTXs(25)
+	status = pool.Status()
+	assert.Equal(t, 2, status.Used)
+	assert.EqualValues(t, 30, status.OldestTimestamp)
+	assert.EqualValues(t, 2, status.Dropped)
+	assert.EqualValues(t, 1, status.Expired)
+}
@@ -150,3 +150,25 @@ func BTPDigestHashFromResult(result []byte) ([]byte, error) {
 	}
 	return r.BTPData, nil
 }
+
+func StateHashFromResult(result []byte) ([]byte, error) {
+	r, err := newTransitionResultFromBytes(result)
+	if err != nil {
+		return nil, err
+	}
+	return r.StateHash, nil
+}
+
+// DiffWorldStatesOfResults returns changed accounts from the world state of
+// result1 to the world state of result2. See state.DiffWorldStates for limit.
+func DiffWorldStatesOfResults(dbase db.Database, result1, result2 []byte, withStorage bool, limit int) ([]*state.AccountDiff, error) {
+	h1, err := StateHashFromResult(result1)
+	if err != nil {
+		return nil, err
+	}
+	h2, err := StateHashFromResult(result2)
+	if err != nil {
+		return nil, err
+	}
+	return state.DiffWorldStates(dbase, h1, h2, withStorage, limit)
+}
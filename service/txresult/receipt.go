@@ -641,6 +641,16 @@ func (r *receipt) AddLog(addr module.Address, indexed, data [][]byte) {
 	r.data.LogsBloom.AddLog(&log.eventLogData.Addr, log.eventLogData.Indexed)
 }
 
+// EventLogToJSON returns JSON object of the event log in the same form
+// as the one in the receipt.
+func EventLogToJSON(addr module.Address, indexed, data [][]byte) interface{} {
+	log := new(eventLog)
+	log.eventLogData.Addr.Set(addr)
+	log.eventLogData.Indexed = indexed
+	log.eventLogData.Data = data
+	return log.ToJSON(module.JSONVersionLast)
+}
+
 func (r *receipt) AddBTPMessages(messages list.List) {
 	if r.btpMsgs == nil {
 		r.btpMsgs = list.New()
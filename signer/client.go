@@ -0,0 +1,183 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signer
+
+import (
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/module"
+)
+
+var (
+	_ module.ConsensusSigner = (*remoteWallet)(nil)
+	_ module.ContentSigner   = (*remoteWallet)(nil)
+)
+
+// remoteWallet is a wallet using the key served by a signer.
+type remoteWallet struct {
+	cfg *clientConfig
+
+	lock   sync.Mutex
+	conn   net.Conn
+	pubKey []byte
+	addr   module.Address
+}
+
+// NewWallet returns the wallet using the signer at the endpoint.
+// An endpoint is "unix://<path>" for a Unix socket, or "<host>:<port>" for
+// TCP with mutual TLS. Options are OptionAuthKey (file of authentication
+// key, required for Unix sockets), OptionTLSCert, OptionTLSKey, OptionTLSCA
+// (files for mutual TLS, required for TCP) and OptionTimeout (timeout for
+// each request).
+func NewWallet(ep string, opts map[string]string) (module.Wallet, error) {
+	cfg, err := newClientConfig(ep, opts)
+	if err != nil {
+		return nil, err
+	}
+	w := &remoteWallet{cfg: cfg}
+	pk, err := w.call(&request{Method: methodPublicKey})
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := crypto.ParsePublicKey(pk)
+	if err != nil {
+		return nil, errors.InvalidStateError.Wrap(err, "InvalidPublicKey")
+	}
+	w.pubKey = pubKey.SerializeCompressed()
+	w.addr = common.NewAccountAddressFromPublicKey(pubKey)
+	return w, nil
+}
+
+func (w *remoteWallet) dial() (net.Conn, error) {
+	cfg := w.cfg
+	dialer := &net.Dialer{Timeout: cfg.timeout}
+	var conn net.Conn
+	var err error
+	if cfg.tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, cfg.network, cfg.address, cfg.tlsConfig)
+	} else {
+		conn, err = dialer.Dial(cfg.network, cfg.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := w.authenticate(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (w *remoteWallet) authenticate(conn net.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(w.cfg.timeout)); err != nil {
+		return err
+	}
+	var h hello
+	if err := readFrame(conn, &h); err != nil {
+		return err
+	}
+	if h.Version != ProtocolVersion {
+		return errors.UnsupportedError.Errorf(
+			"UnsupportedVersion(version=%d)", h.Version)
+	}
+	req := &request{Method: methodAuth}
+	if len(w.cfg.authKey) > 0 {
+		req.Data = authMAC(w.cfg.authKey, h.Nonce)
+	}
+	if err := writeFrame(conn, req); err != nil {
+		return err
+	}
+	var res response
+	if err := readFrame(conn, &res); err != nil {
+		return err
+	}
+	return res.error()
+}
+
+func (w *remoteWallet) roundTrip(req *request) (*response, error) {
+	if w.conn == nil {
+		conn, err := w.dial()
+		if err != nil {
+			return nil, err
+		}
+		w.conn = conn
+	}
+	if err := w.conn.SetDeadline(time.Now().Add(w.cfg.timeout)); err != nil {
+		return nil, err
+	}
+	var res response
+	err := writeFrame(w.conn, req)
+	if err == nil {
+		err = readFrame(w.conn, &res)
+	}
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return nil, err
+	}
+	return &res, nil
+}
+
+// call sends the request and returns the data of the response. It retries
+// once with a new connection on failure of the connection. It's safe as
+// the signer returns the same signature for the same consensus message.
+func (w *remoteWallet) call(req *request) ([]byte, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	res, err := w.roundTrip(req)
+	if err != nil {
+		if res, err = w.roundTrip(req); err != nil {
+			return nil, errors.Wrap(err, "fail to call signer")
+		}
+	}
+	if err := res.error(); err != nil {
+		return nil, err
+	}
+	return res.Data, nil
+}
+
+func (w *remoteWallet) Address() module.Address {
+	return w.addr
+}
+
+func (w *remoteWallet) PublicKey() []byte {
+	return w.pubKey
+}
+
+func (w *remoteWallet) Sign(data []byte) ([]byte, error) {
+	return w.call(&request{Method: methodSign, Data: data})
+}
+
+func (w *remoteWallet) SignContent(content []byte) ([]byte, error) {
+	return w.call(&request{Method: methodSignContent, Data: content})
+}
+
+func (w *remoteWallet) SignConsensusMessage(nid int, t string, msg []byte) ([]byte, error) {
+	return w.call(&request{
+		Method: methodSignConsensus,
+		Type:   t,
+		NID:    int32(nid),
+		Data:   msg,
+	})
+}
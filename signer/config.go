@@ -0,0 +1,129 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signer
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"github.com/icon-project/goloop/common/errors"
+)
+
+// Options for the remote wallet
+const (
+	OptionAuthKey = "auth_key"
+	OptionTLSCert = "tls_cert"
+	OptionTLSKey  = "tls_key"
+	OptionTLSCA   = "tls_ca"
+	OptionTimeout = "timeout"
+)
+
+const minAuthKeySize = 16
+
+// ReadAuthKey reads the authentication key in the file. Leading and trailing
+// white spaces are ignored.
+func ReadAuthKey(path string) ([]byte, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read auth key file=%s", path)
+	}
+	key := bytes.TrimSpace(bs)
+	if len(key) < minAuthKeySize {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TooShortAuthKey(file=%s,min=%d)", path, minAuthKeySize)
+	}
+	return key, nil
+}
+
+// LoadTLSConfig returns TLS configuration for mutual TLS with the
+// certificate, the key and the certificate of CA verifying the peer.
+func LoadTLSConfig(cert, key, ca string, server bool) (*tls.Config, error) {
+	if cert == "" || key == "" || ca == "" {
+		return nil, errors.IllegalArgumentError.New(
+			"TLS certificate, key and CA certificate are required")
+	}
+	kp, err := tls.LoadX509KeyPair(cert, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to load key pair cert=%s key=%s", cert, key)
+	}
+	caPEM, err := os.ReadFile(ca)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to read CA certificate file=%s", ca)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.IllegalArgumentError.Errorf("InvalidCACertificate(file=%s)", ca)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{kp},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if server {
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// clientConfig is the configuration of the remote wallet.
+type clientConfig struct {
+	network   string
+	address   string
+	authKey   []byte
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+func newClientConfig(ep string, opts map[string]string) (*clientConfig, error) {
+	network, address, err := ParseEndpoint(ep)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &clientConfig{
+		network: network,
+		address: address,
+		timeout: DefaultTimeout,
+	}
+	if p := opts[OptionAuthKey]; p != "" {
+		if cfg.authKey, err = ReadAuthKey(p); err != nil {
+			return nil, err
+		}
+	}
+	if network == "unix" {
+		if cfg.authKey == nil {
+			return nil, errors.IllegalArgumentError.Errorf(
+				"auth key is required for unix socket endpoint=%s", ep)
+		}
+	} else {
+		cfg.tlsConfig, err = LoadTLSConfig(
+			opts[OptionTLSCert], opts[OptionTLSKey], opts[OptionTLSCA], false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if s := opts[OptionTimeout]; s != "" {
+		if cfg.timeout, err = time.ParseDuration(s); err != nil || cfg.timeout <= 0 {
+			return nil, errors.IllegalArgumentError.Errorf("InvalidTimeout(timeout=%s)", s)
+		}
+	}
+	return cfg, nil
+}
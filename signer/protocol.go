@@ -0,0 +1,148 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package signer implements a remote signer for validator keys, and a wallet
+// using it.
+//
+// A signer serves one key through a Unix socket or a TCP connection with
+// mutual TLS. A connection starts with a challenge from the signer, and the
+// client authenticates itself with HMAC-SHA256 of the challenge by the shared
+// authentication key. Then the client sends requests, and the signer sends
+// a response for each request.
+//
+// The signer keeps the position (height, round and step) of the last
+// consensus message it signed for each network, and it refuses to sign
+// a consensus message at or before the position unless it's the same message.
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/icon-project/goloop/common/codec"
+	"github.com/icon-project/goloop/common/errors"
+)
+
+const (
+	ProtocolVersion = 3
+
+	nonceSize        = 32
+	maxFrameSize     = 1024 * 1024
+	DefaultTimeout   = 5 * time.Second
+	unixSchemePrefix = "unix://"
+)
+
+type method uint8
+
+const (
+	methodAuth method = iota
+	methodPublicKey
+	methodSign
+	methodSignConsensus
+	methodSignContent
+)
+
+// hello is the challenge sent by the signer on a new connection.
+type hello struct {
+	Version uint16
+	Nonce   []byte
+}
+
+type request struct {
+	Method method
+	Type   string
+	NID    int32
+	Data   []byte
+}
+
+type response struct {
+	Code    errors.Code
+	Message string
+	Data    []byte
+}
+
+func (r *response) error() error {
+	if r.Code == errors.Success {
+		return nil
+	}
+	return errors.Errorc(r.Code, r.Message)
+}
+
+func newErrorResponse(err error) *response {
+	code := errors.CodeOf(err)
+	if code == errors.Success {
+		code = errors.UnknownError
+	}
+	return &response{Code: code, Message: err.Error()}
+}
+
+func writeFrame(w io.Writer, v interface{}) error {
+	bs, err := codec.BC.MarshalToBytes(v)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, 4+len(bs))
+	binary.BigEndian.PutUint32(frame, uint32(len(bs)))
+	copy(frame[4:], bs)
+	_, err = w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader, v interface{}) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxFrameSize {
+		return errors.IllegalArgumentError.Errorf("TooLargeFrame(size=%d)", size)
+	}
+	bs := make([]byte, size)
+	if _, err := io.ReadFull(r, bs); err != nil {
+		return err
+	}
+	_, err := codec.BC.UnmarshalFromBytes(bs, v)
+	return err
+}
+
+func authMAC(key, nonce []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("goloop-signer"))
+	mac.Write(nonce)
+	return mac.Sum(nil)
+}
+
+// ParseEndpoint returns the network and the address of the endpoint.
+// An endpoint is "unix://<path>" for a Unix socket, or "<host>:<port>" for
+// a TCP connection.
+func ParseEndpoint(ep string) (string, string, error) {
+	if strings.HasPrefix(ep, unixSchemePrefix) {
+		path := strings.TrimPrefix(ep, unixSchemePrefix)
+		if path == "" {
+			return "", "", errors.IllegalArgumentError.Errorf("InvalidEndpoint(ep=%s)", ep)
+		}
+		return "unix", path, nil
+	}
+	if _, _, err := net.SplitHostPort(ep); err != nil {
+		return "", "", errors.IllegalArgumentError.Wrapf(err, "InvalidEndpoint(ep=%s)", ep)
+	}
+	return "tcp", ep, nil
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/tls"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+)
+
+type ServerConfig struct {
+	// AuthKey is the key for authentication of clients. It's required for
+	// Unix sockets.
+	AuthKey []byte
+
+	// StatePath is the file storing the last signed consensus message of
+	// each network.
+	// The state is kept only in memory if it's empty.
+	StatePath string
+
+	// Timeout is the timeout for authentication of a connection.
+	Timeout time.Duration
+
+	// AllowSign allows signing of any hash for keys not used for consensus.
+	// Consensus messages are refused with it, because a signature for
+	// the hash of a conflicting message can be made with it.
+	AllowSign bool
+}
+
+// Server serves the key of the wallet to remote wallets.
+type Server struct {
+	wallet    module.Wallet
+	authKey   []byte
+	timeout   time.Duration
+	allowSign bool
+	protector *protector
+	log       log.Logger
+
+	lock      sync.Mutex
+	listeners []net.Listener
+	conns     map[net.Conn]struct{}
+}
+
+func NewServer(w module.Wallet, cfg *ServerConfig, logger log.Logger) (*Server, error) {
+	p, err := newProtector(cfg.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Server{
+		wallet:    w,
+		authKey:   cfg.AuthKey,
+		timeout:   timeout,
+		allowSign: cfg.AllowSign,
+		protector: p,
+		log:       logger,
+		conns:     make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Listen listens on the endpoint. TLS configuration is required for TCP
+// endpoints.
+func Listen(ep string, tlsConfig *tls.Config) (net.Listener, error) {
+	network, address, err := ParseEndpoint(ep)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		l, err := net.Listen(network, address)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(address, 0600); err != nil {
+			l.Close()
+			return nil, err
+		}
+		return l, nil
+	}
+	if tlsConfig == nil {
+		return nil, errors.IllegalArgumentError.Errorf(
+			"TLS is required for TCP endpoint=%s", ep)
+	}
+	return tls.Listen(network, address, tlsConfig)
+}
+
+// Serve accepts connections on the listener until the listener is closed.
+func (s *Server) Serve(l net.Listener) error {
+	if _, ok := l.(*net.UnixListener); ok && len(s.authKey) == 0 {
+		return errors.IllegalArgumentError.New("auth key is required for unix socket")
+	}
+	s.lock.Lock()
+	s.listeners = append(s.listeners, l)
+	s.lock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.handleConn(conn)
+	}
+}
+
+// Close closes the listeners and the connections, and releases the state.
+func (s *Server) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, l := range s.listeners {
+		l.Close()
+	}
+	s.listeners = nil
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.protector.close()
+	return nil
+}
+
+func (s *Server) addConn(conn net.Conn) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.listeners == nil {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) removeConn(conn net.Conn) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.conns, conn)
+}
+
+func (s *Server) authenticate(conn net.Conn) error {
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		return err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	if err := writeFrame(conn, &hello{Version: ProtocolVersion, Nonce: nonce}); err != nil {
+		return err
+	}
+	var req request
+	if err := readFrame(conn, &req); err != nil {
+		return err
+	}
+	if req.Method != methodAuth {
+		return errors.IllegalArgumentError.Errorf("NotAuthenticated(method=%d)", req.Method)
+	}
+	if len(s.authKey) > 0 && !hmac.Equal(req.Data, authMAC(s.authKey, nonce)) {
+		err := errors.IllegalArgumentError.New("AuthenticationFailure")
+		_ = writeFrame(conn, newErrorResponse(err))
+		return err
+	}
+	if err := writeFrame(conn, &response{}); err != nil {
+		return err
+	}
+	return conn.SetDeadline(time.Time{})
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+	if !s.addConn(conn) {
+		return
+	}
+	defer s.removeConn(conn)
+
+	if err := s.authenticate(conn); err != nil {
+		s.log.Warnf("fail to authenticate peer=%s err=%v", conn.RemoteAddr(), err)
+		return
+	}
+	s.log.Infof("connected peer=%s", conn.RemoteAddr())
+	for {
+		var req request
+		if err := readFrame(conn, &req); err != nil {
+			if err != io.EOF {
+				s.log.Warnf("fail to read request peer=%s err=%v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		res := s.handle(&req)
+		if err := writeFrame(conn, res); err != nil {
+			s.log.Warnf("fail to write response peer=%s err=%v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req *request) *response {
+	switch req.Method {
+	case methodPublicKey:
+		return &response{Data: s.wallet.PublicKey()}
+	case methodSign:
+		if !s.allowSign {
+			return newErrorResponse(errors.UnsupportedError.New("SignNotAllowed"))
+		}
+		if len(req.Data) == 0 || len(req.Data) > crypto.HashLen {
+			return newErrorResponse(errors.IllegalArgumentError.Errorf(
+				"InvalidHash(len=%d)", len(req.Data)))
+		}
+		sig, err := s.wallet.Sign(req.Data)
+		if err != nil {
+			return newErrorResponse(err)
+		}
+		return &response{Data: sig}
+	case methodSignContent:
+		if isConsensusMessage(req.Data) {
+			return newErrorResponse(errors.IllegalArgumentError.New("ConsensusMessageContent"))
+		}
+		sig, err := s.wallet.Sign(crypto.SHA3Sum256(req.Data))
+		if err != nil {
+			return newErrorResponse(err)
+		}
+		return &response{Data: sig}
+	case methodSignConsensus:
+		if s.allowSign {
+			return newErrorResponse(errors.UnsupportedError.New("ConsensusNotAllowed"))
+		}
+		info, err := consensus.SignInfoOf(req.Type, req.Data)
+		if err != nil {
+			return newErrorResponse(err)
+		}
+		sig, err := s.protector.sign(req.NID, info, crypto.SHA3Sum256(req.Data), s.wallet.Sign)
+		if err != nil {
+			s.log.Warnf("fail to sign %s nid=%#x msg=%s err=%v", req.Type, req.NID, info, err)
+			return newErrorResponse(err)
+		}
+		s.log.Debugf("signed %s nid=%#x msg=%s", req.Type, req.NID, info)
+		return &response{Data: sig}
+	default:
+		return newErrorResponse(errors.UnsupportedError.Errorf(
+			"UnknownMethod(method=%d)", req.Method))
+	}
+}
+
+// isConsensusMessage returns whether the content may be accepted as
+// a proposal or a vote.
+func isConsensusMessage(content []byte) bool {
+	for _, t := range []string{module.DSTProposal, module.DSTVote} {
+		if _, err := consensus.SignInfoOf(t, content); err == nil {
+			return true
+		}
+	}
+	return false
+}
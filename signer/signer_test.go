@@ -0,0 +1,353 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/icon-project/goloop/common/crypto"
+	"github.com/icon-project/goloop/common/log"
+	"github.com/icon-project/goloop/common/wallet"
+	"github.com/icon-project/goloop/consensus"
+	"github.com/icon-project/goloop/module"
+)
+
+const (
+	testAuthKey = "0123456789abcdef0123456789abcdef"
+	testNID     = 1
+)
+
+func startServer(t *testing.T, w module.Wallet, ep string, cfg *ServerConfig) *Server {
+	l, err := Listen(ep, nil)
+	assert.NoError(t, err)
+	srv, err := NewServer(w, cfg, log.New())
+	assert.NoError(t, err)
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	p := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(p, data, 0600))
+	return p
+}
+
+type testVerifyContext struct{}
+
+func (testVerifyContext) ValidNID(nid uint32) bool {
+	return true
+}
+
+func (testVerifyContext) NID() int {
+	return 0
+}
+
+func newVote(w module.Wallet, vt consensus.VoteType, height int64, round int32, ts int64) *consensus.VoteMessage {
+	cw := consensus.NewChainWallet(w, testNID)
+	return consensus.NewVoteMessage(cw, vt, height, round, make([]byte, 32), nil, ts, nil, nil, 0)
+}
+
+func TestWallet_Unix(t *testing.T) {
+	dir := t.TempDir()
+	ep := "unix://" + filepath.Join(dir, "signer.sock")
+	state := filepath.Join(dir, "state.json")
+	keyFile := writeFile(t, dir, "auth_key", []byte(testAuthKey+"\n"))
+
+	key := wallet.New()
+	srv := startServer(t, key, ep, &ServerConfig{AuthKey: []byte(testAuthKey), StatePath: state})
+
+	w, err := NewWallet(ep, map[string]string{OptionAuthKey: keyFile})
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), w.Address())
+	assert.Equal(t, key.PublicKey(), w.PublicKey())
+
+	// any hash is refused, since it may be the hash of a consensus message
+	hash := crypto.SHA3Sum256([]byte("data"))
+	_, err = w.Sign(hash)
+	assert.Error(t, err)
+
+	// content is signed unless it's a consensus message
+	cts, ok := w.(module.ContentSigner)
+	assert.True(t, ok)
+	sig, err := cts.SignContent([]byte("data"))
+	assert.NoError(t, err)
+	s, err := crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+	pk, err := s.RecoverPublicKey(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PublicKey(), pk.SerializeCompressed())
+	_, err = cts.SignContent(voteBytes(t, 20, 0, consensus.VoteTypePrecommit, 100))
+	assert.Error(t, err)
+
+	cs, ok := w.(module.ConsensusSigner)
+	assert.True(t, ok)
+
+	// signed messages are verified with the key
+	vm := newVote(w, consensus.VoteTypePrevote, 10, 1, 100)
+	assert.NoError(t, vm.Verify(testVerifyContext{}))
+	vm = newVote(w, consensus.VoteTypePrecommit, 10, 1, 100)
+	assert.NoError(t, vm.Verify(testVerifyContext{}))
+
+	// the same message returns the same signature
+	bs := voteBytes(t, 10, 1, consensus.VoteTypePrecommit, 100)
+	sig1, err := cs.SignConsensusMessage(testNID, module.DSTVote, bs)
+	assert.NoError(t, err)
+	sig2, err := cs.SignConsensusMessage(testNID, module.DSTVote, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, sig1, sig2)
+
+	// conflicting message at the same position
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 10, 1, consensus.VoteTypePrecommit, 101))
+	assert.Error(t, err)
+
+	// messages before the last one
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 10, 1, consensus.VoteTypePrevote, 100))
+	assert.Error(t, err)
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 9, 5, consensus.VoteTypePrecommit, 100))
+	assert.Error(t, err)
+
+	// invalid message
+	_, err = cs.SignConsensusMessage(testNID, module.DSTProposal, []byte{0x01})
+	assert.Error(t, err)
+
+	// the state can't be used by other signers
+	_, err = NewServer(key, &ServerConfig{StatePath: state}, log.New())
+	assert.Error(t, err)
+
+	// the state is kept after restart
+	srv.Close()
+	startServer(t, key, ep, &ServerConfig{AuthKey: []byte(testAuthKey), StatePath: state})
+
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 10, 1, consensus.VoteTypePrevote, 100))
+	assert.Error(t, err)
+	sig3, err := cs.SignConsensusMessage(testNID, module.DSTVote, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, sig1, sig3)
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 10, 2, consensus.VoteTypePrevote, 100))
+	assert.NoError(t, err)
+}
+
+func TestWallet_MultipleChains(t *testing.T) {
+	dir := t.TempDir()
+	ep := "unix://" + filepath.Join(dir, "signer.sock")
+	state := filepath.Join(dir, "state.json")
+	keyFile := writeFile(t, dir, "auth_key", []byte(testAuthKey+"\n"))
+
+	key := wallet.New()
+	srv := startServer(t, key, ep, &ServerConfig{AuthKey: []byte(testAuthKey), StatePath: state})
+
+	w, err := NewWallet(ep, map[string]string{OptionAuthKey: keyFile})
+	assert.NoError(t, err)
+	cs := w.(module.ConsensusSigner)
+
+	// chains at different heights are checked separately
+	_, err = cs.SignConsensusMessage(1, module.DSTVote, voteBytes(t, 100, 0, consensus.VoteTypePrecommit, 100))
+	assert.NoError(t, err)
+	_, err = cs.SignConsensusMessage(2, module.DSTVote, voteBytes(t, 10, 0, consensus.VoteTypePrecommit, 100))
+	assert.NoError(t, err)
+	_, err = cs.SignConsensusMessage(2, module.DSTVote, voteBytes(t, 11, 0, consensus.VoteTypePrevote, 100))
+	assert.NoError(t, err)
+
+	// conflicting messages are refused for each chain
+	_, err = cs.SignConsensusMessage(1, module.DSTVote, voteBytes(t, 100, 0, consensus.VoteTypePrecommit, 101))
+	assert.Error(t, err)
+	_, err = cs.SignConsensusMessage(2, module.DSTVote, voteBytes(t, 10, 0, consensus.VoteTypePrecommit, 100))
+	assert.Error(t, err)
+
+	// the state of each chain is kept after restart
+	srv.Close()
+	startServer(t, key, ep, &ServerConfig{AuthKey: []byte(testAuthKey), StatePath: state})
+
+	_, err = cs.SignConsensusMessage(1, module.DSTVote, voteBytes(t, 99, 0, consensus.VoteTypePrecommit, 100))
+	assert.Error(t, err)
+	_, err = cs.SignConsensusMessage(2, module.DSTVote, voteBytes(t, 11, 0, consensus.VoteTypePrevote, 100))
+	assert.NoError(t, err)
+	_, err = cs.SignConsensusMessage(2, module.DSTVote, voteBytes(t, 11, 0, consensus.VoteTypePrecommit, 100))
+	assert.NoError(t, err)
+	_, err = cs.SignConsensusMessage(3, module.DSTVote, voteBytes(t, 1, 0, consensus.VoteTypePrecommit, 100))
+	assert.NoError(t, err)
+}
+
+func TestWallet_AllowSign(t *testing.T) {
+	dir := t.TempDir()
+	ep := "unix://" + filepath.Join(dir, "signer.sock")
+	keyFile := writeFile(t, dir, "auth_key", []byte(testAuthKey))
+
+	key := wallet.New()
+	startServer(t, key, ep, &ServerConfig{AuthKey: []byte(testAuthKey), AllowSign: true})
+
+	w, err := NewWallet(ep, map[string]string{OptionAuthKey: keyFile})
+	assert.NoError(t, err)
+
+	hash := crypto.SHA3Sum256([]byte("data"))
+	sig, err := w.Sign(hash)
+	assert.NoError(t, err)
+	s, err := crypto.ParseSignature(sig)
+	assert.NoError(t, err)
+	pk, err := s.RecoverPublicKey(hash)
+	assert.NoError(t, err)
+	assert.Equal(t, key.PublicKey(), pk.SerializeCompressed())
+
+	_, err = w.Sign(make([]byte, crypto.HashLen+1))
+	assert.Error(t, err)
+
+	// consensus messages are refused
+	cs := w.(module.ConsensusSigner)
+	_, err = cs.SignConsensusMessage(testNID, module.DSTVote, voteBytes(t, 10, 0, consensus.VoteTypePrevote, 100))
+	assert.Error(t, err)
+}
+
+// voteBytes returns the bytes of the vote passed to ConsensusSigner.
+func voteBytes(t *testing.T, height int64, round int32, vt consensus.VoteType, ts int64) []byte {
+	rec := &recordingSigner{Wallet: wallet.New()}
+	newVote(rec, vt, height, round, ts)
+	assert.NotNil(t, rec.msg)
+	return rec.msg
+}
+
+type recordingSigner struct {
+	module.Wallet
+	msg []byte
+}
+
+func (w *recordingSigner) SignConsensusMessage(nid int, t string, msg []byte) ([]byte, error) {
+	w.msg = msg
+	return w.Sign(crypto.SHA3Sum256(msg))
+}
+
+func TestWallet_AuthFailure(t *testing.T) {
+	dir := t.TempDir()
+	ep := "unix://" + filepath.Join(dir, "signer.sock")
+	startServer(t, wallet.New(), ep, &ServerConfig{AuthKey: []byte(testAuthKey)})
+
+	keyFile := writeFile(t, dir, "auth_key", []byte("fedcba9876543210fedcba9876543210"))
+	_, err := NewWallet(ep, map[string]string{OptionAuthKey: keyFile})
+	assert.Error(t, err)
+
+	// auth key is required for unix sockets
+	_, err = NewWallet(ep, nil)
+	assert.Error(t, err)
+
+	shortKey := writeFile(t, dir, "short_key", []byte("short"))
+	_, err = NewWallet(ep, map[string]string{OptionAuthKey: shortKey})
+	assert.Error(t, err)
+}
+
+func writeCert(t *testing.T, dir, name string, tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+	writeFile(t, dir, name+".crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	kb, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	writeFile(t, dir, name+".key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}))
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func TestWallet_TLS(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	ca, caKey := writeCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	writeCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "signer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+	writeCert(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "node"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+	// a client certificate not signed by the CA
+	writeCert(t, dir, "other", &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "other"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, nil, nil)
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+	tc, err := LoadTLSConfig(path("server.crt"), path("server.key"), path("ca.crt"), true)
+	assert.NoError(t, err)
+
+	_, err = Listen("127.0.0.1:0", nil)
+	assert.Error(t, err)
+	l, err := Listen("127.0.0.1:0", tc)
+	assert.NoError(t, err)
+	key := wallet.New()
+	srv, err := NewServer(key, &ServerConfig{}, log.New())
+	assert.NoError(t, err)
+	go srv.Serve(l)
+	defer srv.Close()
+
+	ep := l.Addr().String()
+	w, err := NewWallet(ep, map[string]string{
+		OptionTLSCert: path("client.crt"),
+		OptionTLSKey:  path("client.key"),
+		OptionTLSCA:   path("ca.crt"),
+		OptionTimeout: "2s",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, key.Address(), w.Address())
+	vm := newVote(w, consensus.VoteTypePrevote, 1, 0, 0)
+	assert.NoError(t, vm.Verify(testVerifyContext{}))
+
+	_, err = NewWallet(ep, map[string]string{
+		OptionTLSCert: path("other.crt"),
+		OptionTLSKey:  path("other.key"),
+		OptionTLSCA:   path("ca.crt"),
+	})
+	assert.Error(t, err)
+
+	// TLS is required for TCP
+	_, err = NewWallet(ep, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2023 ICON Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+
+	"github.com/icon-project/goloop/common"
+	"github.com/icon-project/goloop/common/errors"
+	"github.com/icon-project/goloop/consensus"
+)
+
+// signState is the last consensus message signed by the signer for
+// the network.
+type signState struct {
+	NID       int32              `json:"nid"`
+	Height    int64              `json:"height"`
+	Round     int32              `json:"round"`
+	Step      consensus.SignStep `json:"step"`
+	Hash      common.HexBytes    `json:"hash"`
+	Signature common.HexBytes    `json:"signature"`
+}
+
+func (s *signState) info() *consensus.SignInfo {
+	return &consensus.SignInfo{Height: s.Height, Round: s.Round, Step: s.Step}
+}
+
+// protector protects the key from signing conflicting consensus messages.
+// Vote messages don't include the network ID, so the last signed message is
+// kept for each network, like consensus.SlashingProtection does for each
+// address and network. The last signed messages are stored in the file
+// before the signature is returned, so that they survive restart of the
+// signer. The file is locked while it's used, so that other signers can't
+// use the same state.
+type protector struct {
+	lock     sync.Mutex
+	path     string
+	lockFile *os.File
+	last     map[int32]*signState
+}
+
+func newProtector(path string) (*protector, error) {
+	p := &protector{path: path, last: make(map[int32]*signState)}
+	if path == "" {
+		return p, nil
+	}
+	lf, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fail to open lock file for state=%s", path)
+	}
+	if err := syscall.Flock(int(lf.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lf.Close()
+		return nil, errors.InvalidStateError.Wrapf(err, "StateInUse(file=%s)", path)
+	}
+	p.lockFile = lf
+	bs, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	} else if err != nil {
+		p.close()
+		return nil, errors.Wrapf(err, "fail to read state file=%s", path)
+	}
+	var states []*signState
+	if err := json.Unmarshal(bs, &states); err != nil {
+		p.close()
+		return nil, errors.Wrapf(err, "invalid state file=%s", path)
+	}
+	for _, s := range states {
+		p.last[s.NID] = s
+	}
+	return p, nil
+}
+
+// close releases the lock of the state.
+func (p *protector) close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.lockFile != nil {
+		p.lockFile.Close()
+		p.lockFile = nil
+	}
+}
+
+// store stores the last signed messages with s replacing the one of
+// the network.
+func (p *protector) store(s *signState) error {
+	if p.path == "" {
+		return nil
+	}
+	states := []*signState{s}
+	for nid, last := range p.last {
+		if nid != s.NID {
+			states = append(states, last)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].NID < states[j].NID
+	})
+	bs, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	tmp := p.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(bs); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
+
+// sign signs the hash of the consensus message at the position with
+// signer if it doesn't conflict with the last signed message of the network.
+func (p *protector) sign(
+	nid int32, info *consensus.SignInfo, hash []byte,
+	signer func([]byte) ([]byte, error),
+) ([]byte, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.path != "" && p.lockFile == nil {
+		return nil, errors.InvalidStateError.New("Closed")
+	}
+	if last, ok := p.last[nid]; ok {
+		switch cmp := info.Compare(last.info()); {
+		case cmp < 0:
+			return nil, errors.InvalidStateError.Errorf(
+				"DoubleSignProtection(nid=%#x,msg=%s,last=%s)", nid, info, last.info())
+		case cmp == 0:
+			if bytes.Equal(hash, last.Hash) {
+				return last.Signature, nil
+			}
+			return nil, errors.InvalidStateError.Errorf(
+				"DoubleSignProtection(nid=%#x,msg=%s,hash=%#x,last=%#x)",
+				nid, info, hash, []byte(last.Hash))
+		}
+	}
+	sig, err := signer(hash)
+	if err != nil {
+		return nil, err
+	}
+	s := &signState{
+		NID:       nid,
+		Height:    info.Height,
+		Round:     info.Round,
+		Step:      info.Step,
+		Hash:      hash,
+		Signature: sig,
+	}
+	if err := p.store(s); err != nil {
+		return nil, errors.Wrap(err, "fail to store sign state")
+	}
+	p.last[nid] = s
+	return sig, nil
+}
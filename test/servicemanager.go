@@ -209,6 +209,19 @@ func (sm *ServiceManager) GetRoundLimit(result []byte, vl int) int64 {
 	return limit
 }
 
+func (sm *ServiceManager) GetConsensusTimeouts(result []byte) *module.ConsensusTimeouts {
+	ws, err := service.NewWorldSnapshot(sm.dbase, sm.plt, result, nil)
+	if err != nil {
+		return nil
+	}
+	ass := ws.GetAccountSnapshot(state.SystemID)
+	as := scoredb.NewStateStoreWith(ass)
+	if as == nil {
+		return nil
+	}
+	return contract.ConsensusTimeoutsFromState(as)
+}
+
 func (sm *ServiceManager) GetMinimizeBlockGen(result []byte) bool {
 	ws, err := service.NewWorldSnapshot(sm.dbase, sm.plt, result, nil)
 	if err != nil {